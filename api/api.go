@@ -3,6 +3,7 @@ package api
 import (
 	internalError "go-vk-sdk/errors"
 	"go-vk-sdk/transport"
+	"strings"
 )
 
 type API struct {
@@ -11,43 +12,68 @@ type API struct {
 	AuthEndpoint   string           `json:"auth_endpoint"`
 	Language       string           `json:"language"`
 	Client         transport.Client `json:"client"`
+
+	// RateLimiter limits method calls per access token, nil disables the limits.
+	// NewAPI leaves it nil, set NewTokenBucketRateLimiter() to keep the calls within the limits of VK.
+	// The limit of a call is chosen by the actor type of the request
+	RateLimiter         RateLimiter `json:"-"`
+	UserRequestLimit    int         `json:"user_request_limit"`    // requests per second with a user access token
+	GroupRequestLimit   int         `json:"group_request_limit"`   // requests per second with a community access token
+	ServiceRequestLimit int         `json:"service_request_limit"` // requests per second with a service access token
+
+	// CaptchaHandler solves captchas of the method calls and direct auth, nil returns the captcha error to the caller
+	CaptchaHandler  CaptchaHandler `json:"-"`
+	CaptchaAttempts int            `json:"captcha_attempts"` // max number of the repeated calls with solved captchas
 }
 
 func NewAPI() *API {
 	api := &API{
-		Version:        Version,
-		MethodEndpoint: MethodEndpoint,
-		AuthEndpoint:   AuthEndpoint, // Legacy OAuth or VKID
-		Language:       LanguageDefault,
-		Client:         transport.NewHTTPClient(),
+		Version:             Version,
+		MethodEndpoint:      MethodEndpoint,
+		AuthEndpoint:        AuthEndpoint, // Legacy OAuth or VKID
+		Language:            LanguageDefault,
+		Client:              transport.NewHTTPClient(),
+		UserRequestLimit:    RequestLimitUserToken,
+		GroupRequestLimit:   RequestLimitGroupToken,
+		ServiceRequestLimit: RequestLimitServiceToken,
+		CaptchaAttempts:     CaptchaAttempts,
 	}
 
 	return api
 }
 
+// SetHostMethodEndpoint host may be a bare host (api.vk.ru), which is used with https, or a base URL with
+// an explicit scheme, for example http://127.0.0.1:8080 of a test server
 func (a *API) SetHostMethodEndpoint(host string) error {
 	if host == "" {
 		return internalError.Error("API.SetHostMethodEndpoint()", "empty host value "+host)
 	}
 
-	if host[len(host)-1] == '/' {
-		a.MethodEndpoint = "https://" + host + "method"
-	} else {
-		a.MethodEndpoint = "https://" + host + "/method"
-	}
+	a.MethodEndpoint = endpoint(host) + "method/"
 
 	return nil
 }
 
+// SetHostAuthEndpoint host may be a bare host (oauth.vk.ru), which is used with https, or a base URL with an explicit scheme
 func (a *API) SetHostAuthEndpoint(host string) error {
 	if host == "" {
 		return internalError.Error("API.SetHostAuthEndpoint()", "empty host value "+host)
 	}
 
-	a.MethodEndpoint = "https://" + host
-	if a.MethodEndpoint[len(a.MethodEndpoint)-1] != '/' {
-		a.MethodEndpoint += "/"
-	}
+	a.AuthEndpoint = endpoint(host)
 
 	return nil
 }
+
+// endpoint returns the base URL of the host with a scheme and a trailing slash
+func endpoint(host string) string {
+	if !strings.HasPrefix(host, "http://") && !strings.HasPrefix(host, "https://") {
+		host = "https://" + host
+	}
+
+	if host[len(host)-1] != '/' {
+		host += "/"
+	}
+
+	return host
+}
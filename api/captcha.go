@@ -0,0 +1,20 @@
+package api
+
+import "context"
+
+// CaptchaHandler Solver of the captchas required by VK.
+//
+//	SolveCaptcha receives captcha_sid and the URL of the captcha image of the error 14 or the need_captcha OAuth error
+//	and returns the text of the image. The request is repeated with the solved captcha up to API.CaptchaAttempts times.
+//	It must respect the context and be safe for concurrent use.
+//	Doc: https://dev.vk.com/ru/api/captcha-error
+type CaptchaHandler interface {
+	SolveCaptcha(ctx context.Context, sid, img string) (string, error)
+}
+
+// CaptchaHandlerFunc adapter to use an ordinary function as CaptchaHandler
+type CaptchaHandlerFunc func(ctx context.Context, sid, img string) (string, error)
+
+func (f CaptchaHandlerFunc) SolveCaptcha(ctx context.Context, sid, img string) (string, error) {
+	return f(ctx, sid, img)
+}
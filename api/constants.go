@@ -1,5 +1,7 @@
 package api
 
+import "time"
+
 const (
 	Version                     string = "5.199"
 	HostCom                     string = "api.vk.com"
@@ -14,5 +16,9 @@ const (
 	LanguageDefault             string = "ru"
 	RequestLimitUserToken       int    = 3
 	RequestLimitGroupToken      int    = 20
+	RequestLimitServiceToken    int    = 3
 	RequestLimitExecuteMethod   int    = 25
+	CaptchaAttempts             int    = 3
+
+	RateLimiterSweepInterval = time.Minute // interval of removing the idle buckets of TokenBucketRateLimiter
 )
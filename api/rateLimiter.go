@@ -0,0 +1,159 @@
+package api
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimiter Client-side limiter of the api method calls
+//
+//	Wait blocks until the call with the given access token can be made without exceeding limit requests per second
+//	or until the context is done. It must be safe for concurrent use.
+type RateLimiter interface {
+	Wait(ctx context.Context, token string, limit int) error
+}
+
+// TokenBucketRateLimiter RateLimiter with a separate token bucket for every access token.
+//
+//	Burst is the number of calls which can be made at once, by default 1, so calls are evenly spaced
+//	and never exceed the limit within any second. It can be shared between several API instances.
+//	The buckets which are full again are removed every RateLimiterSweepInterval, so unused tokens are not kept.
+type TokenBucketRateLimiter struct {
+	mtx     sync.Mutex
+	burst   int
+	buckets map[string]*tokenBucket
+	sweepAt time.Time
+}
+
+func NewTokenBucketRateLimiter() *TokenBucketRateLimiter {
+	return &TokenBucketRateLimiter{
+		burst:   1,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// SetBurst burst > 0 and <= limit of the token, it is applied to the buckets created after the call
+func (l *TokenBucketRateLimiter) SetBurst(burst int) {
+	if burst > 0 {
+		l.mtx.Lock()
+		l.burst = burst
+		l.mtx.Unlock()
+	}
+}
+
+func (l *TokenBucketRateLimiter) Wait(ctx context.Context, token string, limit int) error {
+	if token == "" || limit <= 0 {
+		return nil
+	}
+
+	bucket, delay := l.reserve(token, limit, time.Now())
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		bucket.cancel()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Reset removes all buckets, for example after the tokens were revoked
+func (l *TokenBucketRateLimiter) Reset() {
+	l.mtx.Lock()
+	clear(l.buckets)
+	l.mtx.Unlock()
+}
+
+// reserve takes a token of the bucket and returns the time to wait until it is available.
+// The token is taken under the lock of the limiter, so the bucket cannot be removed as idle in between
+func (l *TokenBucketRateLimiter) reserve(token string, limit int, now time.Time) (*tokenBucket, time.Duration) {
+	key := strconv.Itoa(limit) + ":" + token
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if now.After(l.sweepAt) {
+		l.sweep(now)
+		l.sweepAt = now.Add(RateLimiterSweepInterval)
+	}
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		burst := l.burst
+		if burst > limit {
+			burst = limit
+		}
+
+		bucket = &tokenBucket{
+			rate:   float64(limit),
+			burst:  float64(burst),
+			tokens: float64(burst),
+			last:   now,
+		}
+		l.buckets[key] = bucket
+	}
+
+	return bucket, bucket.reserve(now)
+}
+
+// sweep removes the buckets which are full again, a new bucket of the token starts in the same state.
+// It must be called with the lock held
+func (l *TokenBucketRateLimiter) sweep(now time.Time) {
+	for key, bucket := range l.buckets {
+		if bucket.isFull(now) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+type tokenBucket struct {
+	mtx    sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// reserve takes a token and returns the time to wait until it is available
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens += elapsed * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+	}
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// isFull reports whether the bucket has refilled to its burst by the time now
+func (b *tokenBucket) isFull(now time.Time) bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	return b.tokens+now.Sub(b.last).Seconds()*b.rate >= b.burst
+}
+
+// cancel returns the token of the reservation which was not used
+func (b *tokenBucket) cancel() {
+	b.mtx.Lock()
+	b.tokens++
+	b.mtx.Unlock()
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketIsolation(t *testing.T) {
+	l := NewTokenBucketRateLimiter()
+	now := time.Now()
+
+	if _, delay := l.reserve("first", 2, now); delay != 0 {
+		t.Errorf("the first call waits %v", delay)
+	}
+
+	if _, delay := l.reserve("first", 2, now); delay != 500*time.Millisecond {
+		t.Errorf("the second call of the token waits %v, want 500ms", delay)
+	}
+
+	// the other token and the same token with the other limit have their own buckets
+	if _, delay := l.reserve("second", 2, now); delay != 0 {
+		t.Errorf("the call of the other token waits %v", delay)
+	}
+
+	if _, delay := l.reserve("first", 20, now); delay != 0 {
+		t.Errorf("the call with the other limit waits %v", delay)
+	}
+
+	// the bucket refills with the rate of the limit
+	if _, delay := l.reserve("second", 2, now.Add(500*time.Millisecond)); delay != 0 {
+		t.Errorf("the call after the refill waits %v", delay)
+	}
+}
+
+func TestTokenBucketBurst(t *testing.T) {
+	l := NewTokenBucketRateLimiter()
+	l.SetBurst(3)
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		if _, delay := l.reserve("token", 3, now); delay != 0 {
+			t.Fatalf("call %d of the burst waits %v", i+1, delay)
+		}
+	}
+
+	if _, delay := l.reserve("token", 3, now); delay <= 0 {
+		t.Error("the call after the burst does not wait")
+	}
+
+	// the burst is limited by the limit of the token
+	if _, delay := l.reserve("other", 1, now); delay != 0 {
+		t.Errorf("the first call waits %v", delay)
+	}
+
+	if _, delay := l.reserve("other", 1, now); delay != time.Second {
+		t.Errorf("got delay %v, want the burst limited to 1", delay)
+	}
+}
+
+func TestTokenBucketSweep(t *testing.T) {
+	l := NewTokenBucketRateLimiter()
+	now := time.Now()
+
+	l.reserve("idle", 3, now)
+	l.reserve("busy", 1, now)
+	l.reserve("busy", 1, now)
+	l.reserve("busy", 1, now)
+
+	if len(l.buckets) != 2 {
+		t.Fatalf("got %d buckets, want 2", len(l.buckets))
+	}
+
+	// both buckets are full again after the interval and are removed by the sweep of the next call
+	sweep := now.Add(RateLimiterSweepInterval + time.Second)
+	l.reserve("new", 3, sweep)
+
+	if _, ok := l.buckets["3:idle"]; ok {
+		t.Error("the idle bucket is not removed")
+	}
+
+	if _, ok := l.buckets["1:busy"]; ok {
+		t.Error("the bucket full again is not removed")
+	}
+
+	if len(l.buckets) != 1 {
+		t.Errorf("got %d buckets, want only the new one", len(l.buckets))
+	}
+
+	// the bucket which still has reservations to wait for is kept by the next sweep
+	for i := 0; i < 3; i++ {
+		l.reserve("busy", 1, sweep.Add(RateLimiterSweepInterval-time.Second))
+	}
+
+	l.reserve("new", 3, sweep.Add(RateLimiterSweepInterval+time.Second/2))
+	if _, ok := l.buckets["1:busy"]; !ok {
+		t.Error("the bucket with the pending reservations is removed")
+	}
+
+	if _, ok := l.buckets["3:new"]; !ok {
+		t.Error("the bucket used by the sweeping call is removed")
+	}
+}
+
+func TestTokenBucketWaitCancel(t *testing.T) {
+	l := NewTokenBucketRateLimiter()
+
+	if err := l.Wait(context.Background(), "token", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := l.Wait(ctx, "token", 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want context.DeadlineExceeded", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Wait returned after %v, not when the context was done", elapsed)
+	}
+
+	// the canceled reservation is returned to the bucket, the next call waits only for the first one
+	if _, delay := l.reserve("token", 1, time.Now()); delay > time.Second {
+		t.Errorf("the next call waits %v, the canceled reservation is kept", delay)
+	}
+}
+
+func TestTokenBucketWithoutLimit(t *testing.T) {
+	l := NewTokenBucketRateLimiter()
+
+	for i := 0; i < 10; i++ {
+		if err := l.Wait(context.Background(), "", 1); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := l.Wait(context.Background(), "token", 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(l.buckets) != 0 {
+		t.Errorf("got %d buckets for the calls without the limit", len(l.buckets))
+	}
+}
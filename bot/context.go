@@ -0,0 +1,101 @@
+package bot
+
+import (
+	"context"
+	"go-vk-sdk/actor"
+	"go-vk-sdk/api"
+	"go-vk-sdk/constants"
+	"go-vk-sdk/objects"
+	"go-vk-sdk/request"
+	"strings"
+)
+
+// Context Message handled by the router, the reply helpers send the messages to the peer of the message
+type Context struct {
+	context.Context
+
+	API        *api.API
+	Actor      actor.Actor
+	Message    *objects.Message
+	ClientInfo objects.ClientInfo
+
+	Text    string                      // text of the message without the mention of the bot
+	Payload objects.MessagesBasePayload // payload of the pressed button
+	Command string                      // matched command, prefix or payload command
+	Args    []string                    // arguments after the command, the quoted arguments may contain spaces
+	Match   []string                    // submatches of the regexp
+
+	window *request.RandomIDWindow
+}
+
+// PeerID returns peer_id of the message
+func (c *Context) PeerID() int {
+	return c.Message.PeerID
+}
+
+// FromID returns from_id of the message
+func (c *Context) FromID() int {
+	return c.Message.FromID
+}
+
+// IsChat reports whether the message is sent to the chat
+func (c *Context) IsChat() bool {
+	return c.Message.PeerID > constants.PeerChatIDOffset
+}
+
+// IsPrivate reports whether the message is sent to the private dialog with the bot
+func (c *Context) IsPrivate() bool {
+	return c.Message.PeerID > 0 && c.Message.PeerID < constants.PeerChatIDOffset
+}
+
+// Arg returns the argument i, empty if there is no such argument
+func (c *Context) Arg(i int) string {
+	if i < 0 || i >= len(c.Args) {
+		return ""
+	}
+
+	return c.Args[i]
+}
+
+// ArgsString returns the arguments joined by the space
+func (c *Context) ArgsString() string {
+	return strings.Join(c.Args, " ")
+}
+
+// NewReply returns messages.send to the peer of the message, the sent messages are tracked by the window of the router
+func (c *Context) NewReply() *request.MessagesSendRequest {
+	return request.NewMessagesSendRequest(c.API, c.Actor).
+		PeerID(c.Message.PeerID).
+		Track(c.window)
+}
+
+// Reply sends the text to the peer of the message
+func (c *Context) Reply(text string) error {
+	_, err := c.NewReply().Message(text).Exec(c)
+	return err
+}
+
+// ReplyTo sends the text to the peer of the message as the reply to the message
+func (c *Context) ReplyTo(text string) error {
+	reply := c.NewReply().Message(text)
+
+	if c.Message.ID != 0 {
+		reply.ReplyTo(c.Message.ID)
+	} else {
+		// messages of the chats have only conversation_message_id
+		reply.Forward(objects.MessagesForward{
+			PeerID:                 c.Message.PeerID,
+			ConversationMessageIDs: []int{c.Message.ConversationMessageID},
+			IsReply:                true,
+		})
+	}
+
+	_, err := reply.Exec(c)
+	return err
+}
+
+// ReplyKeyboard sends the text with the keyboard to the peer of the message
+func (c *Context) ReplyKeyboard(text string, keyboard *objects.MessagesKeyboard) error {
+	_, err := c.NewReply().Message(text).Keyboard(keyboard).Exec(c)
+	return err
+}
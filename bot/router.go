@@ -0,0 +1,342 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"go-vk-sdk/actor"
+	"go-vk-sdk/api"
+	"go-vk-sdk/callback"
+	"go-vk-sdk/events"
+	"go-vk-sdk/logger"
+	"go-vk-sdk/longPollGroup"
+	"go-vk-sdk/markup"
+	"go-vk-sdk/request"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Doc: https://dev.vk.com/ru/api/bots/development/messages
+
+// HandlerFunc Handler of the message, the error is logged by the router
+type HandlerFunc func(c *Context) error
+
+// Filter Condition of the route, the route is skipped if any filter returns false
+type Filter func(c *Context) bool
+
+// Private passes the messages of the private dialogs with the bot
+func Private(c *Context) bool {
+	return c.IsPrivate()
+}
+
+// Chat passes the messages of the chats
+func Chat(c *Context) bool {
+	return c.IsChat()
+}
+
+// FromIDs passes the messages of the users
+func FromIDs(ids ...int) Filter {
+	return func(c *Context) bool {
+		for _, id := range ids {
+			if c.Message.FromID == id {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+type routeKind int
+
+const (
+	routePayload routeKind = iota
+	routeCommand
+	routePrefix
+	routeRegexp
+)
+
+type route struct {
+	kind    routeKind
+	pattern string
+	re      *regexp.Regexp
+	handler HandlerFunc
+	filters []Filter
+}
+
+// Router Dispatches message_new to the handlers by the command, the prefix, the regexp or the command of the payload.
+//
+//	The routes are checked in the order of the registration, the first matched route handles the message,
+//	the messages without the matched route are handled by the fallback handler.
+//	The mention of the bot at the beginning of the message in the chat is removed before the matching,
+//	so "[club1|@bot] /start" matches the command /start.
+//
+//	router := bot.NewRouter(a, groupActor, groupID)
+//	router.Command("/start", start)
+//	router.Payload("buy", buy, bot.Private)
+//	router.Fallback(help)
+type Router struct {
+	api    *api.API
+	actor  actor.Actor
+	bot    markup.Bot
+	window *request.RandomIDWindow
+
+	routes     []route
+	fallback   HandlerFunc
+	middleware []events.Middleware
+}
+
+// NewRouter groupID is the id of the community of the bot, it is used to remove the mention of the bot
+func NewRouter(a *api.API, actor actor.Actor, groupID int) *Router {
+	return &Router{
+		api:    a,
+		actor:  actor,
+		bot:    markup.Bot{GroupID: groupID},
+		window: request.NewRandomIDWindow(request.RandomIDWindowSize, 0),
+	}
+}
+
+// ScreenName screen name of the community, @{screen_name} at the beginning of the message is removed as the mention
+func (r *Router) ScreenName(screenName string) *Router {
+	r.bot.ScreenName = screenName
+	return r
+}
+
+// Command handles the messages which start with the command, the case is ignored,
+// the words after the command are the arguments
+func (r *Router) Command(command string, handler HandlerFunc, filters ...Filter) *Router {
+	return r.add(route{kind: routeCommand, pattern: command, handler: handler, filters: filters})
+}
+
+// Prefix handles the messages which start with the prefix, the case is ignored,
+// the words after the prefix are the arguments
+func (r *Router) Prefix(prefix string, handler HandlerFunc, filters ...Filter) *Router {
+	return r.add(route{kind: routePrefix, pattern: prefix, handler: handler, filters: filters})
+}
+
+// Regexp handles the messages matched by the regexp, the submatches are in Context.Match
+func (r *Router) Regexp(re *regexp.Regexp, handler HandlerFunc, filters ...Filter) *Router {
+	return r.add(route{kind: routeRegexp, re: re, handler: handler, filters: filters})
+}
+
+// Payload handles the presses of the buttons with the payload {"command": command}
+func (r *Router) Payload(command string, handler HandlerFunc, filters ...Filter) *Router {
+	return r.add(route{kind: routePayload, pattern: command, handler: handler, filters: filters})
+}
+
+// Fallback handles the messages without the matched route
+func (r *Router) Fallback(handler HandlerFunc) *Router {
+	r.fallback = handler
+	return r
+}
+
+// Use adds the middleware of the events which wraps the router in ServeLongPoll, CallbackListener and Handler
+//
+//	router.Use(events.Logging(), events.FilterGroupIDs(groupID))
+func (r *Router) Use(middleware ...events.Middleware) *Router {
+	r.middleware = append(r.middleware, middleware...)
+	return r
+}
+
+// Handler returns the router as the handler of the events wrapped by the middleware, the other events are skipped
+func (r *Router) Handler() events.Handler {
+	return events.Chain(func(ctx context.Context, update *events.Update) error {
+		event, ok := update.Event.(*events.EventMessageNew)
+		if !ok {
+			return nil
+		}
+
+		return r.Handle(ctx, event)
+	}, r.middleware...)
+}
+
+// Window returns the window of random_id of the replies, the echoes of the replies can be recognised by it
+func (r *Router) Window() *request.RandomIDWindow {
+	return r.window
+}
+
+// Handle dispatches the message to the handler, the message without the route and the fallback is ignored
+func (r *Router) Handle(ctx context.Context, event *events.EventMessageNew) error {
+	c := r.newContext(ctx, event)
+
+	for i := range r.routes {
+		if r.match(&r.routes[i], c) {
+			return r.routes[i].handler(c)
+		}
+	}
+
+	if r.fallback != nil {
+		c.Command, c.Args, c.Match = "", ParseArgs(c.Text), nil
+		return r.fallback(c)
+	}
+
+	return nil
+}
+
+// ServeLongPoll handles message_new of the long poll until the context is done or the channel of the updates is closed.
+// The long poll must track message_new and be run separately
+//
+//	lp.TrackEvent(events.EventTypeMessageNew)
+//	go lp.Run(ctx)
+//	router.ServeLongPoll(ctx, lp)
+func (r *Router) ServeLongPoll(ctx context.Context, lp *longPollGroup.LongPoll) error {
+	return lp.Serve(ctx, r.Handler())
+}
+
+// CallbackListener returns the listener of message_new of the callback server.
+// The errors and the panics of the handlers are logged and VK is answered ok, so the message is not sent again
+func (r *Router) CallbackListener() *events.EventListener[*events.EventCallback] {
+	handler := r.Handler()
+
+	return events.NewHandlerListener(func(ctx context.Context, update *events.Update) error {
+		if err := handler.Call(ctx, update); err != nil {
+			logger.Log("Bot.Router.CallbackListener()", "error handling message: "+err.Error())
+		}
+
+		return nil
+	})
+}
+
+// ServeCallback adds the listener of message_new to the callback server
+func (r *Router) ServeCallback(c *callback.Callback) {
+	c.AddEventListener(events.EventTypeMessageNew, r.CallbackListener())
+}
+
+func (r *Router) add(route route) *Router {
+	r.routes = append(r.routes, route)
+	return r
+}
+
+func (r *Router) newContext(ctx context.Context, event *events.EventMessageNew) *Context {
+	c := &Context{
+		Context:    ctx,
+		API:        r.api,
+		Actor:      r.actor,
+		Message:    &event.Message,
+		ClientInfo: event.ClientInfo,
+		Text:       strings.TrimSpace(event.Message.Text),
+		window:     r.window,
+	}
+
+	if c.IsChat() {
+		c.Text, _ = r.bot.StripMention(c.Text)
+	}
+
+	if event.Message.Payload != "" {
+		// the payload which is not an object has no command
+		_ = json.Unmarshal([]byte(event.Message.Payload), &c.Payload)
+	}
+
+	return c
+}
+
+// match checks the route and fills the command and the arguments of the context
+func (r *Router) match(route *route, c *Context) bool {
+	var args []string
+	var submatches []string
+
+	switch route.kind {
+	case routePayload:
+		if c.Payload.Command == "" || c.Payload.Command != route.pattern {
+			return false
+		}
+		args = ParseArgs(c.Text)
+	case routeCommand:
+		first, rest := splitCommand(c.Text)
+		if !strings.EqualFold(first, route.pattern) {
+			return false
+		}
+		args = ParseArgs(rest)
+	case routePrefix:
+		rest, ok := cutPrefixFold(c.Text, route.pattern)
+		if !ok {
+			return false
+		}
+		args = ParseArgs(rest)
+	case routeRegexp:
+		submatches = route.re.FindStringSubmatch(c.Text)
+		if submatches == nil {
+			return false
+		}
+	}
+
+	c.Command, c.Args, c.Match = route.pattern, args, submatches
+
+	for _, filter := range route.filters {
+		if !filter(c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// splitCommand returns the first word of the text and the rest of the text
+func splitCommand(text string) (string, string) {
+	i := strings.IndexFunc(text, unicode.IsSpace)
+	if i < 0 {
+		return text, ""
+	}
+
+	return text[:i], text[i:]
+}
+
+// cutPrefixFold returns the text after the prefix, the runes are compared ignoring the case,
+// the folded runes may have the different length in bytes, so the text and the prefix are walked separately
+func cutPrefixFold(text, prefix string) (string, bool) {
+	for prefix != "" {
+		if text == "" {
+			return "", false
+		}
+
+		r, n := utf8.DecodeRuneInString(text)
+		p, m := utf8.DecodeRuneInString(prefix)
+		if r != p && !strings.EqualFold(text[:n], prefix[:m]) {
+			return "", false
+		}
+
+		text, prefix = text[n:], prefix[m:]
+	}
+
+	return text, true
+}
+
+// ParseArgs splits the text into the arguments by the spaces,
+// the arguments in the double or single quotes may contain spaces, the backslash escapes the next character
+func ParseArgs(text string) []string {
+	var args []string
+	var arg strings.Builder
+
+	inArg, quote, escaped := false, rune(0), false
+
+	for _, c := range text {
+		switch {
+		case escaped:
+			arg.WriteRune(c)
+			escaped = false
+		case c == '\\':
+			inArg, escaped = true, true
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			arg.WriteRune(c)
+		case c == '"' || c == '\'':
+			inArg, quote = true, c
+		case unicode.IsSpace(c):
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			inArg = true
+			arg.WriteRune(c)
+		}
+	}
+
+	if inArg {
+		args = append(args, arg.String())
+	}
+
+	return args
+}
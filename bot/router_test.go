@@ -0,0 +1,135 @@
+package bot_test
+
+import (
+	"context"
+	"go-vk-sdk/actor"
+	"go-vk-sdk/bot"
+	"go-vk-sdk/constants"
+	"go-vk-sdk/events"
+	"go-vk-sdk/vktest"
+	"reflect"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func newMessage(peerID int, text, payload string) *events.EventMessageNew {
+	event := &events.EventMessageNew{}
+	event.Message.PeerID = peerID
+	event.Message.FromID = 1
+	event.Message.Text = text
+	event.Message.Payload = payload
+
+	return event
+}
+
+// record returns the handler which saves the name of the route and the arguments
+func record(handled *string, args *[]string, name string) bot.HandlerFunc {
+	return func(c *bot.Context) error {
+		*handled, *args = name, c.Args
+		if c.Match != nil {
+			*args = c.Match
+		}
+		return nil
+	}
+}
+
+func TestRouter(t *testing.T) {
+	var handled string
+	var args []string
+
+	router := bot.NewRouter(nil, nil, 1).ScreenName("apiclub")
+	router.Payload("buy", record(&handled, &args, "payload"))
+	router.Command("/start", record(&handled, &args, "start"))
+	router.Command("/admin", record(&handled, &args, "admin"), bot.FromIDs(100))
+	router.Prefix("привет", record(&handled, &args, "hello"), bot.Private)
+	router.Prefix("K", record(&handled, &args, "kelvin"))
+	router.Regexp(regexp.MustCompile(`^order (\d+)$`), record(&handled, &args, "order"))
+	router.Fallback(record(&handled, &args, "fallback"))
+
+	chat := constants.PeerChatIDOffset + 1
+
+	tests := []struct {
+		event   *events.EventMessageNew
+		handled string
+		args    []string
+	}{
+		{newMessage(1, "/START 42 \"a b\"", ""), "start", []string{"42", "a b"}},
+		{newMessage(chat, "[club1|@apiclub], /start", ""), "start", nil},
+		{newMessage(chat, "@apiclub /start x", ""), "start", []string{"x"}},
+		{newMessage(1, "/admin", ""), "fallback", []string{"/admin"}},
+		{newMessage(1, "ПРИВЕТ, мир", ""), "hello", []string{",", "мир"}},
+		{newMessage(chat, "привет", ""), "fallback", []string{"привет"}},
+		// the Kelvin sign is folded to K and takes 3 bytes
+		{newMessage(1, "K 1", ""), "kelvin", []string{"1"}},
+		{newMessage(1, "order 7", ""), "order", []string{"order 7", "7"}},
+		{newMessage(1, "Купить", `{"command":"buy"}`), "payload", []string{"Купить"}},
+		{newMessage(1, "/start", `"not an object"`), "start", nil},
+		{newMessage(1, "прив", ""), "fallback", []string{"прив"}},
+	}
+
+	for _, test := range tests {
+		handled, args = "", nil
+
+		if err := router.Handle(context.Background(), test.event); err != nil {
+			t.Fatal(err)
+		}
+
+		if handled != test.handled || !reflect.DeepEqual(args, test.args) {
+			t.Errorf("%q: handled by %q with %q, want %q with %q",
+				test.event.Message.Text, handled, args, test.handled, test.args)
+		}
+	}
+}
+
+func TestRouterReply(t *testing.T) {
+	s := vktest.NewServer()
+	t.Cleanup(s.Close)
+
+	s.Handle("messages.send", func(*vktest.Call) (interface{}, error) {
+		return 10, nil
+	})
+
+	router := bot.NewRouter(s.API(), &actor.Group{ID: 1, AccessToken: "token"}, 1)
+	router.Command("/ping", func(c *bot.Context) error {
+		return c.Reply("pong")
+	})
+
+	if err := router.Handle(context.Background(), newMessage(5, "/ping", "")); err != nil {
+		t.Fatal(err)
+	}
+
+	call := s.LastCall("messages.send")
+	if call == nil || call.Get("peer_id") != "5" || call.Get("message") != "pong" {
+		t.Fatalf("unexpected call %+v", call)
+	}
+
+	// the echo of the reply is recognised by its random_id
+	randomID, _ := strconv.Atoi(call.Get("random_id"))
+	if !router.Window().Contains(randomID) {
+		t.Errorf("random_id %d of the reply is not tracked", randomID)
+	}
+}
+
+func TestRouterHandlerSkipsOtherEvents(t *testing.T) {
+	called := false
+
+	router := bot.NewRouter(nil, nil, 1).Fallback(func(*bot.Context) error {
+		called = true
+		return nil
+	})
+
+	err := router.Handler()(context.Background(), &events.Update{Event: &events.EventMessageReply{}})
+	if err != nil || called {
+		t.Errorf("got error %v and called %v for message_reply", err, called)
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	got := bot.ParseArgs(`a  "b c" 'd\'e' f\ g ""`)
+	want := []string{"a", "b c", "d'e", "f g", ""}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -78,13 +78,17 @@ func (c *Callback) UpdateSettings(ctx context.Context, url string) error {
 	serverID := 0
 
 	g, err := request.NewGroupsGetByIDRequest(c.api, c.actor).Exec(ctx)
-	if err != nil || g.Error.Code != 0 {
+	if err != nil {
 		if errors.Is(err, internalErrors.ParamCode) {
 			return internalErrors.ErrorLog("Callback.UpdateSettings()", "Error request groups, need group access token")
 		}
 		return internalErrors.ErrorLog("Callback.UpdateSettings()", err.Error())
 	}
 
+	if len(g.Response.Groups) == 0 {
+		return internalErrors.ErrorLog("Callback.UpdateSettings()", "Error request groups, response is empty")
+	}
+
 	groupID := g.Response.Groups[0].ID
 
 	servers, err := c.GetServers(groupID)
@@ -206,7 +210,12 @@ func (c *Callback) handle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	callbackEvent := &events.EventCallback{Event: event}
+	callbackEvent := &events.EventCallback{
+		Event:   event,
+		EventID: updateEvent.EventID,
+		GroupID: updateEvent.GroupID,
+		Context: r.Context(),
+	}
 
 	callbackEvent.RetryCounter, _ = strconv.Atoi(r.Header.Get("X-Retry-Counter"))
 
@@ -243,6 +252,15 @@ func (c *Callback) AddEventListener(event events.EventType, listener *events.Eve
 	c.eventEmitter.On(event, listener)
 }
 
+// Handle adds the listener which calls the handler, see events.NewHandlerListener
+//
+//	c.Handle(events.EventTypeMessageNew, events.Chain(handle, events.Logging()))
+func (c *Callback) Handle(event events.EventType, handler events.Handler) *events.EventListener[*events.EventCallback] {
+	listener := events.NewHandlerListener(handler)
+	c.AddEventListener(event, listener)
+	return listener
+}
+
 func (c *Callback) RemoveEventListener(event events.EventType, listener *events.EventListener[*events.EventCallback]) {
 	if event == "" || listener == nil {
 		logger.Log("Callback.RemoveEventListener()", "attempted to remove nil event or listener")
@@ -282,7 +300,7 @@ func (c *Callback) GetServers(groupID int) ([]objects.GroupCallbackServer, error
 		GroupID(groupID).
 		Exec(context.Background())
 	if err != nil {
-		return nil, internalErrors.ErrorLog("Callback.GetCallbackServers()", "Request error: "+err.Error()+"\nResponse error: "+res.Error.Error())
+		return nil, internalErrors.ErrorLog("Callback.GetCallbackServers()", "Request error: "+err.Error())
 	}
 
 	return res.Response.Items, nil
@@ -300,7 +318,7 @@ func (c *Callback) AddServer(groupID int, title, url, secret string) (int, error
 		Exec(context.Background())
 
 	if err != nil {
-		return -1, internalErrors.ErrorLog("Callback.AddServer()", "Request error: "+err.Error()+"\nResponse error: "+res.Error.Error())
+		return -1, internalErrors.ErrorLog("Callback.AddServer()", "Request error: "+err.Error())
 	}
 
 	return res.Response.ServerID, nil
@@ -314,7 +332,7 @@ func (c *Callback) DeleteServer(serverID, groupID int) (bool, error) {
 		Exec(context.Background())
 
 	if err != nil {
-		return false, internalErrors.ErrorLog("Callback.DeleteServer()", "Request error: "+err.Error()+"\nResponse error: "+res.Error.Error())
+		return false, internalErrors.ErrorLog("Callback.DeleteServer()", "Request error: "+err.Error())
 	}
 
 	if res.Response == 1 {
@@ -340,9 +358,9 @@ func (c *Callback) SetSettingsEvents(groupID, serverID int, e []events.EventType
 		req.SetEvent(string(event), true)
 	}
 
-	res, err := req.Exec(context.Background())
+	_, err := req.Exec(context.Background())
 	if err != nil {
-		return false, internalErrors.ErrorLog("Callback.SetSettings()", "Request error: "+err.Error()+"\nResponse error: "+res.Error.Error())
+		return false, internalErrors.ErrorLog("Callback.SetSettings()", "Request error: "+err.Error())
 	}
 
 	return true, nil
@@ -355,7 +373,7 @@ func (c *Callback) GetConfirmationKey(groupID int) (string, error) {
 		Exec(context.Background())
 
 	if err != nil {
-		return "", internalErrors.ErrorLog("Callback.GetConfirmationKey()", "Request error: "+err.Error()+"\nResponse error: "+res.Error.Error())
+		return "", internalErrors.ErrorLog("Callback.GetConfirmationKey()", "Request error: "+err.Error())
 	}
 
 	return res.Response.Code, nil
@@ -64,12 +64,31 @@ const (
 	ButtonGreen string = Positive  // Accept, agree. #4BB34B
 )
 
+// Type of the range of format_data.
+const (
+	FormatDataVersion   int    = 1
+	FormatDataBold      string = "bold"
+	FormatDataItalic    string = "italic"
+	FormatDataUnderline string = "underline"
+	FormatDataURL       string = "url"
+)
+
+// Message template.
+const (
+	TemplateCarousel string = "carousel"
+
+	CarouselActionOpenLink  string = "open_link"  // Opens the link of the element
+	CarouselActionOpenPhoto string = "open_photo" // Opens the photo of the element
+)
+
 // Conversations types.
 const (
 	PeerUser  string = "user"
 	PeerChat  string = "chat"
 	PeerGroup string = "group"
 	PeerEmail string = "email"
+
+	PeerChatIDOffset int = 2000000000 // peer_id of the chat is 2000000000 + chat_id
 )
 
 // Ads
@@ -494,6 +513,61 @@ const (
 	MessagesCommandNotSupportedButton string = "not_supported_button"
 )
 
+// Filter for method messages.getConversations
+const (
+	MessagesConversationsFilterAll        string = "all"
+	MessagesConversationsFilterArchive    string = "archive"
+	MessagesConversationsFilterImportant  string = "important"
+	MessagesConversationsFilterUnanswered string = "unanswered"
+	MessagesConversationsFilterUnread     string = "unread"
+)
+
+// Media type for method messages.getHistoryAttachments
+const (
+	MessagesMediaTypePhoto    string = "photo"
+	MessagesMediaTypeVideo    string = "video"
+	MessagesMediaTypeAudio    string = "audio"
+	MessagesMediaTypeDoc      string = "doc"
+	MessagesMediaTypeLink     string = "link"
+	MessagesMediaTypeMarket   string = "market"
+	MessagesMediaTypeWall     string = "wall"
+	MessagesMediaTypeShare    string = "share"
+	MessagesMediaTypeGraffiti string = "graffiti"
+)
+
+// Activity type for method messages.setActivity
+const (
+	MessagesActivityTyping       string = "typing"
+	MessagesActivityAudioMessage string = "audiomessage"
+	MessagesActivityPhoto        string = "photo"
+	MessagesActivityVideo        string = "video"
+	MessagesActivityFile         string = "file"
+)
+
+// Intent of the message sent by the community. Doc: https://dev.vk.com/ru/api/bots/development/messages#Интенты
+const (
+	MessagesIntentDefault               string = "default"
+	MessagesIntentPromoNewsletter       string = "promo_newsletter"
+	MessagesIntentBotAdInvite           string = "bot_ad_invite"
+	MessagesIntentBotAdPromo            string = "bot_ad_promo"
+	MessagesIntentConfirmedNotification string = "confirmed_notification"
+	MessagesIntentPurchaseUpdate        string = "purchase_update"
+	MessagesIntentAccountUpdate         string = "account_update"
+	MessagesIntentGameNotification      string = "game_notification"
+	MessagesIntentCustomerSupport       string = "customer_support"
+	MessagesIntentNonPromoNewsletter    string = "non_promo_newsletter"
+)
+
+// Name case for the names of the users
+const (
+	NameCaseNom string = "nom" // nominative
+	NameCaseGen string = "gen" // genitive
+	NameCaseDat string = "dat" // dative
+	NameCaseAcc string = "acc" // accusative
+	NameCaseIns string = "ins" // instrumental
+	NameCaseAbl string = "abl" // prepositional
+)
+
 // Stories
 
 type StoriesStoryType string
@@ -589,23 +663,32 @@ const (
 // Attachment
 
 const (
-	AttachmentTypePhoto       = "photo"
-	AttachmentTypePostedPhoto = "posted_photo"
-	AttachmentTypeAudio       = "audio"
-	AttachmentTypeVideo       = "video"
-	AttachmentTypeDoc         = "doc"
-	AttachmentTypeLink        = "link"
-	AttachmentTypeGraffiti    = "graffiti"
-	AttachmentTypeNote        = "note"
-	AttachmentTypeApp         = "app"
-	AttachmentTypePoll        = "poll"
-	AttachmentTypePage        = "page"
-	AttachmentTypeAlbum       = "album"
-	AttachmentTypePhotosList  = "photos_list"
-	AttachmentTypeMarketAlbum = "market_album"
-	AttachmentTypeMarket      = "market"
-	AttachmentTypeEvent       = "event"
-	AttachmentTypeWall        = "wall"
-	AttachmentTypeStory       = "story"
-	AttachmentTypePodcast     = "podcast"
+	AttachmentTypePhoto         = "photo"
+	AttachmentTypePostedPhoto   = "posted_photo"
+	AttachmentTypeAudio         = "audio"
+	AttachmentTypeVideo         = "video"
+	AttachmentTypeDoc           = "doc"
+	AttachmentTypeLink          = "link"
+	AttachmentTypeGraffiti      = "graffiti"
+	AttachmentTypeNote          = "note"
+	AttachmentTypeApp           = "app"
+	AttachmentTypePoll          = "poll"
+	AttachmentTypePage          = "page"
+	AttachmentTypeAlbum         = "album"
+	AttachmentTypePhotosList    = "photos_list"
+	AttachmentTypeMarketAlbum   = "market_album"
+	AttachmentTypeMarket        = "market"
+	AttachmentTypeEvent         = "event"
+	AttachmentTypeWall          = "wall"
+	AttachmentTypeStory         = "story"
+	AttachmentTypePodcast       = "podcast"
+	AttachmentTypeAudioMessage  = "audio_message"
+	AttachmentTypeAudioPlaylist = "audio_playlist"
+	AttachmentTypeWallReply     = "wall_reply"
+	AttachmentTypeSticker       = "sticker"
+	AttachmentTypeGift          = "gift"
+	AttachmentTypeCall          = "call"
+	AttachmentTypeClip          = "clip"
+	AttachmentTypeVideoPlaylist = "video_playlist"
+	AttachmentTypeGroup         = "group"
 )
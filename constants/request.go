@@ -4,6 +4,7 @@ const (
 	ContentTypeFormURLEncoded string = "application/x-www-form-urlencoded"
 	ContentTypeMultipartForm  string = "multipart/form-data"
 	ContentTypeJSON           string = "application/json"
+	ContentTypeOctetStream    string = "application/octet-stream"
 	AcceptEncodingGzip        string = "gzip"
 )
 
@@ -70,6 +71,7 @@ const (
 	ParameterNameNewPassword            string = "new_password"              // type=string
 	ParameterNameOffset                 string = "offset"                    // type=uint,int
 	ParameterNameCount                  string = "count"                     // type=uint,int
+	ParameterNameStartFrom              string = "start_from"                // type=string
 	ParameterNameFields                 string = "fields"                    // type=string
 	ParameterNameFilter                 string = "filter"                    // type=string
 	ParameterNameServerID               string = "server_id"                 // type=uint
@@ -168,4 +170,85 @@ const (
 	ParameterNameNeedPts                string = "need_pts"                  //type=1
 	ParameterNameEnabled                string = "enabled"                   //type=1
 	ParameterNameAPIVersion             string = "api_version"               //type=string
+	ParameterNameMessage                string = "message"                   //type=string
+	ParameterNameRandomID               string = "random_id"                 //type=int
+	ParameterNamePeerIDs                string = "peer_ids"                  //type=string
+	ParameterNameAttachment             string = "attachment"                //type=string
+	ParameterNameReplyTo                string = "reply_to"                  //type=int
+	ParameterNameForwardMessages        string = "forward_messages"          //type=string
+	ParameterNameForward                string = "forward"                   //type=string (json)
+	ParameterNameStickerID              string = "sticker_id"                //type=int
+	ParameterNameKeyboard               string = "keyboard"                  //type=string (json)
+	ParameterNameTemplate               string = "template"                  //type=string (json)
+	ParameterNameFormatData             string = "format_data"               //type=string (json)
+	ParameterNamePayload                string = "payload"                   //type=string (json)
+	ParameterNameContentSource          string = "content_source"            //type=string (json)
+	ParameterNameDontParseLinks         string = "dont_parse_links"          //type=0,1
+	ParameterNameDisableMentions        string = "disable_mentions"          //type=0,1
+	ParameterNameIntent                 string = "intent"                    //type=string
+	ParameterNameSubscribeID            string = "subscribe_id"              //type=int
+	ParameterNameLat                    string = "lat"                       //type=float
+	ParameterNameLong                   string = "long"                      //type=float
+	ParameterNameMessageID              string = "message_id"                //type=int
+	ParameterNameMessageIDs             string = "message_ids"               //type=string
+	ParameterNameConversationMessageID  string = "conversation_message_id"   //type=int
+	ParameterNameConversationMessageIDs string = "conversation_message_ids"  //type=string
+	ParameterNameCMID                   string = "cmid"                      //type=int
+	ParameterNameCMIDs                  string = "cmids"                     //type=string
+	ParameterNameSpam                   string = "spam"                      //type=0,1
+	ParameterNameDeleteForAll           string = "delete_for_all"            //type=0,1
+	ParameterNameKeepForwardMessages    string = "keep_forward_messages"     //type=0,1
+	ParameterNameKeepSnippets           string = "keep_snippets"             //type=0,1
+	ParameterNameChatIDs                string = "chat_ids"                  //type=string
+	ParameterNameNameCase               string = "name_case"                 //type=string
+	ParameterNameLink                   string = "link"                      //type=string
+	ParameterNameStartMessageID         string = "start_message_id"          //type=int
+	ParameterNameRev                    string = "rev"                       //type=0,1
+	ParameterNameMediaType              string = "media_type"                //type=string
+	ParameterNamePhotoSizes             string = "photo_sizes"               //type=0,1
+	ParameterNamePreserveOrder          string = "preserve_order"            //type=0,1
+	ParameterNameMaxForwardsLevel       string = "max_forwards_level"        //type=int
+	ParameterNamePreviewLength          string = "preview_length"            //type=int
+	ParameterNamePts                    string = "pts"                       //type=int
+	ParameterNameOnlines                string = "onlines"                   //type=0,1
+	ParameterNameEventsLimit            string = "events_limit"              //type=int
+	ParameterNameMsgsLimit              string = "msgs_limit"                //type=int
+	ParameterNameMaxMsgID               string = "max_msg_id"                //type=int
+	ParameterNameLastN                  string = "last_n"                    //type=int
+	ParameterNameCredentials            string = "credentials"               //type=0,1
+	ParameterNameReactionID             string = "reaction_id"               //type=int
+	ParameterNameClientVersion          string = "client_version"            //type=int
+	ParameterNameAnswered               string = "answered"                  //type=0,1
+	ParameterNameImportant              string = "important"                 //type=0,1
+	ParameterNameMarkConversationAsRead string = "mark_conversation_as_read" //type=0,1
+	ParameterNameMemberID               string = "member_id"                 //type=int
+	ParameterNameDate                   string = "date"                      //type=string
+	ParameterNameEventID                string = "event_id"                  //type=string
+	ParameterNameEventData              string = "event_data"                //type=string (json)
+	ParameterNameType                   string = "type"                      //type=string
+	ParameterNameFile                   string = "file"                      //type=string
+	ParameterNameReset                  string = "reset"                     //type=0,1
+	ParameterNameVisibleMessagesCount   string = "visible_messages_count"    //type=int
+	ParameterNameFromGroup              string = "from_group"                //type=0,1
+	ParameterNameAttachments            string = "attachments"               //type=string
+	ParameterNamePublishDate            string = "publish_date"              //type=int (unixtime)
+	ParameterNameCopyright              string = "copyright"                 //type=string
+	ParameterNamePlaceID                string = "place_id"                  //type=int
+	ParameterNameMuteNotifications      string = "mute_notifications"        //type=0,1
+	ParameterNameCloseComments          string = "close_comments"            //type=0,1
+	ParameterNameDonutPaidDuration      string = "donut_paid_duration"       //type=int
+	ParameterNameSigned                 string = "signed"                    //type=0,1
+	ParameterNameMarkAsAds              string = "mark_as_ads"               //type=0,1
+	ParameterNameFriendsOnly            string = "friends_only"              //type=0,1
+	ParameterNamePostID                 string = "post_id"                   //type=int
+	ParameterNameGUID                   string = "guid"                      //type=string
+	ParameterNamePhoto                  string = "photo"                     //type=string
+	ParameterNameServer                 string = "server"                    //type=int
+	ParameterNameCaption                string = "caption"                   //type=string
+	ParameterNameTags                   string = "tags"                      //type=string
+	ParameterNameReturnTags             string = "return_tags"               //type=0,1
+	ParameterNameAddToNews              string = "add_to_news"               //type=0,1
+	ParameterNameLinkText               string = "link_text"                 //type=string
+	ParameterNameUploadResults          string = "upload_results"            //type=string
+	ParameterNameUploadResponse         string = "upload_response"           //type=string (json)
 )
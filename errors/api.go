@@ -1,6 +1,9 @@
 package errors
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type NumberAPIError int
 
@@ -27,10 +30,31 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("%s error code: %d -> %s", MessagePrefix, e.Code, e.Message)
 }
 
+// Is matches the ErrorType constants, for example errors.Is(err, errors.CaptchaCode)
+func (e *APIError) Is(target error) bool {
+	code, ok := target.(ErrorType)
+	return ok && e.Code == int(code)
+}
+
 type ExecuteAPIErrors []ExecuteAPIError
 
 func (e *ExecuteAPIErrors) Error() string {
-	return fmt.Sprintf("%s execute errors: %w", MessagePrefix, e)
+	messages := make([]string, 0, len(*e))
+	for _, err := range *e {
+		messages = append(messages, fmt.Sprintf("%s code: %d -> %s", err.Method, err.Code, err.Message))
+	}
+
+	return fmt.Sprintf("%s execute errors (%d): %s", MessagePrefix, len(*e), strings.Join(messages, "; "))
+}
+
+// Unwrap returns the errors of the single calls, so errors.Is and errors.As match any of them
+func (e *ExecuteAPIErrors) Unwrap() []error {
+	errs := make([]error, 0, len(*e))
+	for i := range *e {
+		errs = append(errs, &(*e)[i])
+	}
+
+	return errs
 }
 
 type ExecuteAPIError struct {
@@ -43,6 +67,12 @@ func (e *ExecuteAPIError) Error() string {
 	return fmt.Sprintf("%s execute error code: %d -> %s", MessagePrefix, e.Code, e.Message)
 }
 
+// Is matches the ErrorType constants, for example errors.Is(err, errors.FloodCode)
+func (e *ExecuteAPIError) Is(target error) bool {
+	code, ok := target.(ErrorType)
+	return ok && e.Code == int(code)
+}
+
 type UploadAPIError struct {
 	Err         string `json:"errors"`
 	Code        int    `json:"error_code"`
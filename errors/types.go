@@ -289,3 +289,26 @@ const (
 	InvalidPhotoCode                                  ErrorType = 5709 // Invalid Photo
 	InvalidHashCode                                   ErrorType = 5710 // Invalid hash
 )
+
+// IsRetryable reports whether a request failed with this code can be repeated automatically:
+// too many requests per second (6), the internal server error (10) and the rate limit of the method (29)
+func (e ErrorType) IsRetryable() bool {
+	switch e {
+	case TooManyRequestCode, InternalServerCode, RateLimitCode:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsTransient reports whether a request failed with this code may succeed if it is repeated later.
+// Besides the retryable codes it includes flood control (9), which is not repeated automatically:
+// the limit of the same actions is lifted only after a long time
+func (e ErrorType) IsTransient() bool {
+	switch e {
+	case FloodCode:
+		return true
+	default:
+		return e.IsRetryable()
+	}
+}
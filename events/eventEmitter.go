@@ -1,5 +1,11 @@
 package events
 
+import (
+	"fmt"
+	"go-vk-sdk/logger"
+	"runtime/debug"
+)
+
 type EventListener[T interface{}] struct {
 	Listener func(T)
 }
@@ -37,14 +43,25 @@ func (e *EventEmitter[T, U]) Off(name T, handler *EventListener[U]) {
 	}
 }
 
+// Emit calls the listeners of the event, the panic of the listener is logged and the next listeners are called
 func (e *EventEmitter[T, U]) Emit(name T, event U) {
 	if handlers, ok := e.listeners[name]; ok {
 		for _, handler := range handlers {
-			handler.Listener(event)
+			e.call(name, handler, event)
 		}
 	}
 }
 
+func (e *EventEmitter[T, U]) call(name T, handler *EventListener[U], event U) {
+	defer func() {
+		if v := recover(); v != nil {
+			logger.Log("Events.EventEmitter.Emit()", fmt.Sprintf("listener of %v panicked: %v\n%s", name, v, debug.Stack()))
+		}
+	}()
+
+	handler.Listener(event)
+}
+
 func (e *EventEmitter[T, U]) Clear(name T) {
 	delete(e.listeners, name)
 }
@@ -0,0 +1,247 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"go-vk-sdk/logger"
+	"runtime/debug"
+	"strconv"
+	"time"
+)
+
+// Update Decoded event with the fields of its envelope, the same for the callback and the long poll.
+// The updates of the user long poll are wrapped by longPollUser.NewUpdate
+type Update struct {
+	Type    EventType
+	EventID string
+	GroupID int // ID of the community where the event occurred
+	Event   Event
+}
+
+// Handler Handler of the event, the errors are returned to the source:
+// the callback answers VK with an error, the long poll logs it.
+// The sources call the handler by Call, so the panic of the handler is returned as *PanicError as well
+type Handler func(ctx context.Context, update *Update) error
+
+// Call calls the handler, the panic of the handler is returned as *PanicError with the stack of the panic
+func (h Handler) Call(ctx context.Context, update *Update) (err error) {
+	defer RecoverPanic(&err)
+	return h(ctx, update)
+}
+
+// Middleware Wraps the handler, for example to recover from panics or to skip the events
+type Middleware func(next Handler) Handler
+
+// Chain wraps the handler by the middleware, the first middleware is the outermost one
+//
+//	handler := events.Chain(handle, events.Logging(), events.FilterGroupIDs(groupID))
+func Chain(handler Handler, middleware ...Middleware) Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+
+	return handler
+}
+
+// PanicError Panic of the handler recovered by Recover
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("events: handler panicked: %v", e.Value)
+}
+
+// RecoverPanic must be deferred, it sets the panic of the function to err as *PanicError
+//
+//	defer events.RecoverPanic(&err)
+func RecoverPanic(err *error) {
+	if v := recover(); v != nil {
+		*err = &PanicError{Value: v, Stack: debug.Stack()}
+	}
+}
+
+// Recover returns the panic of the next handler as *PanicError, so the outer middleware sees it as the error.
+// The sources recover the panics themselves, Recover is needed only inside the chain:
+// Logging before Recover logs the panic with the duration and the envelope of the event
+func Recover() Middleware {
+	return func(next Handler) Handler {
+		return next.Call
+	}
+}
+
+// Logging logs every event with its envelope, the duration of the handler and the error
+// as key=value pairs, for example "type=message_new event_id=abc group_id=1 from_id=2 duration=1.5ms"
+func Logging() Middleware {
+	return Timing(func(update *Update, duration time.Duration, err error) {
+		line := "type=" + string(update.Type) +
+			" event_id=" + update.EventID +
+			" group_id=" + strconv.Itoa(update.GroupID)
+
+		if fromID, ok := FromID(update.Event); ok {
+			line += " from_id=" + strconv.Itoa(fromID)
+		}
+
+		line += " duration=" + duration.String()
+
+		if err != nil {
+			line += " error=" + strconv.Quote(err.Error())
+		}
+
+		logger.Log("Events.Logging()", line)
+	})
+}
+
+// Timing calls observe with the duration of the handler after every event
+func Timing(observe func(update *Update, duration time.Duration, err error)) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, update *Update) error {
+			start := time.Now()
+			err := next(ctx, update)
+			observe(update, time.Since(start), err)
+
+			return err
+		}
+	}
+}
+
+// FilterGroupIDs skips the events of the other communities
+func FilterGroupIDs(ids ...int) Middleware {
+	allowed := idSet(ids)
+
+	return Filter(func(update *Update) bool {
+		return allowed[update.GroupID]
+	})
+}
+
+// FilterFromIDs skips the events of the other users and the events without the author, see FromID
+func FilterFromIDs(ids ...int) Middleware {
+	allowed := idSet(ids)
+
+	return Filter(func(update *Update) bool {
+		fromID, ok := FromID(update.Event)
+		return ok && allowed[fromID]
+	})
+}
+
+// Filter skips the events for which pass returns false, the skipped events are handled without an error
+func Filter(pass func(update *Update) bool) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, update *Update) error {
+			if !pass(update) {
+				return nil
+			}
+
+			return next(ctx, update)
+		}
+	}
+}
+
+// NewHandlerListener returns the listener of the callback which calls the handler,
+// the error or the panic of the handler is set to EventCallback.Error, so the callback answers VK with an error
+func NewHandlerListener(handler Handler) *EventListener[*EventCallback] {
+	return NewEventListener(func(e *EventCallback) {
+		ctx := e.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		e.Error = handler.Call(ctx, &Update{
+			Type:    e.Event.EventType(),
+			EventID: e.EventID,
+			GroupID: e.GroupID,
+			Event:   e.Event,
+		})
+	})
+}
+
+// Author Event of the other source which reports its author to FromID, for example the event of the user long poll
+type Author interface {
+	AuthorID() (int, bool)
+}
+
+// FromID returns the author of the event: the sender of the message, the author of the comment or the post,
+// the user who joined, voted, paid or pressed the button. ok is false for the events without the author
+func FromID(event Event) (fromID int, ok bool) {
+	switch e := event.(type) {
+	case *EventMessageNew:
+		return e.Message.FromID, true
+	case *EventMessageReply:
+		return e.FromID, true
+	case *EventMessageEdit:
+		return e.FromID, true
+	case *EventMessageAllow:
+		return e.UserID, true
+	case *EventMessageDeny:
+		return e.UserID, true
+	case *EventMessageTypingState:
+		return e.FromID, true
+	case *EventMessageEvent:
+		return e.UserID, true
+	case *EventMessageRead:
+		return e.FromID, true
+	case *EventPhotoCommentNew:
+		return e.FromID, true
+	case *EventPhotoCommentEdit:
+		return e.FromID, true
+	case *EventPhotoCommentRestore:
+		return e.FromID, true
+	case *EventVideoCommentNew:
+		return e.FromID, true
+	case *EventVideoCommentEdit:
+		return e.FromID, true
+	case *EventVideoCommentRestore:
+		return e.FromID, true
+	case *EventWallPostNew:
+		return e.FromID, true
+	case *EventWallRepost:
+		return e.FromID, true
+	case *EventWallReplyNew:
+		return e.FromID, true
+	case *EventWallReplyEdit:
+		return e.FromID, true
+	case *EventWallReplyRestore:
+		return e.FromID, true
+	case *EventBoardPostNew:
+		return e.FromID, true
+	case *EventBoardPostEdit:
+		return e.FromID, true
+	case *EventBoardPostRestore:
+		return e.FromID, true
+	case *EventMarketCommentNew:
+		return e.FromID, true
+	case *EventMarketCommentEdit:
+		return e.FromID, true
+	case *EventMarketCommentRestore:
+		return e.FromID, true
+	case *EventGroupJoin:
+		return e.UserID, true
+	case *EventGroupLeave:
+		return e.UserID, true
+	case *EventPollVoteNew:
+		return e.UserID, true
+	case *EventVkpayTransaction:
+		return e.FromID, true
+	case *EventLeadFormsNew:
+		return e.UserID, true
+	case *EventAppPayload:
+		return e.UserID, true
+	case *EventLikeAdd:
+		return e.LikerID, true
+	case *EventLikeRemove:
+		return e.LikerID, true
+	case Author:
+		return e.AuthorID()
+	}
+
+	return 0, false
+}
+
+func idSet(ids []int) map[int]bool {
+	set := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
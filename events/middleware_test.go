@@ -0,0 +1,152 @@
+package events_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"go-vk-sdk/events"
+	"go-vk-sdk/logger"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newUpdate(groupID, fromID int) *events.Update {
+	event := &events.EventMessageNew{}
+	event.Message.FromID = fromID
+
+	return &events.Update{Type: events.EventTypeMessageNew, EventID: "abc", GroupID: groupID, Event: event}
+}
+
+func TestChainOrder(t *testing.T) {
+	var order []string
+
+	mark := func(name string) events.Middleware {
+		return func(next events.Handler) events.Handler {
+			return func(ctx context.Context, update *events.Update) error {
+				order = append(order, name)
+				return next(ctx, update)
+			}
+		}
+	}
+
+	handler := events.Chain(func(context.Context, *events.Update) error {
+		order = append(order, "handler")
+		return nil
+	}, mark("first"), mark("second"))
+
+	if err := handler(context.Background(), newUpdate(1, 2)); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(order, " ") != "first second handler" {
+		t.Errorf("got order %v", order)
+	}
+}
+
+func TestHandlerCallRecovers(t *testing.T) {
+	handler := events.Handler(func(context.Context, *events.Update) error {
+		panic("boom")
+	})
+
+	var panicError *events.PanicError
+	if err := handler.Call(context.Background(), newUpdate(1, 2)); !errors.As(err, &panicError) || panicError.Value != "boom" {
+		t.Fatalf("got error %v, want *events.PanicError", err)
+	}
+
+	if len(panicError.Stack) == 0 {
+		t.Error("the stack of the panic is empty")
+	}
+}
+
+func TestRecoverInsideLogging(t *testing.T) {
+	var out bytes.Buffer
+	logger.SetOutput(&out)
+	logger.Enable()
+	t.Cleanup(func() {
+		logger.Disable()
+		logger.SetOutput(os.Stdout)
+	})
+
+	var observed error
+	handler := events.Chain(func(context.Context, *events.Update) error {
+		panic("boom")
+	}, events.Logging(), events.Timing(func(_ *events.Update, _ time.Duration, err error) {
+		observed = err
+	}), events.Recover())
+
+	var panicError *events.PanicError
+	if err := handler(context.Background(), newUpdate(1, 2)); !errors.As(err, &panicError) {
+		t.Fatalf("got error %v, want *events.PanicError", err)
+	}
+
+	if !errors.As(observed, &panicError) {
+		t.Errorf("Timing observed %v, want *events.PanicError", observed)
+	}
+
+	line := out.String()
+	for _, want := range []string{"type=message_new", "event_id=abc", "group_id=1", "from_id=2", "duration=", `error="events: handler panicked: boom"`} {
+		if !strings.Contains(line, want) {
+			t.Errorf("log %q does not contain %q", line, want)
+		}
+	}
+}
+
+func TestFilters(t *testing.T) {
+	calls := 0
+	handle := func(context.Context, *events.Update) error {
+		calls++
+		return nil
+	}
+
+	byGroup := events.Chain(handle, events.FilterGroupIDs(1))
+	byAuthor := events.Chain(handle, events.FilterFromIDs(2))
+
+	_ = byGroup(context.Background(), newUpdate(1, 3))
+	_ = byGroup(context.Background(), newUpdate(5, 3))
+	_ = byAuthor(context.Background(), newUpdate(5, 2))
+	_ = byAuthor(context.Background(), newUpdate(5, 3))
+
+	// the event without the author is skipped by FilterFromIDs
+	_ = byAuthor(context.Background(), &events.Update{Type: events.EventTypeGroupChangeSettings, Event: &events.EventGroupChangeSettings{}})
+
+	if calls != 2 {
+		t.Errorf("got %d calls, want 2", calls)
+	}
+}
+
+func TestHandlerListenerRecovers(t *testing.T) {
+	listener := events.NewHandlerListener(func(ctx context.Context, update *events.Update) error {
+		if update.GroupID != 1 || update.EventID != "abc" {
+			t.Errorf("unexpected update %+v", update)
+		}
+		panic("boom")
+	})
+
+	e := &events.EventCallback{Event: &events.EventMessageNew{}, EventID: "abc", GroupID: 1}
+	listener.Listener(e)
+
+	var panicError *events.PanicError
+	if !errors.As(e.Error, &panicError) {
+		t.Errorf("got error %v, want *events.PanicError", e.Error)
+	}
+}
+
+func TestEmitRecovers(t *testing.T) {
+	emitter := events.NewEventEmitter[events.EventType, *events.EventCallback]()
+
+	called := false
+	emitter.On(events.EventTypeMessageNew, events.NewEventListener(func(*events.EventCallback) {
+		panic("boom")
+	}))
+	emitter.On(events.EventTypeMessageNew, events.NewEventListener(func(*events.EventCallback) {
+		called = true
+	}))
+
+	emitter.Emit(events.EventTypeMessageNew, &events.EventCallback{})
+
+	if !called {
+		t.Error("the listener after the panicked one is not called")
+	}
+}
@@ -1,6 +1,7 @@
 package events
 
 import (
+	"context"
 	"encoding/json"
 	internalErrors "go-vk-sdk/errors"
 	"go-vk-sdk/objects"
@@ -18,7 +19,7 @@ type EventUpdate struct {
 	Type       EventType       `json:"type"`
 	EventID    string          `json:"event_id"`
 	VersionAPI string          `json:"v"`        // api version for which the event was generated
-	Object     json.RawMessage `json:"objects"`  // objects that triggered the event
+	Object     json.RawMessage `json:"object"`   // objects that triggered the event
 	GroupID    int             `json:"group_id"` // ID of the community where the event occurred
 	Secret     string          `json:"secret"`
 }
@@ -27,6 +28,9 @@ type EventUpdate struct {
 
 type EventCallback struct {
 	Event           Event
+	EventID         string
+	GroupID         int             // ID of the community where the event occurred
+	Context         context.Context // context of the request of VK
 	Error           error
 	RetryCounter    int       // retry counter
 	IsRetryAfterKey bool      // retry after
@@ -21,8 +21,10 @@ type Server struct {
 }
 
 type EventUpdate struct {
-	Type  events.EventType
-	Event interface{}
+	Type    events.EventType
+	EventID string
+	GroupID int // ID of the community where the event occurred
+	Event   interface{}
 }
 
 type LongPoll struct {
@@ -144,6 +146,7 @@ func (l *LongPoll) Run(ctx context.Context) error {
 			switch FailedType(resp.Failed) {
 			case 0, FailedTypeOutdatedStory:
 				l.ts = resp.Ts
+				l.req.Ts(l.ts)
 			case FailedTypeExpiredKey:
 				err = l.UpdateServer(false)
 			case FailedTypeOutdatedUserInfo:
@@ -164,8 +167,10 @@ func (l *LongPoll) Run(ctx context.Context) error {
 				}
 
 				l.chanUpdate <- &EventUpdate{
-					Type:  update.Type,
-					Event: event,
+					Type:    update.Type,
+					EventID: update.EventID,
+					GroupID: update.GroupID,
+					Event:   event,
 				}
 			}
 		}
@@ -193,6 +198,39 @@ func (l *LongPoll) Updates() chan *EventUpdate {
 	return l.chanUpdate
 }
 
+// Serve calls the handler for every update until the context is done,
+// the errors and the panics of the handler are logged. The long poll must be run separately
+//
+//	go lp.Run(ctx)
+//	lp.Serve(ctx, events.Chain(handle, events.Logging()))
+func (l *LongPoll) Serve(ctx context.Context, handler events.Handler) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case update, ok := <-l.chanUpdate:
+			if !ok {
+				return nil
+			}
+
+			event, ok := update.Event.(events.Event)
+			if !ok {
+				continue
+			}
+
+			err := handler.Call(ctx, &events.Update{
+				Type:    update.Type,
+				EventID: update.EventID,
+				GroupID: update.GroupID,
+				Event:   event,
+			})
+			if err != nil {
+				logger.Log("LongPollGroup.LongPoll.Serve()", "error handling event "+string(update.Type)+": "+err.Error())
+			}
+		}
+	}
+}
+
 func (l *LongPoll) SetServer(server *Server) error {
 	if server == nil || server.URL == "" || server.Key == "" {
 		return internalErrors.ErrorLog("LongPollGroup.LongPoll.SetServer()", fmt.Sprintf("Invalid server configuration %+v\n", server))
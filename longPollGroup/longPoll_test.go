@@ -0,0 +1,45 @@
+package longPollGroup_test
+
+import (
+	"context"
+	"go-vk-sdk/actor"
+	"go-vk-sdk/api"
+	"go-vk-sdk/events"
+	"go-vk-sdk/longPollGroup"
+	"testing"
+	"time"
+)
+
+func TestServeRecovers(t *testing.T) {
+	lp := longPollGroup.NewLongPoll(api.NewAPI(), &actor.Group{ID: 1, AccessToken: "token"}, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	handled := make(chan int, 2)
+	go func() {
+		_ = lp.Serve(ctx, func(_ context.Context, update *events.Update) error {
+			handled <- update.GroupID
+			if update.GroupID == 1 {
+				panic("boom")
+			}
+			return nil
+		})
+	}()
+
+	// the update after the panic is handled
+	for _, groupID := range []int{1, 2} {
+		lp.Updates() <- &longPollGroup.EventUpdate{Type: events.EventTypeMessageNew, GroupID: groupID, Event: &events.EventMessageNew{}}
+	}
+
+	for _, want := range []int{1, 2} {
+		select {
+		case got := <-handled:
+			if got != want {
+				t.Errorf("got group %d, want %d", got, want)
+			}
+		case <-ctx.Done():
+			t.Fatal("the update is not handled")
+		}
+	}
+}
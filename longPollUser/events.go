@@ -2,6 +2,7 @@ package longPollUser
 
 import (
 	internalErrors "go-vk-sdk/errors"
+	"strconv"
 	"time"
 )
 
@@ -37,6 +38,43 @@ const (
 	EventTypeNotificationSettingsChange EventType = 114 // $peer_id(integer), $sound(integer), $disabled_until(integer). Notification settings changed for $peer_id. $sound indicates on/off, $disabled_until is the mute duration (-1: forever, 0: off).
 )
 
+var eventTypeNames = map[EventType]string{
+	EventTypeMessageFlagsReplace:        "message_flags_replace",
+	EventTypeMessageFlagsSet:            "message_flags_set",
+	EventTypeMessageFlagsReset:          "message_flags_reset",
+	EventTypeMessageNew:                 "message_new",
+	EventTypeMessageEdit:                "message_edit",
+	EventTypeMessagesIncomingRead:       "messages_incoming_read",
+	EventTypeMessagesOutgoingRead:       "messages_outgoing_read",
+	EventTypeFriendOnline:               "friend_online",
+	EventTypeFriendOffline:              "friend_offline",
+	EventTypeDialogFlagsReset:           "dialog_flags_reset",
+	EventTypeDialogFlagsReplace:         "dialog_flags_replace",
+	EventTypeDialogFlagsSet:             "dialog_flags_set",
+	EventTypeMessagesDelete:             "messages_delete",
+	EventTypeMessagesRestore:            "messages_restore",
+	EventTypeMajorIDChange:              "major_id_change",
+	EventTypeMinorIDChange:              "minor_id_change",
+	EventTypeChatParametersChange:       "chat_parameters_change",
+	EventTypeChatInfoChange:             "chat_info_change",
+	EventTypeUserTypingDialog:           "user_typing_dialog",
+	EventTypeUserTypingChat:             "user_typing_chat",
+	EventTypeUsersTypingChat:            "users_typing_chat",
+	EventTypeUsersRecordingAudioMessage: "users_recording_audio_message",
+	EventTypeUserCall:                   "user_call",
+	EventTypeMenuCounterChange:          "menu_counter_change",
+	EventTypeNotificationSettingsChange: "notification_settings_change",
+}
+
+// String returns the name of the event, for example message_new, or the code of the unknown event
+func (t EventType) String() string {
+	if name, ok := eventTypeNames[t]; ok {
+		return name
+	}
+
+	return strconv.Itoa(int(t))
+}
+
 type Event interface {
 	init([]interface{}) error
 	EventType() EventType
@@ -12,6 +12,7 @@ type ExtraFieldsMessages struct {
 	Text           string
 	Attachments    Attachments // attachments, if mode = 2 was chosen ExtraOptionsMode = ExtraOptionsModeReceiveAttachments
 	AdditionalData AdditionalData
+	RandomID       int // random_id of the sent message, if mode = 128 was chosen ExtraOptionsMode = ExtraOptionsModeReturnRandomID
 }
 
 func (e *ExtraFieldsMessages) init(i []interface{}) {
@@ -46,6 +47,12 @@ func (e *ExtraFieldsMessages) init(i []interface{}) {
 			e.Attachments = v
 		}
 	}
+
+	if length > 8 {
+		if v, ok := i[8].(float64); ok {
+			e.RandomID = int(v)
+		}
+	}
 }
 
 type Attachments map[string]interface{}
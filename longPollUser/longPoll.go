@@ -6,6 +6,7 @@ import (
 	"go-vk-sdk/actor"
 	"go-vk-sdk/api"
 	internalErrors "go-vk-sdk/errors"
+	"go-vk-sdk/events"
 	"go-vk-sdk/logger"
 	"go-vk-sdk/request"
 	"sync/atomic"
@@ -114,6 +115,7 @@ func (l *LongPoll) Run(ctx context.Context) error {
 			switch FailedType(resp.Failed) {
 			case 0, FailedTypeOutdatedStory:
 				l.ts = resp.Ts
+				l.req.Ts(l.ts)
 			case FailedTypeExpiredKey:
 				err = l.UpdateServer(false)
 			case FailedTypeOutdatedUserInfo:
@@ -154,6 +156,30 @@ func (l *LongPoll) Updates() chan *EventUpdate {
 	return l.chanUpdate
 }
 
+// Serve calls the handler for every update until the context is done,
+// the errors and the panics of the handler are logged. The long poll must be run separately.
+// The updates are wrapped by NewUpdate, so the chain of the community long poll and the callback can be reused,
+// FromUpdate returns the update of the user long poll in the handler
+//
+//	go lp.Run(ctx)
+//	lp.Serve(ctx, events.Chain(handle, events.Logging()))
+func (l *LongPoll) Serve(ctx context.Context, handler events.Handler) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case update, ok := <-l.chanUpdate:
+			if !ok {
+				return nil
+			}
+
+			if err := handler.Call(ctx, NewUpdate(update)); err != nil {
+				logger.Log("LongPollUser.LongPoll.Serve()", "error handling event "+update.Type.String()+": "+err.Error())
+			}
+		}
+	}
+}
+
 func (l *LongPoll) SetServer(server *Server) error {
 	if server == nil || server.URL == "" || server.Key == "" {
 		return internalErrors.ErrorLog("LongPollUser.LongPoll.SetServer()", fmt.Sprintf("Invalid server configuration %+v", server))
@@ -0,0 +1,164 @@
+package longPollUser_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"go-vk-sdk/actor"
+	"go-vk-sdk/api"
+	"go-vk-sdk/events"
+	"go-vk-sdk/logger"
+	"go-vk-sdk/longPollUser"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServeRecovers(t *testing.T) {
+	lp := longPollUser.NewLongPoll(api.NewAPI(), &actor.User{ID: 1, AccessToken: "token"}, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	handled := make(chan longPollUser.EventType, 2)
+	served := make(chan error, 1)
+	go func() {
+		served <- lp.Serve(ctx, func(_ context.Context, update *events.Update) error {
+			userUpdate, ok := longPollUser.FromUpdate(update)
+			if !ok {
+				t.Errorf("the update %+v is not the update of the user long poll", update)
+				return nil
+			}
+
+			handled <- userUpdate.Type
+			if userUpdate.Type == longPollUser.EventTypeMessageNew {
+				panic("boom")
+			}
+			return nil
+		})
+	}()
+
+	// the update after the panic is handled
+	for _, kind := range []longPollUser.EventType{longPollUser.EventTypeMessageNew, longPollUser.EventTypeMessageEdit} {
+		lp.Updates() <- &longPollUser.EventUpdate{Type: kind}
+	}
+
+	for _, want := range []longPollUser.EventType{longPollUser.EventTypeMessageNew, longPollUser.EventTypeMessageEdit} {
+		select {
+		case got := <-handled:
+			if got != want {
+				t.Errorf("got event %d, want %d", got, want)
+			}
+		case <-ctx.Done():
+			t.Fatal("the update is not handled")
+		}
+	}
+
+	close(lp.Updates())
+
+	if err := <-served; err != nil {
+		t.Errorf("got error %v after the updates are closed", err)
+	}
+}
+
+func newMessage(flags longPollUser.MessageFlag, peerID int, from string) *events.Update {
+	event := &longPollUser.EventMessageNew{MessageID: 1, Flags: flags}
+	event.PeerID = peerID
+	event.AdditionalData.From = from
+
+	return longPollUser.NewUpdate(&longPollUser.EventUpdate{Type: longPollUser.EventTypeMessageNew, Event: event})
+}
+
+func TestUpdateFromID(t *testing.T) {
+	tests := []struct {
+		name   string
+		update *events.Update
+		fromID int
+		ok     bool
+	}{
+		{name: "personal message", update: newMessage(0, 5, ""), fromID: 5, ok: true},
+		{name: "chat message", update: newMessage(0, 2000000001, "7"), fromID: 7, ok: true},
+		{name: "outgoing message", update: newMessage(longPollUser.MessageFlagOutbox, 5, ""), ok: false},
+		{name: "community message", update: newMessage(0, -1, ""), ok: false},
+		{
+			name: "typing",
+			update: longPollUser.NewUpdate(&longPollUser.EventUpdate{
+				Type:  longPollUser.EventTypeUserTypingDialog,
+				Event: &longPollUser.EventUserTypingDialog{UserID: 9},
+			}),
+			fromID: 9,
+			ok:     true,
+		},
+		{
+			name: "friend online",
+			update: longPollUser.NewUpdate(&longPollUser.EventUpdate{
+				Type:  longPollUser.EventTypeFriendOnline,
+				Event: &longPollUser.EventFriendOnline{UserID: 9},
+			}),
+			ok: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fromID, ok := events.FromID(test.update.Event)
+			if fromID != test.fromID || ok != test.ok {
+				t.Errorf("got %d %t, want %d %t", fromID, ok, test.fromID, test.ok)
+			}
+		})
+	}
+
+	if update := newMessage(0, 5, ""); update.Type != "user_message_new" {
+		t.Errorf("got type %s", update.Type)
+	}
+
+	if _, ok := longPollUser.FromUpdate(&events.Update{Type: events.EventTypeMessageNew, Event: &events.EventMessageNew{}}); ok {
+		t.Error("the update of the community is returned as the update of the user long poll")
+	}
+}
+
+func TestChainAcrossSources(t *testing.T) {
+	var out bytes.Buffer
+	logger.SetOutput(&out)
+	logger.Enable()
+	t.Cleanup(func() {
+		logger.Disable()
+		logger.SetOutput(os.Stdout)
+	})
+
+	var handled []events.EventType
+	handler := events.Chain(func(_ context.Context, update *events.Update) error {
+		handled = append(handled, update.Type)
+		if _, ok := longPollUser.FromUpdate(update); ok {
+			panic("boom")
+		}
+		return nil
+	}, events.Logging(), events.FilterFromIDs(5), events.Recover())
+
+	community := &events.EventMessageNew{}
+	community.Message.FromID = 5
+
+	// the same chain handles the community update and the update of the user long poll
+	if err := handler(context.Background(), &events.Update{Type: events.EventTypeMessageNew, GroupID: 1, Event: community}); err != nil {
+		t.Fatal(err)
+	}
+
+	var panicError *events.PanicError
+	if err := handler(context.Background(), newMessage(0, 5, "")); !errors.As(err, &panicError) {
+		t.Errorf("got error %v, want *events.PanicError", err)
+	}
+
+	// the message of the other author is skipped by the filter
+	if err := handler(context.Background(), newMessage(0, 6, "")); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(handled) != 2 || handled[0] != events.EventTypeMessageNew || handled[1] != "user_message_new" {
+		t.Errorf("got handled %v", handled)
+	}
+
+	if log := out.String(); !strings.Contains(log, "type=user_message_new event_id= group_id=0 from_id=5") {
+		t.Errorf("log %q does not contain the update of the user long poll", log)
+	}
+}
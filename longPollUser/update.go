@@ -0,0 +1,83 @@
+package longPollUser
+
+import (
+	"go-vk-sdk/events"
+	"strconv"
+)
+
+// UpdateTypePrefix prefix of the type of events.Update of the user long poll, for example user_message_new,
+// so the events of the user long poll do not match the events of the community with the same names
+const UpdateTypePrefix = "user_"
+
+// chatPeerIDOffset peer_id of the chat is 2000000000 + chat ID
+const chatPeerIDOffset = 2000000000
+
+// UserEvent Event of the user long poll in events.Update, so the updates of the user long poll and of the community
+// are handled by one chain of the middlewares of the events package
+type UserEvent struct {
+	Update *EventUpdate
+}
+
+// EventType returns the type of the update with UpdateTypePrefix, for example user_message_new
+func (e *UserEvent) EventType() events.EventType {
+	return events.EventType(UpdateTypePrefix + e.Update.Type.String())
+}
+
+// AuthorID returns the sender of the incoming message and the user who is typing, it is used by events.FromID.
+// The outgoing messages and the messages of the communities have no author
+func (e *UserEvent) AuthorID() (int, bool) {
+	switch event := e.Update.Event.(type) {
+	case *EventMessageNew:
+		return messageAuthorID(event.Flags, event.PeerID, event.AdditionalData)
+	case *EventMessageEdit:
+		return messageAuthorID(event.Flags, event.PeerID, event.AdditionalData)
+	case *EventUserTypingDialog:
+		return event.UserID, true
+	case *EventUserTypingChat:
+		return event.UserID, true
+	}
+
+	return 0, false
+}
+
+// NewUpdate wraps the update of the user long poll into events.Update with the event *UserEvent
+func NewUpdate(update *EventUpdate) *events.Update {
+	event := &UserEvent{Update: update}
+
+	return &events.Update{Type: event.EventType(), Event: event}
+}
+
+// FromUpdate returns the update of the user long poll wrapped by NewUpdate, ok is false for the updates of the other sources
+//
+//	handler := events.Chain(func(ctx context.Context, update *events.Update) error {
+//		if userUpdate, ok := longPollUser.FromUpdate(update); ok {
+//			...
+//		}
+//		return nil
+//	}, events.Logging(), events.Recover())
+func FromUpdate(update *events.Update) (*EventUpdate, bool) {
+	if update == nil {
+		return nil, false
+	}
+
+	event, ok := update.Event.(*UserEvent)
+	if !ok || event.Update == nil {
+		return nil, false
+	}
+
+	return event.Update, true
+}
+
+// messageAuthorID returns the sender of the chat message from its additional data and the peer of the incoming personal message
+func messageAuthorID(flags MessageFlag, peerID int, data AdditionalData) (int, bool) {
+	if data.From != "" {
+		id, err := strconv.Atoi(data.From)
+		return id, err == nil
+	}
+
+	if flags.Has(MessageFlagOutbox) || peerID <= 0 || peerID >= chatPeerIDOffset {
+		return 0, false
+	}
+
+	return peerID, true
+}
@@ -0,0 +1,295 @@
+package markup
+
+import (
+	"go-vk-sdk/constants"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Doc: https://dev.vk.com/ru/api/bots/development/messages
+
+type TokenType int
+
+const (
+	TokenText       TokenType = iota
+	TokenMention              // [id1|Name], [club1|Name], @id1 (Name) or *club1, the id is known
+	TokenScreenName           // @durov, the id is filled by Resolver
+	TokenLink                 // https://example.com or vk.com/durov, the id of vk.com/{screen_name} is filled by Resolver
+	TokenHashtag              // #news or #news@apiclub
+)
+
+func (t TokenType) String() string {
+	switch t {
+	case TokenText:
+		return "text"
+	case TokenMention:
+		return "mention"
+	case TokenScreenName:
+		return "screen_name"
+	case TokenLink:
+		return "link"
+	case TokenHashtag:
+		return "hashtag"
+	}
+
+	return "TokenType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// Token Part of the text, Start and End are the byte offsets of Raw in the text
+type Token struct {
+	Type  TokenType
+	Raw   string
+	Start int
+	End   int
+
+	Kind       string // constants.PeerUser or constants.PeerGroup, empty until resolved
+	ID         int    // positive id of the user or the community, 0 until resolved
+	ScreenName string // @{screen_name} and vk.com/{screen_name}
+	Name       string // displayed name of the mention
+	URL        string // only for links, the scheme is added if missing
+	Hashtag    string // without #
+	Domain     string // community of the hashtag #{hashtag}@{domain}
+}
+
+// PeerID returns the id of the user or the negative id of the community, 0 if the token is not resolved
+func (t Token) PeerID() int {
+	switch t.Kind {
+	case constants.PeerUser:
+		return t.ID
+	case constants.PeerGroup:
+		return -t.ID
+	}
+
+	return 0
+}
+
+// IsResolved reports whether the id of the mentioned user or community is known
+func (t Token) IsResolved() bool {
+	return t.Kind != "" && t.ID > 0
+}
+
+var (
+	bracketMentionPattern = regexp.MustCompile(`^\[(id|club|public|event)(\d+)\|([^\]\n]*)\]`)
+	atMentionPattern      = regexp.MustCompile(`^[@*]([A-Za-z0-9_.]{2,32})(?:[ \t]?\(([^)\n]*)\))?`)
+	linkPattern           = regexp.MustCompile(`^(?i:(?:https?://)[^\s<>"]+|(?:(?:m|www)\.)?vk\.(?:com|ru|me)/[^\s<>"]*)`)
+	hashtagPattern        = regexp.MustCompile(`^#([\p{L}\p{N}_]+)(?:@([A-Za-z0-9_.]+))?`)
+	screenNamePattern     = regexp.MustCompile(`^[A-Za-z0-9_.]{2,32}$`)
+	objectScreenName      = regexp.MustCompile(`^(id|club|public|event)(\d+)$`)
+)
+
+// Tokenize splits the text into mentions, links, hashtags and the plain text between them.
+// Concatenated Raw of the tokens is the text.
+//
+//	Mentions and screen names must not follow a letter or a digit, so e-mails are kept as the text.
+//	The asterisk starts the mention only with the id (*id1, *club1), so "*note: x" is kept as the text.
+func Tokenize(text string) []Token {
+	var tokens []Token
+
+	plain := 0
+
+	for i := 0; i < len(text); {
+		token, ok := tokenAt(text, i)
+		if !ok {
+			_, size := utf8.DecodeRuneInString(text[i:])
+			i += size
+			continue
+		}
+
+		if plain < i {
+			tokens = append(tokens, Token{Type: TokenText, Raw: text[plain:i], Start: plain, End: i})
+		}
+
+		tokens = append(tokens, token)
+		i, plain = token.End, token.End
+	}
+
+	if plain < len(text) {
+		tokens = append(tokens, Token{Type: TokenText, Raw: text[plain:], Start: plain, End: len(text)})
+	}
+
+	return tokens
+}
+
+// Mentions returns the mentions and the screen names of the text
+func Mentions(text string) []Token {
+	var mentions []Token
+
+	for _, token := range Tokenize(text) {
+		if token.Type == TokenMention || token.Type == TokenScreenName {
+			mentions = append(mentions, token)
+		}
+	}
+
+	return mentions
+}
+
+// tokenAt returns the token starting at the byte offset i
+func tokenAt(text string, i int) (Token, bool) {
+	rest := text[i:]
+
+	switch rest[0] {
+	case '[':
+		match := bracketMentionPattern.FindStringSubmatch(rest)
+		if match == nil {
+			return Token{}, false
+		}
+
+		token := newToken(TokenMention, text, i, len(match[0]))
+		token.Kind, token.ID = objectKind(match[1], match[2])
+		token.Name = match[3]
+
+		return token, true
+	case '@', '*':
+		if afterWord(text, i) {
+			return Token{}, false
+		}
+
+		match := atMentionPattern.FindStringSubmatch(rest)
+		if match == nil {
+			return Token{}, false
+		}
+
+		// the trailing dots belong to the sentence
+		name := strings.TrimRight(match[1], ".")
+		length := len(match[0])
+		if match[2] == "" && name != match[1] {
+			length = 1 + len(name)
+		}
+
+		token := newToken(TokenScreenName, text, i, length)
+		token.ScreenName = name
+		token.Name = strings.TrimSpace(match[2])
+
+		kind, id := objectKind(objectScreenNameParts(name))
+		if id > 0 {
+			token.Type, token.Kind, token.ID, token.ScreenName = TokenMention, kind, id, ""
+		} else if rest[0] == '*' {
+			return Token{}, false
+		}
+
+		return token, true
+	case '#':
+		if afterWord(text, i) {
+			return Token{}, false
+		}
+
+		match := hashtagPattern.FindStringSubmatch(rest)
+		if match == nil {
+			return Token{}, false
+		}
+
+		token := newToken(TokenHashtag, text, i, len(match[0]))
+		token.Hashtag, token.Domain = match[1], match[2]
+
+		return token, true
+	}
+
+	// links start with http, vk, m.vk or www.vk
+	if strings.IndexByte("hHvVmMwW", rest[0]) < 0 || afterWord(text, i) {
+		return Token{}, false
+	}
+
+	match := linkPattern.FindString(rest)
+	if match == "" {
+		return Token{}, false
+	}
+
+	match = trimLink(match)
+
+	token := newToken(TokenLink, text, i, len(match))
+	token.URL = match
+	if !strings.Contains(strings.ToLower(match), "://") {
+		token.URL = "https://" + match
+	}
+
+	token.ScreenName = vkScreenName(match)
+	if kind, id := objectKind(objectScreenNameParts(token.ScreenName)); id > 0 {
+		token.Kind, token.ID, token.ScreenName = kind, id, ""
+	}
+
+	return token, true
+}
+
+func newToken(kind TokenType, text string, start, length int) Token {
+	return Token{Type: kind, Raw: text[start : start+length], Start: start, End: start + length}
+}
+
+// afterWord reports whether the byte offset i follows a letter, a digit or the underscore
+func afterWord(text string, i int) bool {
+	if i == 0 {
+		return false
+	}
+
+	r, _ := utf8.DecodeLastRuneInString(text[:i])
+
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// trimLink removes the punctuation of the sentence and the unpaired closing brackets from the end of the link
+func trimLink(link string) string {
+	for len(link) > 0 {
+		last := link[len(link)-1]
+
+		switch {
+		case strings.IndexByte(".,:;!?'", last) >= 0:
+			link = link[:len(link)-1]
+		case last == ')' && strings.Count(link, "(") < strings.Count(link, ")"):
+			link = link[:len(link)-1]
+		default:
+			return link
+		}
+	}
+
+	return link
+}
+
+// vkScreenName returns the screen name of the link vk.com/{screen_name}, empty for the other links
+func vkScreenName(link string) string {
+	lower := strings.ToLower(link)
+	if i := strings.Index(lower, "://"); i >= 0 {
+		link, lower = link[i+3:], lower[i+3:]
+	}
+
+	lower = strings.TrimPrefix(strings.TrimPrefix(lower, "m."), "www.")
+	if !strings.HasPrefix(lower, "vk.com/") && !strings.HasPrefix(lower, "vk.ru/") {
+		return ""
+	}
+
+	_, path, _ := strings.Cut(link, "/")
+	path = strings.TrimSuffix(path, "/")
+
+	if !screenNamePattern.MatchString(path) {
+		return ""
+	}
+
+	return path
+}
+
+// objectScreenNameParts splits id1, club1, public1 and event1 into the prefix and the id
+func objectScreenNameParts(screenName string) (string, string) {
+	match := objectScreenName.FindStringSubmatch(screenName)
+	if match == nil {
+		return "", ""
+	}
+
+	return match[1], match[2]
+}
+
+func objectKind(prefix, id string) (string, int) {
+	n, err := strconv.Atoi(id)
+	if err != nil || n <= 0 {
+		return "", 0
+	}
+
+	switch prefix {
+	case "id":
+		return constants.PeerUser, n
+	case "club", "public", "event":
+		return constants.PeerGroup, n
+	}
+
+	return "", 0
+}
@@ -0,0 +1,105 @@
+package markup_test
+
+import (
+	"go-vk-sdk/constants"
+	"go-vk-sdk/markup"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	text := "Привет, [id1|Павел] и @apiclub. Пиши *id2 (Коле), смотри vk.com/durov, https://example.com/a_(b)). #news@apiclub"
+
+	tokens := markup.Tokenize(text)
+
+	var raw strings.Builder
+	for _, token := range tokens {
+		if text[token.Start:token.End] != token.Raw {
+			t.Errorf("token %q does not match the offsets %d-%d", token.Raw, token.Start, token.End)
+		}
+		raw.WriteString(token.Raw)
+	}
+
+	if raw.String() != text {
+		t.Errorf("concatenated tokens %q, want %q", raw.String(), text)
+	}
+
+	want := []markup.Token{
+		{Type: markup.TokenMention, Raw: "[id1|Павел]", Kind: constants.PeerUser, ID: 1, Name: "Павел"},
+		{Type: markup.TokenScreenName, Raw: "@apiclub", ScreenName: "apiclub"},
+		{Type: markup.TokenMention, Raw: "*id2 (Коле)", Kind: constants.PeerUser, ID: 2, Name: "Коле"},
+		{Type: markup.TokenLink, Raw: "vk.com/durov", URL: "https://vk.com/durov", ScreenName: "durov"},
+		{Type: markup.TokenLink, Raw: "https://example.com/a_(b)", URL: "https://example.com/a_(b)"},
+		{Type: markup.TokenHashtag, Raw: "#news@apiclub", Hashtag: "news", Domain: "apiclub"},
+	}
+
+	var got []markup.Token
+	for _, token := range tokens {
+		if token.Type != markup.TokenText {
+			token.Start, token.End = 0, 0
+			got = append(got, token)
+		}
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got tokens %+v, want %+v", got, want)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("token %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTokenizeText(t *testing.T) {
+	// e-mails, the asterisks without the id and the single characters are not the mentions
+	for _, text := range []string{
+		"mail@example.ru",
+		"*note: x",
+		"*apiclub",
+		"**bold**",
+		"@a",
+		"C#1",
+	} {
+		tokens := markup.Tokenize(text)
+		if len(tokens) != 1 || tokens[0].Type != markup.TokenText {
+			t.Errorf("%q: got tokens %+v", text, tokens)
+		}
+	}
+}
+
+func TestTokenizeTrailingDot(t *testing.T) {
+	tokens := markup.Tokenize("Спасибо @durov.")
+	if len(tokens) != 3 || tokens[1].Raw != "@durov" || tokens[2].Raw != "." {
+		t.Errorf("got tokens %+v", tokens)
+	}
+}
+
+func TestBotStripMention(t *testing.T) {
+	bot := markup.Bot{GroupID: 1, ScreenName: "apiclub"}
+
+	for _, text := range []string{"[club1|@apiclub], /start 42", "@apiclub: /start 42", "*club1 /start 42"} {
+		rest, ok := bot.StripMention(text)
+		if !ok || rest != "/start 42" {
+			t.Errorf("%q: got %q and %v", text, rest, ok)
+		}
+	}
+
+	if _, ok := bot.StripMention("[club2|@other] /start"); ok {
+		t.Error("the mention of the other community is stripped")
+	}
+}
+
+func TestRender(t *testing.T) {
+	tokens := markup.Tokenize("Привет, @durov и @unknown")
+	tokens[1].Kind, tokens[1].ID = constants.PeerUser, 1
+
+	if text := markup.Render(tokens); text != "Привет, [id1|durov] и @unknown" {
+		t.Errorf("got %q", text)
+	}
+
+	if name := markup.Sanitize("a]|b[c"); strings.ContainsAny(name, "[]|") {
+		t.Errorf("sanitized name %q keeps the markup", name)
+	}
+}
@@ -0,0 +1,59 @@
+package markup
+
+import (
+	"go-vk-sdk/constants"
+	"strings"
+	"unicode"
+)
+
+// Bot Community of the bot, ScreenName is optional and matches @{screen_name} which is not resolved yet
+type Bot struct {
+	GroupID    int
+	ScreenName string
+}
+
+// IsMentioned reports whether the text mentions the bot
+func (bot Bot) IsMentioned(text string) bool {
+	for _, token := range Mentions(text) {
+		if bot.matches(token) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// StripMention removes the mention of the bot at the beginning of the text with the following comma or colon,
+// "[club1|@bot], /start 42" becomes "/start 42". Chats prepend the mention when the bot is addressed
+func (bot Bot) StripMention(text string) (string, bool) {
+	rest := strings.TrimLeftFunc(text, unicode.IsSpace)
+
+	tokens := Tokenize(rest)
+	if len(tokens) == 0 || !bot.matches(tokens[0]) {
+		return text, false
+	}
+
+	rest = strings.TrimLeftFunc(rest[tokens[0].End:], unicode.IsSpace)
+	rest = strings.TrimPrefix(strings.TrimPrefix(rest, ","), ":")
+
+	return strings.TrimLeftFunc(rest, unicode.IsSpace), true
+}
+
+// StripBotMention removes the mention of the community at the beginning of the text, see Bot.StripMention
+func StripBotMention(text string, groupID int) (string, bool) {
+	return Bot{GroupID: groupID}.StripMention(text)
+}
+
+func (bot Bot) matches(token Token) bool {
+	switch token.Type {
+	case TokenMention, TokenScreenName:
+	default:
+		return false
+	}
+
+	if token.IsResolved() {
+		return token.Kind == constants.PeerGroup && token.ID == bot.GroupID
+	}
+
+	return bot.ScreenName != "" && strings.EqualFold(token.ScreenName, bot.ScreenName)
+}
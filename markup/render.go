@@ -0,0 +1,115 @@
+package markup
+
+import (
+	"go-vk-sdk/constants"
+	"go-vk-sdk/objects"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// UserMention returns the mention [id{id}|{name}], the name is sanitized so it cannot break the markup,
+// the empty name is replaced by id{id}
+func UserMention(id int, name string) string {
+	return mention("id", id, name)
+}
+
+// GroupMention returns the mention [club{id}|{name}], the negative id of the community is accepted
+func GroupMention(id int, name string) string {
+	if id < 0 {
+		id = -id
+	}
+
+	return mention("club", id, name)
+}
+
+// PeerMention returns the mention of the user or the community by the peer id, the community has the negative id
+func PeerMention(peerID int, name string) string {
+	if peerID < 0 {
+		return GroupMention(peerID, name)
+	}
+
+	return UserMention(peerID, name)
+}
+
+// User returns the mention of the user with the first and the last name
+func User(user *objects.UserFull) string {
+	return UserMention(user.ID, user.FirstName+" "+user.LastName)
+}
+
+// UserMin returns the mention of the user with the first and the last name
+func UserMin(user *objects.User) string {
+	return UserMention(user.ID, user.FirstName+" "+user.LastName)
+}
+
+// Group returns the mention of the community with its name
+func Group(group *objects.GroupFull) string {
+	return GroupMention(group.ID, group.Name)
+}
+
+// Render returns the text of the tokens, the mentions are rendered as [id{id}|{name}] and [club{id}|{name}],
+// the resolved screen names keep the screen name as the name if there is no name
+func Render(tokens []Token) string {
+	var b strings.Builder
+
+	for _, token := range tokens {
+		if token.Type != TokenMention && token.Type != TokenScreenName || !token.IsResolved() {
+			b.WriteString(token.Raw)
+			continue
+		}
+
+		name := token.Name
+		if name == "" {
+			name = token.ScreenName
+		}
+
+		if token.Kind == constants.PeerGroup {
+			b.WriteString(GroupMention(token.ID, name))
+		} else {
+			b.WriteString(UserMention(token.ID, name))
+		}
+	}
+
+	return b.String()
+}
+
+// Plain returns the text with the mentions replaced by their names, for example for the logs or the search
+func Plain(text string) string {
+	var b strings.Builder
+
+	for _, token := range Tokenize(text) {
+		if token.Type == TokenMention && token.Name != "" {
+			b.WriteString(token.Name)
+		} else {
+			b.WriteString(token.Raw)
+		}
+	}
+
+	return b.String()
+}
+
+// Sanitize removes the characters which break the mention markup from the name
+func Sanitize(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r == '[' || r == ']' || r == '|':
+			return -1
+		case unicode.IsSpace(r):
+			return ' '
+		case unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, name)
+
+	return strings.Join(strings.Fields(name), " ")
+}
+
+func mention(prefix string, id int, name string) string {
+	name = Sanitize(name)
+	if name == "" {
+		name = prefix + strconv.Itoa(id)
+	}
+
+	return "[" + prefix + strconv.Itoa(id) + "|" + name + "]"
+}
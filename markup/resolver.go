@@ -0,0 +1,159 @@
+package markup
+
+import (
+	"context"
+	"go-vk-sdk/actor"
+	"go-vk-sdk/api"
+	"go-vk-sdk/constants"
+	"go-vk-sdk/request"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Doc: https://dev.vk.com/ru/method/utils.resolveScreenName
+
+const (
+	ResolverCacheSize = 10000     // default number of the screen names remembered by the resolver
+	ResolverCacheTTL  = time.Hour // default time the screen name is remembered by the resolver
+)
+
+// Resolver Resolves the screen names of the tokens to the ids by utils.resolveScreenName.
+// The results, including the unknown screen names, are cached for ResolverCacheTTL,
+// the oldest results are forgotten when the cache is full. The resolver is safe for concurrent use.
+type Resolver struct {
+	api   *api.API
+	actor actor.Actor
+
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	cache map[string]resolvedScreenName
+	order []resolvedKey // keys of the cache in the order they were added
+}
+
+type resolvedScreenName struct {
+	kind string
+	id   int
+	time time.Time
+}
+
+type resolvedKey struct {
+	key  string
+	time time.Time
+}
+
+func NewResolver(a *api.API, actor actor.Actor) *Resolver {
+	return &Resolver{
+		api:   a,
+		actor: actor,
+		size:  ResolverCacheSize,
+		ttl:   ResolverCacheTTL,
+		cache: map[string]resolvedScreenName{},
+	}
+}
+
+// SetCacheSize size > 0, otherwise ResolverCacheSize is used
+func (r *Resolver) SetCacheSize(size int) *Resolver {
+	if size <= 0 {
+		size = ResolverCacheSize
+	}
+
+	r.mu.Lock()
+	r.size = size
+	r.expire(time.Now())
+	r.mu.Unlock()
+
+	return r
+}
+
+// SetCacheTTL ttl > 0, otherwise ResolverCacheTTL is used
+func (r *Resolver) SetCacheTTL(ttl time.Duration) *Resolver {
+	if ttl <= 0 {
+		ttl = ResolverCacheTTL
+	}
+
+	r.mu.Lock()
+	r.ttl = ttl
+	r.expire(time.Now())
+	r.mu.Unlock()
+
+	return r
+}
+
+// Resolve fills Kind and ID of the screen names and the links vk.com/{screen_name},
+// the screen names of the applications and the unknown screen names stay unresolved
+func (r *Resolver) Resolve(ctx context.Context, tokens []Token) error {
+	for i := range tokens {
+		token := &tokens[i]
+		if token.ScreenName == "" || token.IsResolved() {
+			continue
+		}
+
+		kind, id, err := r.ResolveScreenName(ctx, token.ScreenName)
+		if err != nil {
+			return err
+		}
+
+		token.Kind, token.ID = kind, id
+	}
+
+	return nil
+}
+
+// ResolveScreenName returns constants.PeerUser or constants.PeerGroup and the id of the screen name,
+// empty kind and 0 if the screen name does not belong to the user or the community
+func (r *Resolver) ResolveScreenName(ctx context.Context, screenName string) (string, int, error) {
+	key := strings.ToLower(screenName)
+
+	if kind, id := objectKind(objectScreenNameParts(key)); id > 0 {
+		return kind, id, nil
+	}
+
+	r.mu.Lock()
+	r.expire(time.Now())
+	resolved, ok := r.cache[key]
+	r.mu.Unlock()
+
+	if ok {
+		return resolved.kind, resolved.id, nil
+	}
+
+	resp, err := request.NewUtilsResolveScreenNameRequest(r.api, r.actor).
+		ScreenName(screenName).
+		Exec(ctx)
+	if err != nil {
+		return "", 0, err
+	}
+
+	switch resp.Response.Type {
+	case "user":
+		resolved = resolvedScreenName{kind: constants.PeerUser, id: resp.Response.ObjectID}
+	case "group", "page", "event":
+		resolved = resolvedScreenName{kind: constants.PeerGroup, id: resp.Response.ObjectID}
+	}
+
+	resolved.time = time.Now()
+
+	r.mu.Lock()
+	r.cache[key] = resolved
+	r.order = append(r.order, resolvedKey{key: key, time: resolved.time})
+	r.expire(resolved.time)
+	r.mu.Unlock()
+
+	return resolved.kind, resolved.id, nil
+}
+
+// expire must be called with the lock held, it forgets the expired results and the oldest results above the size
+func (r *Resolver) expire(now time.Time) {
+	n := 0
+	for n < len(r.order) && (len(r.cache) > r.size || now.Sub(r.order[n].time) >= r.ttl) {
+		// the key added again later keeps its newer result
+		if entry := r.order[n]; r.cache[entry.key].time.Equal(entry.time) {
+			delete(r.cache, entry.key)
+		}
+		n++
+	}
+
+	r.order = r.order[n:]
+}
@@ -0,0 +1,110 @@
+package markup_test
+
+import (
+	"context"
+	"go-vk-sdk/actor"
+	"go-vk-sdk/constants"
+	"go-vk-sdk/markup"
+	"go-vk-sdk/vktest"
+	"testing"
+	"time"
+)
+
+func newResolver(t *testing.T) (*vktest.Server, *markup.Resolver) {
+	t.Helper()
+
+	s := vktest.NewServer()
+	t.Cleanup(s.Close)
+
+	s.Handle("utils.resolveScreenName", func(call *vktest.Call) (interface{}, error) {
+		switch call.Get("screen_name") {
+		case "durov":
+			return map[string]interface{}{"type": "user", "object_id": 1}, nil
+		case "apiclub":
+			return map[string]interface{}{"type": "group", "object_id": 1}, nil
+		}
+
+		return []interface{}{}, nil
+	})
+
+	return s, markup.NewResolver(s.API(), &actor.Group{ID: 1, AccessToken: "token"})
+}
+
+func TestResolver(t *testing.T) {
+	s, r := newResolver(t)
+
+	tokens := markup.Tokenize("@durov, @apiclub, @unknown, vk.com/Durov и [id2|Коля]")
+	if err := r.Resolve(context.Background(), tokens); err != nil {
+		t.Fatal(err)
+	}
+
+	var peers []int
+	for _, token := range tokens {
+		if token.Type != markup.TokenText {
+			peers = append(peers, token.PeerID())
+		}
+	}
+
+	want := []int{1, -1, 0, 1, 2}
+	if len(peers) != len(want) {
+		t.Fatalf("got peers %v, want %v", peers, want)
+	}
+
+	for i := range want {
+		if peers[i] != want[i] {
+			t.Errorf("got peers %v, want %v", peers, want)
+			break
+		}
+	}
+
+	// vk.com/Durov is cached as durov, [id2|Коля] is not resolved by the request
+	if calls := len(s.CallsOf("utils.resolveScreenName")); calls != 3 {
+		t.Errorf("got %d calls, want 3", calls)
+	}
+}
+
+func TestResolverCacheSize(t *testing.T) {
+	s, r := newResolver(t)
+	r.SetCacheSize(1)
+
+	for _, name := range []string{"durov", "apiclub", "durov"} {
+		if _, _, err := r.ResolveScreenName(context.Background(), name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// durov is forgotten when apiclub is added
+	if calls := len(s.CallsOf("utils.resolveScreenName")); calls != 3 {
+		t.Errorf("got %d calls, want 3", calls)
+	}
+}
+
+func TestResolverCacheTTL(t *testing.T) {
+	s, r := newResolver(t)
+	r.SetCacheTTL(10 * time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		kind, id, err := r.ResolveScreenName(context.Background(), "unknown")
+		if err != nil || kind != "" || id != 0 {
+			t.Fatalf("got %q, %d and error %v", kind, id, err)
+		}
+	}
+
+	if calls := len(s.CallsOf("utils.resolveScreenName")); calls != 1 {
+		t.Errorf("got %d calls before the expiration, want 1", calls)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if kind, id, _ := r.ResolveScreenName(context.Background(), "durov"); kind != constants.PeerUser || id != 1 {
+		t.Errorf("got %q and %d", kind, id)
+	}
+
+	if _, _, err := r.ResolveScreenName(context.Background(), "unknown"); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls := len(s.CallsOf("utils.resolveScreenName")); calls != 3 {
+		t.Errorf("got %d calls after the expiration, want 3", calls)
+	}
+}
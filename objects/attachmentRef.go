@@ -0,0 +1,269 @@
+package objects
+
+import (
+	"go-vk-sdk/constants"
+	internalErrors "go-vk-sdk/errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// attachmentRefTypes types of the objects which can be referenced by {type}{owner_id}_{id}
+var attachmentRefTypes = map[string]bool{
+	constants.AttachmentTypePhoto:         true,
+	constants.AttachmentTypeVideo:         true,
+	constants.AttachmentTypeAudio:         true,
+	constants.AttachmentTypeDoc:           true,
+	constants.AttachmentTypeWall:          true,
+	constants.AttachmentTypeMarket:        true,
+	constants.AttachmentTypeMarketAlbum:   true,
+	constants.AttachmentTypePoll:          true,
+	constants.AttachmentTypeStory:         true,
+	constants.AttachmentTypeNote:          true,
+	constants.AttachmentTypePage:          true,
+	constants.AttachmentTypeAlbum:         true,
+	constants.AttachmentTypePodcast:       true,
+	constants.AttachmentTypeAudioPlaylist: true,
+	constants.AttachmentTypeClip:          true,
+}
+
+var attachmentRefPattern = regexp.MustCompile(`^([a-z_]+?)(-?\d+)_(\d+)(?:_([0-9A-Za-z]+))?$`)
+
+// AttachmentRef Reference to the object in the format {type}{owner_id}_{id} or {type}{owner_id}_{id}_{access_key},
+// used by the parameter attachment of messages.send and attachments of wall.post.
+// The link is referenced by its URL.
+//
+//	Doc: https://dev.vk.com/ru/method/messages.send
+type AttachmentRef struct {
+	Type      string
+	OwnerID   int
+	ID        int
+	AccessKey string
+	URL       string // only for the type link
+}
+
+func NewAttachmentRef(kind string, ownerID, id int, accessKey string) AttachmentRef {
+	return AttachmentRef{Type: kind, OwnerID: ownerID, ID: id, AccessKey: accessKey}
+}
+
+func NewAttachmentRefLink(url string) AttachmentRef {
+	return AttachmentRef{Type: constants.AttachmentTypeLink, URL: url}
+}
+
+// ParseAttachmentRef parses photo-123_456_accesskey, doc1_2, wall-1_5 or the URL of the link
+func ParseAttachmentRef(s string) (AttachmentRef, error) {
+	s = strings.TrimSpace(s)
+
+	if strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") {
+		ref := NewAttachmentRefLink(s)
+		return ref, ref.Validate()
+	}
+
+	match := attachmentRefPattern.FindStringSubmatch(s)
+	if match == nil {
+		return AttachmentRef{}, internalErrors.Error("Objects.ParseAttachmentRef()", "invalid attachment "+s+", expected {type}{owner_id}_{id}")
+	}
+
+	ownerID, err := strconv.Atoi(match[2])
+	if err != nil {
+		return AttachmentRef{}, internalErrors.Error("Objects.ParseAttachmentRef()", "invalid owner_id of the attachment "+s)
+	}
+
+	id, err := strconv.Atoi(match[3])
+	if err != nil {
+		return AttachmentRef{}, internalErrors.Error("Objects.ParseAttachmentRef()", "invalid id of the attachment "+s)
+	}
+
+	ref := NewAttachmentRef(match[1], ownerID, id, match[4])
+
+	return ref, ref.Validate()
+}
+
+// ParseAttachmentRefs parses the comma-separated list of the attachments
+func ParseAttachmentRefs(s string) ([]AttachmentRef, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(s, ",")
+	refs := make([]AttachmentRef, 0, len(parts))
+
+	for _, part := range parts {
+		ref, err := ParseAttachmentRef(part)
+		if err != nil {
+			return nil, err
+		}
+
+		refs = append(refs, ref)
+	}
+
+	return refs, nil
+}
+
+// Validate checks the type, the owner, the id and the access key of the reference
+func (ref AttachmentRef) Validate() error {
+	if ref.Type == constants.AttachmentTypeLink {
+		if !strings.HasPrefix(ref.URL, "http://") && !strings.HasPrefix(ref.URL, "https://") {
+			return internalErrors.Error("Objects.AttachmentRef.Validate()", "invalid URL of the link "+ref.URL)
+		}
+
+		return nil
+	}
+
+	if !attachmentRefTypes[ref.Type] {
+		return internalErrors.Error("Objects.AttachmentRef.Validate()", "unsupported type of the attachment "+ref.Type)
+	}
+
+	if ref.OwnerID == 0 {
+		return internalErrors.Error("Objects.AttachmentRef.Validate()", "owner_id of the attachment "+ref.Type+" is 0")
+	}
+
+	if ref.ID <= 0 {
+		return internalErrors.Error("Objects.AttachmentRef.Validate()", "invalid id of the attachment "+ref.Type+" "+strconv.Itoa(ref.ID))
+	}
+
+	for _, c := range ref.AccessKey {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+			return internalErrors.Error("Objects.AttachmentRef.Validate()", "invalid access_key of the attachment "+ref.AccessKey)
+		}
+	}
+
+	return nil
+}
+
+// ToAttachment returns the reference in the format of the parameter attachment, empty for the zero reference
+func (ref AttachmentRef) ToAttachment() string {
+	switch ref.Type {
+	case "":
+		return ""
+	case constants.AttachmentTypeLink:
+		return ref.URL
+	}
+
+	return attachmentString(ref.Type, ref.OwnerID, ref.ID, ref.AccessKey)
+}
+
+func (ref AttachmentRef) String() string {
+	return ref.ToAttachment()
+}
+
+// IsZero reports whether the reference is empty
+func (ref AttachmentRef) IsZero() bool {
+	return ref == AttachmentRef{}
+}
+
+// Ref returns the reference to the attachment of the message, voice messages and graffiti are referenced as documents.
+// Stickers, gifts, calls and comments of the wall cannot be attached and are returned as an error
+func (a *MessagesMessageAttachment) Ref() (AttachmentRef, error) {
+	var ref AttachmentRef
+
+	switch a.Type {
+	case constants.AttachmentTypePhoto:
+		return a.Photo.Ref()
+	case constants.AttachmentTypeVideo:
+		return a.Video.Ref()
+	case constants.AttachmentTypeAudio:
+		return a.Audio.Ref()
+	case constants.AttachmentTypeDoc:
+		return a.Doc.Ref()
+	case constants.AttachmentTypeAudioMessage:
+		ref = NewAttachmentRef(constants.AttachmentTypeDoc, a.AudioMessage.OwnerID, a.AudioMessage.ID, a.AudioMessage.AccessKey)
+	case constants.AttachmentTypeGraffiti:
+		ref = NewAttachmentRef(constants.AttachmentTypeDoc, a.Graffiti.OwnerID, a.Graffiti.ID, a.Graffiti.AccessKey)
+	case constants.AttachmentTypeLink:
+		ref = NewAttachmentRefLink(a.Link.URL)
+	case constants.AttachmentTypeMarket:
+		ref = NewAttachmentRef(a.Type, a.Market.OwnerID, a.Market.ID, a.Market.AccessKey)
+	case constants.AttachmentTypeMarketAlbum:
+		ref = NewAttachmentRef(a.Type, a.MarketMarketAlbum.OwnerID, a.MarketMarketAlbum.ID, "")
+	case constants.AttachmentTypeWall:
+		ref = NewAttachmentRef(a.Type, wallpostOwnerID(&a.Wall), a.Wall.ID, a.Wall.AccessKey)
+	case constants.AttachmentTypePoll:
+		ref = NewAttachmentRef(a.Type, a.Poll.OwnerID, a.Poll.ID, "")
+	case constants.AttachmentTypeStory:
+		ref = NewAttachmentRef(a.Type, a.Story.OwnerID, a.Story.ID, a.Story.AccessKey)
+	case constants.AttachmentTypePodcast:
+		ref = NewAttachmentRef(a.Type, a.Podcast.OwnerID, a.Podcast.ID, "")
+	default:
+		return AttachmentRef{}, internalErrors.Error("Objects.MessagesMessageAttachment.Ref()", "attachment "+a.Type+" cannot be referenced")
+	}
+
+	return ref, ref.Validate()
+}
+
+// Ref returns the reference to the attachment of the post.
+// Photos uploaded directly to the post, lists of photos, applications, graffiti, events and communities
+// cannot be attached and are returned as an error
+func (a *WallWallpostAttachment) Ref() (AttachmentRef, error) {
+	var ref AttachmentRef
+
+	switch a.Type {
+	case constants.AttachmentTypePhoto:
+		ref = NewAttachmentRef(a.Type, a.Photo.OwnerID, a.Photo.ID, a.Photo.AccessKey)
+	case constants.AttachmentTypeVideo:
+		ref = NewAttachmentRef(a.Type, a.Video.OwnerID, a.Video.ID, a.Video.AccessKey)
+	case constants.AttachmentTypeClip:
+		ref = NewAttachmentRef(a.Type, a.Clip.OwnerID, a.Clip.ID, a.Clip.AccessKey)
+	case constants.AttachmentTypeAudio:
+		ref = NewAttachmentRef(a.Type, a.Audio.OwnerID, a.Audio.ID, a.Audio.AccessKey)
+	case constants.AttachmentTypeDoc:
+		ref = NewAttachmentRef(a.Type, a.Doc.OwnerID, a.Doc.ID, a.Doc.AccessKey)
+	case constants.AttachmentTypeLink:
+		ref = NewAttachmentRefLink(a.Link.URL)
+	case constants.AttachmentTypeMarket:
+		ref = NewAttachmentRef(a.Type, a.Market.OwnerID, a.Market.ID, a.Market.AccessKey)
+	case constants.AttachmentTypeMarketAlbum:
+		ref = NewAttachmentRef(a.Type, a.MarketAlbum.OwnerID, a.MarketAlbum.ID, "")
+	case constants.AttachmentTypeAlbum:
+		ref = NewAttachmentRef(a.Type, a.Album.OwnerID, a.Album.ID, "")
+	case constants.AttachmentTypeNote:
+		ref = NewAttachmentRef(a.Type, a.Note.OwnerID, a.Note.ID, "")
+	case constants.AttachmentTypePage:
+		ref = NewAttachmentRef(a.Type, -a.Page.GroupID, a.Page.ID, "")
+	case constants.AttachmentTypePoll:
+		ref = NewAttachmentRef(a.Type, a.Poll.OwnerID, a.Poll.ID, "")
+	case constants.AttachmentTypePodcast:
+		ref = NewAttachmentRef(a.Type, a.Podcast.OwnerID, a.Podcast.ID, "")
+	default:
+		return AttachmentRef{}, internalErrors.Error("Objects.WallWallpostAttachment.Ref()", "attachment "+a.Type+" cannot be referenced")
+	}
+
+	if ref.AccessKey == "" && ref.Type != constants.AttachmentTypeLink {
+		ref.AccessKey = a.AccessKey
+	}
+
+	return ref, ref.Validate()
+}
+
+// Ref returns the reference to the photo, the error if the photo has no owner or id
+func (photo *Photo) Ref() (AttachmentRef, error) {
+	ref := NewAttachmentRef(constants.AttachmentTypePhoto, photo.OwnerID, photo.ID, photo.AccessKey)
+	return ref, ref.Validate()
+}
+
+// Ref returns the reference to the document, the error if the document has no owner or id
+func (d *Document) Ref() (AttachmentRef, error) {
+	ref := NewAttachmentRef(constants.AttachmentTypeDoc, d.OwnerID, d.ID, d.AccessKey)
+	return ref, ref.Validate()
+}
+
+// Ref returns the reference to the video, the error if the video has no owner or id
+func (video *Video) Ref() (AttachmentRef, error) {
+	ref := NewAttachmentRef(constants.AttachmentTypeVideo, video.OwnerID, video.ID, video.AccessKey)
+	return ref, ref.Validate()
+}
+
+// Ref returns the reference to the audio, the error if the audio has no owner or id
+func (a *Audio) Ref() (AttachmentRef, error) {
+	ref := NewAttachmentRef(constants.AttachmentTypeAudio, a.OwnerID, a.ID, a.AccessKey)
+	return ref, ref.Validate()
+}
+
+// wallpostOwnerID returns owner_id of the post attached to the message, from_id if owner_id is not returned
+func wallpostOwnerID(post *WallWallpost) int {
+	if post.OwnerID != 0 {
+		return post.OwnerID
+	}
+
+	return post.FromID
+}
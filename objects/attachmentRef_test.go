@@ -0,0 +1,169 @@
+package objects_test
+
+import (
+	"go-vk-sdk/constants"
+	"go-vk-sdk/objects"
+	"testing"
+)
+
+func TestAttachmentRefRoundTrip(t *testing.T) {
+	tests := []struct {
+		value string
+		want  objects.AttachmentRef
+	}{
+		{value: "photo-1_2_accesskey", want: objects.NewAttachmentRef("photo", -1, 2, "accesskey")},
+		{value: "photo1_2", want: objects.NewAttachmentRef("photo", 1, 2, "")},
+		{value: "doc-123456_789", want: objects.NewAttachmentRef("doc", -123456, 789, "")},
+		{value: "wall-1_5", want: objects.NewAttachmentRef("wall", -1, 5, "")},
+		{value: "market-1_2", want: objects.NewAttachmentRef("market", -1, 2, "")},
+		{value: "market_album-1_2", want: objects.NewAttachmentRef("market_album", -1, 2, "")},
+		{value: "audio_playlist1_2_Ab9", want: objects.NewAttachmentRef("audio_playlist", 1, 2, "Ab9")},
+		{value: "poll1_2", want: objects.NewAttachmentRef("poll", 1, 2, "")},
+		{value: "story1_2_key", want: objects.NewAttachmentRef("story", 1, 2, "key")},
+		{value: "https://vk.com/club1", want: objects.NewAttachmentRefLink("https://vk.com/club1")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			ref, err := objects.ParseAttachmentRef(test.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if ref != test.want {
+				t.Errorf("got %+v, want %+v", ref, test.want)
+			}
+
+			if ref.ToAttachment() != test.value || ref.String() != test.value {
+				t.Errorf("got %q, want %q", ref.ToAttachment(), test.value)
+			}
+		})
+	}
+}
+
+func TestAttachmentRefInvalid(t *testing.T) {
+	for _, value := range []string{
+		"",
+		"photo1",
+		"photo_1_2",
+		"photo0_2",
+		"photo1_0",
+		"photo1_2_key!",
+		"sticker1_2",
+		"Photo1_2",
+		"photo1_2_",
+		"ftp://vk.com",
+		"photo99999999999999999999_1",
+	} {
+		if ref, err := objects.ParseAttachmentRef(value); err == nil {
+			t.Errorf("%q is parsed as %+v", value, ref)
+		}
+	}
+
+	if err := objects.NewAttachmentRefLink("vk.com").Validate(); err == nil {
+		t.Error("the link without the scheme is valid")
+	}
+}
+
+func TestParseAttachmentRefs(t *testing.T) {
+	refs, err := objects.ParseAttachmentRefs("photo1_2, doc-1_3_key ,https://vk.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"photo1_2", "doc-1_3_key", "https://vk.com"}
+	if len(refs) != len(want) {
+		t.Fatalf("got %v, want %v", refs, want)
+	}
+
+	for i, ref := range refs {
+		if ref.ToAttachment() != want[i] {
+			t.Errorf("got %q, want %q", ref.ToAttachment(), want[i])
+		}
+	}
+
+	if refs, err = objects.ParseAttachmentRefs(" "); err != nil || refs != nil {
+		t.Errorf("got %v %v for the empty list", refs, err)
+	}
+
+	if _, err = objects.ParseAttachmentRefs("photo1_2,photo1"); err == nil {
+		t.Error("the list with the invalid attachment is parsed")
+	}
+
+	if !(objects.AttachmentRef{}).IsZero() || (objects.AttachmentRef{}).ToAttachment() != "" {
+		t.Error("the zero reference is not empty")
+	}
+}
+
+func TestObjectRef(t *testing.T) {
+	tests := []struct {
+		name   string
+		object interface {
+			Ref() (objects.AttachmentRef, error)
+		}
+		want string
+	}{
+		{name: "photo", object: &objects.Photo{OwnerID: -1, ID: 2, AccessKey: "key"}, want: "photo-1_2_key"},
+		{name: "doc", object: &objects.Document{OwnerID: 1, ID: 2}, want: "doc1_2"},
+		{name: "video", object: &objects.Video{OwnerID: 1, ID: 2, AccessKey: "key"}, want: "video1_2_key"},
+		{name: "audio", object: &objects.Audio{OwnerID: -1, ID: 2}, want: "audio-1_2"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ref, err := test.object.Ref()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if ref.ToAttachment() != test.want {
+				t.Errorf("got %q, want %q", ref.ToAttachment(), test.want)
+			}
+
+			// the reference is formatted as ToAttachment of the object
+			if attachment, ok := test.object.(objects.Attachment); ok && attachment.ToAttachment() != test.want {
+				t.Errorf("ToAttachment returns %q, want %q", attachment.ToAttachment(), test.want)
+			}
+		})
+	}
+
+	if _, err := (&objects.Photo{}).Ref(); err == nil {
+		t.Error("the photo without the owner and id is referenced")
+	}
+}
+
+func TestAttachmentsRef(t *testing.T) {
+	message := []struct {
+		attachment objects.MessagesMessageAttachment
+		want       string
+	}{
+		{attachment: objects.MessagesMessageAttachment{Type: constants.AttachmentTypePhoto, Photo: objects.Photo{OwnerID: 1, ID: 2}}, want: "photo1_2"},
+		{attachment: objects.MessagesMessageAttachment{Type: constants.AttachmentTypeAudioMessage, AudioMessage: objects.Document{OwnerID: 1, ID: 3, AccessKey: "key"}}, want: "doc1_3_key"},
+		{attachment: objects.MessagesMessageAttachment{Type: constants.AttachmentTypeMarketAlbum, MarketMarketAlbum: objects.MarketAlbum{OwnerID: -1, ID: 4}}, want: "market_album-1_4"},
+		{attachment: objects.MessagesMessageAttachment{Type: constants.AttachmentTypeLink, Link: objects.Link{URL: "https://vk.com"}}, want: "https://vk.com"},
+	}
+
+	for _, test := range message {
+		ref, err := test.attachment.Ref()
+		if err != nil || ref.ToAttachment() != test.want {
+			t.Errorf("got %q %v, want %q", ref.ToAttachment(), err, test.want)
+		}
+	}
+
+	sticker := objects.MessagesMessageAttachment{Type: constants.AttachmentTypeSticker}
+	if _, err := sticker.Ref(); err == nil {
+		t.Error("the sticker is referenced")
+	}
+
+	// the access key of the attachment is used when the object has no own key
+	post := objects.WallWallpostAttachment{Type: constants.AttachmentTypePhoto, AccessKey: "key", Photo: objects.Photo{OwnerID: -1, ID: 2}}
+	if ref, err := post.Ref(); err != nil || ref.ToAttachment() != "photo-1_2_key" {
+		t.Errorf("got %q %v, want photo-1_2_key", ref.ToAttachment(), err)
+	}
+
+	page := objects.WallWallpostAttachment{Type: constants.AttachmentTypePage}
+	page.Page.GroupID, page.Page.ID = 1, 5
+	if ref, err := page.Ref(); err != nil || ref.ToAttachment() != "page-1_5" {
+		t.Errorf("got %q %v, want page-1_5", ref.ToAttachment(), err)
+	}
+}
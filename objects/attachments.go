@@ -0,0 +1,588 @@
+package objects
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-vk-sdk/constants"
+)
+
+// messagesAttachmentKinds kinds of the attachments decoded into the typed fields
+var messagesAttachmentKinds = map[string]bool{
+	constants.AttachmentTypePhoto:        true,
+	constants.AttachmentTypeVideo:        true,
+	constants.AttachmentTypeAudio:        true,
+	constants.AttachmentTypeDoc:          true,
+	constants.AttachmentTypeLink:         true,
+	constants.AttachmentTypeMarket:       true,
+	constants.AttachmentTypeMarketAlbum:  true,
+	constants.AttachmentTypeWall:         true,
+	constants.AttachmentTypeWallReply:    true,
+	constants.AttachmentTypeSticker:      true,
+	constants.AttachmentTypeGift:         true,
+	constants.AttachmentTypeAudioMessage: true,
+	constants.AttachmentTypeGraffiti:     true,
+	constants.AttachmentTypePoll:         true,
+	constants.AttachmentTypeCall:         true,
+	constants.AttachmentTypeStory:        true,
+	constants.AttachmentTypePodcast:      true,
+}
+
+// UnmarshalJSON keeps the raw JSON of the unknown kinds in Raw, so new kinds of VK attachments are not lost
+func (a *MessagesMessageAttachment) UnmarshalJSON(data []byte) error {
+	type renamedMessagesMessageAttachment MessagesMessageAttachment
+
+	var r renamedMessagesMessageAttachment
+
+	err := json.Unmarshal(data, &r)
+	if err != nil {
+		return fmt.Errorf("objects.MessagesMessageAttachment: %w", err)
+	}
+
+	*a = MessagesMessageAttachment(r)
+
+	// the album of the products is returned in the field market_album
+	if a.Type == constants.AttachmentTypeMarketAlbum && a.MarketMarketAlbum.ID == 0 {
+		var album struct {
+			MarketAlbum MarketAlbum `json:"market_album"`
+		}
+
+		if err = json.Unmarshal(data, &album); err != nil {
+			return fmt.Errorf("objects.MessagesMessageAttachment: %w", err)
+		}
+
+		a.MarketMarketAlbum = album.MarketAlbum
+	}
+
+	if messagesAttachmentKinds[a.Type] {
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("objects.MessagesMessageAttachment: %w", err)
+	}
+
+	if raw, ok := fields[a.Type]; ok {
+		a.Raw = raw
+	} else {
+		a.Raw = append(json.RawMessage(nil), data...)
+	}
+
+	return nil
+}
+
+// AsPhoto returns the photo attachment, ok is false for the other kinds
+func (a *MessagesMessageAttachment) AsPhoto() (*Photo, bool) {
+	if a.Type != constants.AttachmentTypePhoto {
+		return nil, false
+	}
+
+	return &a.Photo, true
+}
+
+// AsVideo returns the video attachment, ok is false for the other kinds
+func (a *MessagesMessageAttachment) AsVideo() (*Video, bool) {
+	if a.Type != constants.AttachmentTypeVideo {
+		return nil, false
+	}
+
+	return &a.Video, true
+}
+
+// AsAudio returns the audio attachment, ok is false for the other kinds
+func (a *MessagesMessageAttachment) AsAudio() (*Audio, bool) {
+	if a.Type != constants.AttachmentTypeAudio {
+		return nil, false
+	}
+
+	return &a.Audio, true
+}
+
+// AsDoc returns the doc attachment, ok is false for the other kinds
+func (a *MessagesMessageAttachment) AsDoc() (*Document, bool) {
+	if a.Type != constants.AttachmentTypeDoc {
+		return nil, false
+	}
+
+	return &a.Doc, true
+}
+
+// AsLink returns the link attachment, ok is false for the other kinds
+func (a *MessagesMessageAttachment) AsLink() (*Link, bool) {
+	if a.Type != constants.AttachmentTypeLink {
+		return nil, false
+	}
+
+	return &a.Link, true
+}
+
+// AsMarket returns the market attachment, ok is false for the other kinds
+func (a *MessagesMessageAttachment) AsMarket() (*MarketItem, bool) {
+	if a.Type != constants.AttachmentTypeMarket {
+		return nil, false
+	}
+
+	return &a.Market, true
+}
+
+// AsMarketAlbum returns the market_album attachment, ok is false for the other kinds
+func (a *MessagesMessageAttachment) AsMarketAlbum() (*MarketAlbum, bool) {
+	if a.Type != constants.AttachmentTypeMarketAlbum {
+		return nil, false
+	}
+
+	return &a.MarketMarketAlbum, true
+}
+
+// AsWall returns the wall attachment, ok is false for the other kinds
+func (a *MessagesMessageAttachment) AsWall() (*WallWallpost, bool) {
+	if a.Type != constants.AttachmentTypeWall {
+		return nil, false
+	}
+
+	return &a.Wall, true
+}
+
+// AsWallReply returns the wall_reply attachment, ok is false for the other kinds
+func (a *MessagesMessageAttachment) AsWallReply() (*WallWallComment, bool) {
+	if a.Type != constants.AttachmentTypeWallReply {
+		return nil, false
+	}
+
+	return &a.WallReply, true
+}
+
+// AsSticker returns the sticker attachment, ok is false for the other kinds
+func (a *MessagesMessageAttachment) AsSticker() (*Sticker, bool) {
+	if a.Type != constants.AttachmentTypeSticker {
+		return nil, false
+	}
+
+	return &a.Sticker, true
+}
+
+// AsGift returns the gift attachment, ok is false for the other kinds
+func (a *MessagesMessageAttachment) AsGift() (*GiftsLayout, bool) {
+	if a.Type != constants.AttachmentTypeGift {
+		return nil, false
+	}
+
+	return &a.Gift, true
+}
+
+// AsAudioMessage returns the audio_message attachment, ok is false for the other kinds
+func (a *MessagesMessageAttachment) AsAudioMessage() (*Document, bool) {
+	if a.Type != constants.AttachmentTypeAudioMessage {
+		return nil, false
+	}
+
+	return &a.AudioMessage, true
+}
+
+// AsGraffiti returns the graffiti attachment, ok is false for the other kinds
+func (a *MessagesMessageAttachment) AsGraffiti() (*Document, bool) {
+	if a.Type != constants.AttachmentTypeGraffiti {
+		return nil, false
+	}
+
+	return &a.Graffiti, true
+}
+
+// AsPoll returns the poll attachment, ok is false for the other kinds
+func (a *MessagesMessageAttachment) AsPoll() (*PollsPoll, bool) {
+	if a.Type != constants.AttachmentTypePoll {
+		return nil, false
+	}
+
+	return &a.Poll, true
+}
+
+// AsCall returns the call attachment, ok is false for the other kinds
+func (a *MessagesMessageAttachment) AsCall() (*MessageCall, bool) {
+	if a.Type != constants.AttachmentTypeCall {
+		return nil, false
+	}
+
+	return &a.Call, true
+}
+
+// AsStory returns the story attachment, ok is false for the other kinds
+func (a *MessagesMessageAttachment) AsStory() (*StoriesStory, bool) {
+	if a.Type != constants.AttachmentTypeStory {
+		return nil, false
+	}
+
+	return &a.Story, true
+}
+
+// AsPodcast returns the podcast attachment, ok is false for the other kinds
+func (a *MessagesMessageAttachment) AsPodcast() (*PodcastsEpisode, bool) {
+	if a.Type != constants.AttachmentTypePodcast {
+		return nil, false
+	}
+
+	return &a.Podcast, true
+}
+
+// Payload returns the pointer to the field of the kind of the attachment, json.RawMessage for the unknown kinds
+// and nil if the attachment is empty
+func (a *MessagesMessageAttachment) Payload() interface{} {
+	switch a.Type {
+	case constants.AttachmentTypePhoto:
+		return &a.Photo
+	case constants.AttachmentTypeVideo:
+		return &a.Video
+	case constants.AttachmentTypeAudio:
+		return &a.Audio
+	case constants.AttachmentTypeDoc:
+		return &a.Doc
+	case constants.AttachmentTypeLink:
+		return &a.Link
+	case constants.AttachmentTypeMarket:
+		return &a.Market
+	case constants.AttachmentTypeMarketAlbum:
+		return &a.MarketMarketAlbum
+	case constants.AttachmentTypeWall:
+		return &a.Wall
+	case constants.AttachmentTypeWallReply:
+		return &a.WallReply
+	case constants.AttachmentTypeSticker:
+		return &a.Sticker
+	case constants.AttachmentTypeGift:
+		return &a.Gift
+	case constants.AttachmentTypeAudioMessage:
+		return &a.AudioMessage
+	case constants.AttachmentTypeGraffiti:
+		return &a.Graffiti
+	case constants.AttachmentTypePoll:
+		return &a.Poll
+	case constants.AttachmentTypeCall:
+		return &a.Call
+	case constants.AttachmentTypeStory:
+		return &a.Story
+	case constants.AttachmentTypePodcast:
+		return &a.Podcast
+	}
+
+	if a.Raw != nil {
+		return a.Raw
+	}
+
+	return nil
+}
+
+// EachMessagesAttachment calls fn with the kind and the payload of every non-empty attachment until fn returns false
+func EachMessagesAttachment(attachments []MessagesMessageAttachment, fn func(kind string, payload interface{}) bool) {
+	for i := range attachments {
+		payload := attachments[i].Payload()
+		if payload == nil {
+			continue
+		}
+
+		if !fn(attachments[i].Type, payload) {
+			return
+		}
+	}
+}
+
+// wallAttachmentKinds kinds of the attachments decoded into the typed fields
+var wallAttachmentKinds = map[string]bool{
+	constants.AttachmentTypePhoto:         true,
+	constants.AttachmentTypePostedPhoto:   true,
+	constants.AttachmentTypeVideo:         true,
+	constants.AttachmentTypeClip:          true,
+	constants.AttachmentTypeAudio:         true,
+	constants.AttachmentTypeDoc:           true,
+	constants.AttachmentTypeLink:          true,
+	constants.AttachmentTypeMarket:        true,
+	constants.AttachmentTypeMarketAlbum:   true,
+	constants.AttachmentTypeAlbum:         true,
+	constants.AttachmentTypePhotosList:    true,
+	constants.AttachmentTypeNote:          true,
+	constants.AttachmentTypePage:          true,
+	constants.AttachmentTypePoll:          true,
+	constants.AttachmentTypeGraffiti:      true,
+	constants.AttachmentTypeApp:           true,
+	constants.AttachmentTypeEvent:         true,
+	constants.AttachmentTypeGroup:         true,
+	constants.AttachmentTypeVideoPlaylist: true,
+	constants.AttachmentTypePodcast:       true,
+}
+
+// UnmarshalJSON keeps the raw JSON of the unknown kinds in Raw, so new kinds of VK attachments are not lost
+func (a *WallWallpostAttachment) UnmarshalJSON(data []byte) error {
+	type renamedWallWallpostAttachment WallWallpostAttachment
+
+	var r renamedWallWallpostAttachment
+
+	err := json.Unmarshal(data, &r)
+	if err != nil {
+		return fmt.Errorf("objects.WallWallpostAttachment: %w", err)
+	}
+
+	*a = WallWallpostAttachment(r)
+
+	if wallAttachmentKinds[a.Type] {
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("objects.WallWallpostAttachment: %w", err)
+	}
+
+	if raw, ok := fields[a.Type]; ok {
+		a.Raw = raw
+	} else {
+		a.Raw = append(json.RawMessage(nil), data...)
+	}
+
+	return nil
+}
+
+// AsPhoto returns the photo attachment, ok is false for the other kinds
+func (a *WallWallpostAttachment) AsPhoto() (*Photo, bool) {
+	if a.Type != constants.AttachmentTypePhoto {
+		return nil, false
+	}
+
+	return &a.Photo, true
+}
+
+// AsPostedPhoto returns the posted_photo attachment, ok is false for the other kinds
+func (a *WallWallpostAttachment) AsPostedPhoto() (*WallPostedPhoto, bool) {
+	if a.Type != constants.AttachmentTypePostedPhoto {
+		return nil, false
+	}
+
+	return &a.PostedPhoto, true
+}
+
+// AsVideo returns the video attachment, ok is false for the other kinds
+func (a *WallWallpostAttachment) AsVideo() (*VideoFull, bool) {
+	if a.Type != constants.AttachmentTypeVideo {
+		return nil, false
+	}
+
+	return &a.Video, true
+}
+
+// AsClip returns the clip attachment, ok is false for the other kinds
+func (a *WallWallpostAttachment) AsClip() (*VideoFull, bool) {
+	if a.Type != constants.AttachmentTypeClip {
+		return nil, false
+	}
+
+	return &a.Clip, true
+}
+
+// AsAudio returns the audio attachment, ok is false for the other kinds
+func (a *WallWallpostAttachment) AsAudio() (*Audio, bool) {
+	if a.Type != constants.AttachmentTypeAudio {
+		return nil, false
+	}
+
+	return &a.Audio, true
+}
+
+// AsDoc returns the doc attachment, ok is false for the other kinds
+func (a *WallWallpostAttachment) AsDoc() (*Document, bool) {
+	if a.Type != constants.AttachmentTypeDoc {
+		return nil, false
+	}
+
+	return &a.Doc, true
+}
+
+// AsLink returns the link attachment, ok is false for the other kinds
+func (a *WallWallpostAttachment) AsLink() (*Link, bool) {
+	if a.Type != constants.AttachmentTypeLink {
+		return nil, false
+	}
+
+	return &a.Link, true
+}
+
+// AsMarket returns the market attachment, ok is false for the other kinds
+func (a *WallWallpostAttachment) AsMarket() (*MarketItem, bool) {
+	if a.Type != constants.AttachmentTypeMarket {
+		return nil, false
+	}
+
+	return &a.Market, true
+}
+
+// AsMarketAlbum returns the market_album attachment, ok is false for the other kinds
+func (a *WallWallpostAttachment) AsMarketAlbum() (*MarketAlbum, bool) {
+	if a.Type != constants.AttachmentTypeMarketAlbum {
+		return nil, false
+	}
+
+	return &a.MarketAlbum, true
+}
+
+// AsAlbum returns the album attachment, ok is false for the other kinds
+func (a *WallWallpostAttachment) AsAlbum() (*PhotosPhotoAlbum, bool) {
+	if a.Type != constants.AttachmentTypeAlbum {
+		return nil, false
+	}
+
+	return &a.Album, true
+}
+
+// AsPhotosList returns the photos_list attachment, ok is false for the other kinds
+func (a *WallWallpostAttachment) AsPhotosList() ([]string, bool) {
+	if a.Type != constants.AttachmentTypePhotosList {
+		return nil, false
+	}
+
+	return a.PhotosList, true
+}
+
+// AsNote returns the note attachment, ok is false for the other kinds
+func (a *WallWallpostAttachment) AsNote() (*WallAttachedNote, bool) {
+	if a.Type != constants.AttachmentTypeNote {
+		return nil, false
+	}
+
+	return &a.Note, true
+}
+
+// AsPage returns the page attachment, ok is false for the other kinds
+func (a *WallWallpostAttachment) AsPage() (*PagesWikipageFull, bool) {
+	if a.Type != constants.AttachmentTypePage {
+		return nil, false
+	}
+
+	return &a.Page, true
+}
+
+// AsPoll returns the poll attachment, ok is false for the other kinds
+func (a *WallWallpostAttachment) AsPoll() (*PollsPoll, bool) {
+	if a.Type != constants.AttachmentTypePoll {
+		return nil, false
+	}
+
+	return &a.Poll, true
+}
+
+// AsGraffiti returns the graffiti attachment, ok is false for the other kinds
+func (a *WallWallpostAttachment) AsGraffiti() (*WallGraffiti, bool) {
+	if a.Type != constants.AttachmentTypeGraffiti {
+		return nil, false
+	}
+
+	return &a.Graffiti, true
+}
+
+// AsApp returns the app attachment, ok is false for the other kinds
+func (a *WallWallpostAttachment) AsApp() (*WallAppPost, bool) {
+	if a.Type != constants.AttachmentTypeApp {
+		return nil, false
+	}
+
+	return &a.App, true
+}
+
+// AsEvent returns the event attachment, ok is false for the other kinds
+func (a *WallWallpostAttachment) AsEvent() (*EventsEventAttach, bool) {
+	if a.Type != constants.AttachmentTypeEvent {
+		return nil, false
+	}
+
+	return &a.Event, true
+}
+
+// AsGroup returns the group attachment, ok is false for the other kinds
+func (a *WallWallpostAttachment) AsGroup() (*GroupAttach, bool) {
+	if a.Type != constants.AttachmentTypeGroup {
+		return nil, false
+	}
+
+	return &a.Group, true
+}
+
+// AsVideoPlaylist returns the video_playlist attachment, ok is false for the other kinds
+func (a *WallWallpostAttachment) AsVideoPlaylist() (*VideoAlbumFull, bool) {
+	if a.Type != constants.AttachmentTypeVideoPlaylist {
+		return nil, false
+	}
+
+	return &a.VideoPlaylist, true
+}
+
+// AsPodcast returns the podcast attachment, ok is false for the other kinds
+func (a *WallWallpostAttachment) AsPodcast() (*PodcastsEpisode, bool) {
+	if a.Type != constants.AttachmentTypePodcast {
+		return nil, false
+	}
+
+	return &a.Podcast, true
+}
+
+// Payload returns the pointer to the field of the kind of the attachment, json.RawMessage for the unknown kinds
+// and nil if the attachment is empty
+func (a *WallWallpostAttachment) Payload() interface{} {
+	switch a.Type {
+	case constants.AttachmentTypePhoto:
+		return &a.Photo
+	case constants.AttachmentTypePostedPhoto:
+		return &a.PostedPhoto
+	case constants.AttachmentTypeVideo:
+		return &a.Video
+	case constants.AttachmentTypeClip:
+		return &a.Clip
+	case constants.AttachmentTypeAudio:
+		return &a.Audio
+	case constants.AttachmentTypeDoc:
+		return &a.Doc
+	case constants.AttachmentTypeLink:
+		return &a.Link
+	case constants.AttachmentTypeMarket:
+		return &a.Market
+	case constants.AttachmentTypeMarketAlbum:
+		return &a.MarketAlbum
+	case constants.AttachmentTypeAlbum:
+		return &a.Album
+	case constants.AttachmentTypePhotosList:
+		return a.PhotosList
+	case constants.AttachmentTypeNote:
+		return &a.Note
+	case constants.AttachmentTypePage:
+		return &a.Page
+	case constants.AttachmentTypePoll:
+		return &a.Poll
+	case constants.AttachmentTypeGraffiti:
+		return &a.Graffiti
+	case constants.AttachmentTypeApp:
+		return &a.App
+	case constants.AttachmentTypeEvent:
+		return &a.Event
+	case constants.AttachmentTypeGroup:
+		return &a.Group
+	case constants.AttachmentTypeVideoPlaylist:
+		return &a.VideoPlaylist
+	case constants.AttachmentTypePodcast:
+		return &a.Podcast
+	}
+
+	if a.Raw != nil {
+		return a.Raw
+	}
+
+	return nil
+}
+
+// EachWallAttachment calls fn with the kind and the payload of every non-empty attachment until fn returns false
+func EachWallAttachment(attachments []WallWallpostAttachment, fn func(kind string, payload interface{}) bool) {
+	for i := range attachments {
+		payload := attachments[i].Payload()
+		if payload == nil {
+			continue
+		}
+
+		if !fn(attachments[i].Type, payload) {
+			return
+		}
+	}
+}
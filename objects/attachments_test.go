@@ -0,0 +1,139 @@
+package objects_test
+
+import (
+	"encoding/json"
+	"go-vk-sdk/objects"
+	"testing"
+)
+
+func TestMessagesAttachmentUnknownKind(t *testing.T) {
+	var attachment objects.MessagesMessageAttachment
+	if err := json.Unmarshal([]byte(`{"type":"mini_app","mini_app":{"app_id":7,"title":"App"}}`), &attachment); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(attachment.Raw) != `{"app_id":7,"title":"App"}` {
+		t.Errorf("got raw %s", attachment.Raw)
+	}
+
+	if payload, ok := attachment.Payload().(json.RawMessage); !ok || string(payload) != string(attachment.Raw) {
+		t.Errorf("got payload %#v, want the raw JSON", attachment.Payload())
+	}
+
+	// the whole object is kept when the kind has no field of its name
+	if err := json.Unmarshal([]byte(`{"type":"artifact","id":1}`), &attachment); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(attachment.Raw) != `{"type":"artifact","id":1}` {
+		t.Errorf("got raw %s", attachment.Raw)
+	}
+
+	// the known kinds have no raw JSON
+	if err := json.Unmarshal([]byte(`{"type":"photo","photo":{"id":1,"owner_id":2}}`), &attachment); err != nil {
+		t.Fatal(err)
+	}
+
+	if attachment.Raw != nil {
+		t.Errorf("got raw %s for the photo", attachment.Raw)
+	}
+}
+
+func TestMessagesAttachmentMarketAlbum(t *testing.T) {
+	var attachment objects.MessagesMessageAttachment
+	if err := json.Unmarshal([]byte(`{"type":"market_album","market_album":{"id":4,"owner_id":-1,"title":"Shoes","count":3}}`), &attachment); err != nil {
+		t.Fatal(err)
+	}
+
+	album, ok := attachment.AsMarketAlbum()
+	if !ok || album.ID != 4 || album.OwnerID != -1 || album.Title != "Shoes" {
+		t.Fatalf("got %+v %t", album, ok)
+	}
+
+	if attachment.Payload() != album {
+		t.Error("the payload is not the album")
+	}
+
+	if album.ToAttachment() != "market_album-1_4" {
+		t.Errorf("got %q", album.ToAttachment())
+	}
+}
+
+func TestMessagesAttachmentAccessors(t *testing.T) {
+	var attachment objects.MessagesMessageAttachment
+	if err := json.Unmarshal([]byte(`{"type":"doc","doc":{"id":3,"owner_id":1,"title":"report.txt"}}`), &attachment); err != nil {
+		t.Fatal(err)
+	}
+
+	if doc, ok := attachment.AsDoc(); !ok || doc.Title != "report.txt" {
+		t.Errorf("got %+v %t", doc, ok)
+	}
+
+	if photo, ok := attachment.AsPhoto(); ok || photo != nil {
+		t.Error("the document is returned as the photo")
+	}
+
+	if _, ok := attachment.AsAudioMessage(); ok {
+		t.Error("the document is returned as the voice message")
+	}
+
+	if _, ok := attachment.AsGraffiti(); ok {
+		t.Error("the document is returned as the graffiti")
+	}
+
+	if _, ok := attachment.AsMarketAlbum(); ok {
+		t.Error("the document is returned as the album of the products")
+	}
+
+	var kinds []string
+	objects.EachMessagesAttachment([]objects.MessagesMessageAttachment{attachment, {}, attachment}, func(kind string, payload interface{}) bool {
+		kinds = append(kinds, kind)
+		return false
+	})
+
+	if len(kinds) != 1 || kinds[0] != "doc" {
+		t.Errorf("got kinds %v, want the iteration stopped after the first attachment", kinds)
+	}
+}
+
+func TestWallAttachmentUnknownKind(t *testing.T) {
+	var attachments []objects.WallWallpostAttachment
+	err := json.Unmarshal([]byte(`[
+		{"type":"textlive","textlive":{"textlive_id":5}},
+		{"type":"market_album","market_album":{"id":4,"owner_id":-1}},
+		{"type":"video","video":{"id":2,"owner_id":1}}
+	]`), &attachments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(attachments[0].Raw) != `{"textlive_id":5}` {
+		t.Errorf("got raw %s", attachments[0].Raw)
+	}
+
+	if album, ok := attachments[1].AsMarketAlbum(); !ok || album.ID != 4 || album.OwnerID != -1 {
+		t.Errorf("got %+v %t", album, ok)
+	}
+
+	if _, ok := attachments[1].AsAlbum(); ok {
+		t.Error("the album of the products is returned as the album of the photos")
+	}
+
+	if video, ok := attachments[2].AsVideo(); !ok || video.ID != 2 {
+		t.Errorf("got %+v %t", video, ok)
+	}
+
+	if _, ok := attachments[2].AsClip(); ok {
+		t.Error("the video is returned as the clip")
+	}
+
+	var kinds []string
+	objects.EachWallAttachment(attachments, func(kind string, payload interface{}) bool {
+		kinds = append(kinds, kind)
+		return true
+	})
+
+	if len(kinds) != 3 || kinds[0] != "textlive" {
+		t.Errorf("got kinds %v", kinds)
+	}
+}
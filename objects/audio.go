@@ -1,9 +1,5 @@
 package objects
 
-import (
-	"fmt"
-)
-
 type Audio struct {
 	ID                  int           `json:"id"`
 	OwnerID             int           `json:"owner_id"`
@@ -31,7 +27,7 @@ type Audio struct {
 }
 
 func (a *Audio) ToAttachment() string {
-	return fmt.Sprintf("a%d_%d", a.OwnerID, a.ID)
+	return attachmentString("audio", a.OwnerID, a.ID, a.AccessKey)
 }
 
 type AudioAds struct {
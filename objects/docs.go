@@ -1,9 +1,5 @@
 package objects
 
-import (
-	"fmt"
-)
-
 type Document struct {
 	AccessKey  string          `json:"access_key"`
 	Date       int             `json:"date"`
@@ -21,7 +17,7 @@ type Document struct {
 }
 
 func (d *Document) ToAttachment() string {
-	return fmt.Sprintf("d%d_%d", d.OwnerID, d.ID)
+	return attachmentString("doc", d.OwnerID, d.ID, d.AccessKey)
 }
 
 type DocumentPreview struct {
@@ -0,0 +1,423 @@
+package objects
+
+import (
+	"encoding/json"
+	"go-vk-sdk/constants"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// MessagesFormatData Styles of the text of the message, the parameter format_data of messages.send and messages.edit.
+//
+//	Offsets and lengths of the items are counted in UTF-16 code units, so an emoji takes 2 units.
+type MessagesFormatData struct {
+	Version int                      `json:"version"`
+	Items   []MessagesFormatDataItem `json:"items"`
+}
+
+type MessagesFormatDataItem struct {
+	Type   string `json:"type"`          // constants.FormatDataBold, FormatDataItalic, FormatDataUnderline or FormatDataURL
+	Offset int    `json:"offset"`        // in UTF-16 code units
+	Length int    `json:"length"`        // in UTF-16 code units
+	URL    string `json:"url,omitempty"` // only for the type url
+}
+
+func (format MessagesFormatData) ToJSON() string {
+	b, _ := json.Marshal(format)
+	return string(b)
+}
+
+// MessagesTextStyle Style of the part of the text, URL is not empty for the link
+type MessagesTextStyle struct {
+	Bold      bool
+	Italic    bool
+	Underline bool
+	URL       string
+}
+
+// MessagesTextSpan Part of the text with the same style
+type MessagesTextSpan struct {
+	Text string
+	MessagesTextStyle
+}
+
+// Spans returns the text of the message split by the styles of format_data
+func (message Message) Spans() []MessagesTextSpan {
+	return FormatSpans(message.Text, message.FormatData)
+}
+
+// MessagesTextBuilder Composes the text of the message from the styled parts and computes its format_data.
+//
+//	text := objects.NewMessagesTextBuilder().
+//		Bold("Заказ №42").
+//		Text(" оплачен 🎉 ").
+//		Link("Подробнее", "https://example.com/orders/42")
+//
+//	request.NewMessagesSendRequest(a, actor).PeerID(peerID).FormattedMessage(text)
+type MessagesTextBuilder struct {
+	text   strings.Builder
+	length int // in UTF-16 code units
+	items  []MessagesFormatDataItem
+	last   map[string]int // index of the last item of every type
+}
+
+func NewMessagesTextBuilder() *MessagesTextBuilder {
+	return &MessagesTextBuilder{last: map[string]int{}}
+}
+
+// Text adds the text without the style
+func (b *MessagesTextBuilder) Text(s string) *MessagesTextBuilder {
+	return b.Styled(s, MessagesTextStyle{})
+}
+
+func (b *MessagesTextBuilder) Bold(s string) *MessagesTextBuilder {
+	return b.Styled(s, MessagesTextStyle{Bold: true})
+}
+
+func (b *MessagesTextBuilder) Italic(s string) *MessagesTextBuilder {
+	return b.Styled(s, MessagesTextStyle{Italic: true})
+}
+
+func (b *MessagesTextBuilder) Underline(s string) *MessagesTextBuilder {
+	return b.Styled(s, MessagesTextStyle{Underline: true})
+}
+
+func (b *MessagesTextBuilder) Link(s, url string) *MessagesTextBuilder {
+	return b.Styled(s, MessagesTextStyle{URL: url})
+}
+
+// Styled adds the text with the combination of the styles,
+// the ranges of the same style following each other are merged
+func (b *MessagesTextBuilder) Styled(s string, style MessagesTextStyle) *MessagesTextBuilder {
+	if s == "" {
+		return b
+	}
+
+	length := utf16Len(s)
+
+	if style.Bold {
+		b.extend(constants.FormatDataBold, "", length)
+	}
+	if style.Italic {
+		b.extend(constants.FormatDataItalic, "", length)
+	}
+	if style.Underline {
+		b.extend(constants.FormatDataUnderline, "", length)
+	}
+	if style.URL != "" {
+		b.extend(constants.FormatDataURL, style.URL, length)
+	}
+
+	b.text.WriteString(s)
+	b.length += length
+
+	return b
+}
+
+// Markdown adds the text in the subset of Markdown:
+// **bold**, *italic* or _italic_, __underline__, [text](url) and the escaping by the backslash.
+// The markers without the closing pair are kept as the text
+func (b *MessagesTextBuilder) Markdown(s string) *MessagesTextBuilder {
+	newMarkdownParser(b, s).parse()
+	return b
+}
+
+// String returns the text of the message
+func (b *MessagesTextBuilder) String() string {
+	return b.text.String()
+}
+
+// FormatData returns format_data of the text, the items are sorted by the offset
+func (b *MessagesTextBuilder) FormatData() MessagesFormatData {
+	items := append([]MessagesFormatDataItem{}, b.items...)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Offset < items[j].Offset
+	})
+
+	return MessagesFormatData{Version: constants.FormatDataVersion, Items: items}
+}
+
+// extend continues the last item of the type if it ends at the end of the text, otherwise adds the new item
+func (b *MessagesTextBuilder) extend(kind, url string, length int) {
+	if i, ok := b.last[kind]; ok {
+		item := &b.items[i]
+		if item.Offset+item.Length == b.length && item.URL == url {
+			item.Length += length
+			return
+		}
+	}
+
+	b.last[kind] = len(b.items)
+	b.items = append(b.items, MessagesFormatDataItem{Type: kind, Offset: b.length, Length: length, URL: url})
+}
+
+// ParseMarkdown converts the subset of Markdown supported by MessagesTextBuilder.Markdown to the text and format_data
+func ParseMarkdown(s string) (string, MessagesFormatData) {
+	b := NewMessagesTextBuilder().Markdown(s)
+	return b.String(), b.FormatData()
+}
+
+// FormatSpans splits the text by the styles of format_data, the ranges outside the text are ignored
+func FormatSpans(text string, format *MessagesFormatData) []MessagesTextSpan {
+	if text == "" {
+		return nil
+	}
+
+	if format == nil || len(format.Items) == 0 {
+		return []MessagesTextSpan{{Text: text}}
+	}
+
+	// boundaries of the ranges in UTF-16 code units
+	bounds := map[int]bool{}
+	for _, item := range format.Items {
+		bounds[item.Offset] = true
+		bounds[item.Offset+item.Length] = true
+	}
+
+	var spans []MessagesTextSpan
+
+	start, startOffset, offset := 0, 0, 0
+	for i, r := range text {
+		if i > 0 && bounds[offset] {
+			spans = appendSpan(spans, text[start:i], format, startOffset)
+			start, startOffset = i, offset
+		}
+
+		offset += utf16RuneLen(r)
+	}
+
+	return appendSpan(spans, text[start:], format, startOffset)
+}
+
+// appendSpan adds the part of the text starting at offset, the part is merged with the previous span of the same style
+func appendSpan(spans []MessagesTextSpan, text string, format *MessagesFormatData, offset int) []MessagesTextSpan {
+	var style MessagesTextStyle
+
+	for _, item := range format.Items {
+		if item.Offset > offset || item.Offset+item.Length <= offset {
+			continue
+		}
+
+		switch item.Type {
+		case constants.FormatDataBold:
+			style.Bold = true
+		case constants.FormatDataItalic:
+			style.Italic = true
+		case constants.FormatDataUnderline:
+			style.Underline = true
+		case constants.FormatDataURL:
+			style.URL = item.URL
+		}
+	}
+
+	if n := len(spans); n > 0 && spans[n-1].MessagesTextStyle == style {
+		spans[n-1].Text += text
+		return spans
+	}
+
+	return append(spans, MessagesTextSpan{Text: text, MessagesTextStyle: style})
+}
+
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16RuneLen(r)
+	}
+	return n
+}
+
+// utf16RuneLen returns 2 for the runes encoded by the surrogate pair
+func utf16RuneLen(r rune) int {
+	if r >= 0x10000 {
+		return 2
+	}
+	return 1
+}
+
+// markdownParser Converts Markdown to the calls of MessagesTextBuilder, the text is collected until the style changes
+type markdownParser struct {
+	builder *MessagesTextBuilder
+	runes   []rune
+	style   MessagesTextStyle
+	pending strings.Builder
+	linkEnd int // index of ] of the current link, -1 outside the link
+	linkTo  int // index after ) of the current link
+}
+
+func newMarkdownParser(builder *MessagesTextBuilder, s string) *markdownParser {
+	return &markdownParser{builder: builder, runes: []rune(s), linkEnd: -1}
+}
+
+func (p *markdownParser) parse() {
+	rs := p.runes
+
+	for i := 0; i < len(rs); {
+		if i == p.linkEnd {
+			p.setStyle(func(style *MessagesTextStyle) { style.URL = "" })
+			i, p.linkEnd = p.linkTo, -1
+			continue
+		}
+
+		if rs[i] == '\\' && i+1 < len(rs) && isMarkdownPunct(rs[i+1]) {
+			p.pending.WriteRune(rs[i+1])
+			i += 2
+			continue
+		}
+
+		if marker := p.marker(i); marker != "" {
+			if flag := p.flag(marker); *flag && p.canClose(marker, i) || !*flag && p.canOpen(marker, i) {
+				on := !*flag
+				p.setStyle(func(style *MessagesTextStyle) { *p.flagOf(style, marker) = on })
+				i += len(marker)
+				continue
+			}
+		}
+
+		if rs[i] == '[' && p.linkEnd < 0 {
+			if end, to, url, ok := p.link(i); ok {
+				p.setStyle(func(style *MessagesTextStyle) { style.URL = url })
+				p.linkEnd, p.linkTo = end, to
+				i++
+				continue
+			}
+		}
+
+		p.pending.WriteRune(rs[i])
+		i++
+	}
+
+	p.flush()
+}
+
+// marker returns the marker of the style at i
+func (p *markdownParser) marker(i int) string {
+	rs := p.runes
+
+	switch rs[i] {
+	case '*', '_':
+		if i+1 < len(rs) && rs[i+1] == rs[i] {
+			return string(rs[i : i+2])
+		}
+		return string(rs[i])
+	}
+
+	return ""
+}
+
+func (p *markdownParser) flag(marker string) *bool {
+	return p.flagOf(&p.style, marker)
+}
+
+func (p *markdownParser) flagOf(style *MessagesTextStyle, marker string) *bool {
+	switch marker {
+	case "**":
+		return &style.Bold
+	case "__":
+		return &style.Underline
+	}
+	return &style.Italic
+}
+
+// canOpen reports whether the marker at i has the closing pair,
+// the underscores open the style only at the beginning of the word
+func (p *markdownParser) canOpen(marker string, i int) bool {
+	rs := p.runes
+	n := len([]rune(marker))
+
+	if marker[0] == '_' && i > 0 && isWordRune(rs[i-1]) {
+		return false
+	}
+
+	if i+n >= len(rs) || unicode.IsSpace(rs[i+n]) {
+		return false
+	}
+
+	end := len(rs)
+	if p.linkEnd >= 0 {
+		end = p.linkEnd
+	}
+
+	for j := i + n; j < end; j++ {
+		if rs[j] == '\\' {
+			j++
+			continue
+		}
+
+		if rs[j] != rune(marker[0]) {
+			continue
+		}
+
+		if p.marker(j) != marker {
+			// the double marker inside the single one is a different style
+			j += len([]rune(p.marker(j))) - 1
+			continue
+		}
+
+		if marker[0] == '_' && j+n < len(rs) && isWordRune(rs[j+n]) {
+			continue
+		}
+
+		return j > i+n
+	}
+
+	return false
+}
+
+// canClose reports whether the marker at i closes the style, the underscores close the style only at the end of the word
+func (p *markdownParser) canClose(marker string, i int) bool {
+	n := i + len([]rune(marker))
+	return marker[0] != '_' || n >= len(p.runes) || !isWordRune(p.runes[n])
+}
+
+// link returns the index of ] and the index after ) of the link [text](url) starting at i
+func (p *markdownParser) link(i int) (end, to int, url string, ok bool) {
+	rs := p.runes
+
+	for j := i + 1; j < len(rs); j++ {
+		switch rs[j] {
+		case '\\':
+			j++
+		case '[':
+			return 0, 0, "", false
+		case ']':
+			if j+1 >= len(rs) || rs[j+1] != '(' {
+				return 0, 0, "", false
+			}
+
+			for k := j + 2; k < len(rs); k++ {
+				if rs[k] == ')' {
+					url = strings.TrimSpace(string(rs[j+2 : k]))
+					return j, k + 1, url, url != "" && j > i+1
+				}
+
+				if unicode.IsSpace(rs[k]) {
+					return 0, 0, "", false
+				}
+			}
+
+			return 0, 0, "", false
+		}
+	}
+
+	return 0, 0, "", false
+}
+
+// setStyle writes the collected text with the current style and changes the style
+func (p *markdownParser) setStyle(change func(style *MessagesTextStyle)) {
+	p.flush()
+	change(&p.style)
+}
+
+func (p *markdownParser) flush() {
+	p.builder.Styled(p.pending.String(), p.style)
+	p.pending.Reset()
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isMarkdownPunct(r rune) bool {
+	return strings.ContainsRune(`\*_[]()`, r)
+}
@@ -0,0 +1,109 @@
+package objects_test
+
+import (
+	"go-vk-sdk/constants"
+	"go-vk-sdk/objects"
+	"reflect"
+	"testing"
+)
+
+func TestMessagesTextBuilderUTF16(t *testing.T) {
+	b := objects.NewMessagesTextBuilder().
+		Bold("Заказ").
+		Bold(" №42").
+		Text(" 🎉 ").
+		Link("Подробнее", "https://example.com").
+		Styled("!", objects.MessagesTextStyle{Bold: true, Italic: true})
+
+	if text := b.String(); text != "Заказ №42 🎉 Подробнее!" {
+		t.Errorf("got text %q", text)
+	}
+
+	// the emoji takes 2 code units, the adjacent bold ranges are merged
+	want := []objects.MessagesFormatDataItem{
+		{Type: constants.FormatDataBold, Offset: 0, Length: 9},
+		{Type: constants.FormatDataURL, Offset: 13, Length: 9, URL: "https://example.com"},
+		{Type: constants.FormatDataBold, Offset: 22, Length: 1},
+		{Type: constants.FormatDataItalic, Offset: 22, Length: 1},
+	}
+
+	if items := b.FormatData().Items; !reflect.DeepEqual(items, want) {
+		t.Errorf("got items %+v, want %+v", items, want)
+	}
+}
+
+func TestFormatSpans(t *testing.T) {
+	format := &objects.MessagesFormatData{Items: []objects.MessagesFormatDataItem{
+		{Type: constants.FormatDataBold, Offset: 0, Length: 4},
+		{Type: constants.FormatDataItalic, Offset: 2, Length: 3},
+		{Type: constants.FormatDataURL, Offset: 100, Length: 2, URL: "https://example.com"},
+	}}
+
+	// 😀 is the surrogate pair, the italic starts after it
+	want := []objects.MessagesTextSpan{
+		{Text: "😀", MessagesTextStyle: objects.MessagesTextStyle{Bold: true}},
+		{Text: "ab", MessagesTextStyle: objects.MessagesTextStyle{Bold: true, Italic: true}},
+		{Text: "c", MessagesTextStyle: objects.MessagesTextStyle{Italic: true}},
+		{Text: "d"},
+	}
+
+	if spans := objects.FormatSpans("😀abcd", format); !reflect.DeepEqual(spans, want) {
+		t.Errorf("got spans %+v, want %+v", spans, want)
+	}
+
+	if spans := objects.FormatSpans("text", nil); len(spans) != 1 || spans[0].Text != "text" {
+		t.Errorf("got spans %+v without format_data", spans)
+	}
+}
+
+func TestParseMarkdown(t *testing.T) {
+	tests := []struct {
+		markdown string
+		text     string
+		items    []objects.MessagesFormatDataItem
+	}{
+		{
+			markdown: "**жирный** и *курсив*",
+			text:     "жирный и курсив",
+			items: []objects.MessagesFormatDataItem{
+				{Type: constants.FormatDataBold, Offset: 0, Length: 6},
+				{Type: constants.FormatDataItalic, Offset: 9, Length: 6},
+			},
+		},
+		{
+			markdown: "__под__ _наклон_ [ссылка](https://vk.com)",
+			text:     "под наклон ссылка",
+			items: []objects.MessagesFormatDataItem{
+				{Type: constants.FormatDataUnderline, Offset: 0, Length: 3},
+				{Type: constants.FormatDataItalic, Offset: 4, Length: 6},
+				{Type: constants.FormatDataURL, Offset: 11, Length: 6, URL: "https://vk.com"},
+			},
+		},
+		{
+			markdown: "🎉 **да**",
+			text:     "🎉 да",
+			items: []objects.MessagesFormatDataItem{
+				{Type: constants.FormatDataBold, Offset: 3, Length: 2},
+			},
+		},
+		{
+			markdown: `snake_case_name \*не курсив\* a * b **без пары`,
+			text:     "snake_case_name *не курсив* a * b **без пары",
+		},
+		{
+			markdown: "[нет ссылки] (url) [](https://vk.com)",
+			text:     "[нет ссылки] (url) [](https://vk.com)",
+		},
+	}
+
+	for _, test := range tests {
+		text, format := objects.ParseMarkdown(test.markdown)
+		if text != test.text {
+			t.Errorf("%q: got text %q, want %q", test.markdown, text, test.text)
+		}
+
+		if len(format.Items) != len(test.items) || len(test.items) > 0 && !reflect.DeepEqual(format.Items, test.items) {
+			t.Errorf("%q: got items %+v, want %+v", test.markdown, format.Items, test.items)
+		}
+	}
+}
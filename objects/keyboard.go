@@ -0,0 +1,436 @@
+package objects
+
+import (
+	"encoding/json"
+	"go-vk-sdk/constants"
+	internalErrors "go-vk-sdk/errors"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Limits of the keyboards and the carousels.
+//
+//	Doc: https://dev.vk.com/ru/api/bots/development/keyboard
+const (
+	KeyboardMaxButtonsInRow      = 5
+	KeyboardMaxRows              = 10
+	KeyboardMaxButtons           = 40
+	KeyboardInlineMaxRows        = 6
+	KeyboardInlineMaxButtons     = 10
+	KeyboardMaxLabelLength       = 40  // in characters
+	KeyboardMaxPayloadLength     = 255 // in bytes
+	CarouselMaxElements          = 10
+	CarouselMaxButtons           = 3
+	CarouselMaxTitleLength       = 80 // in characters
+	CarouselMaxDescriptionLength = 80 // in characters
+)
+
+// KeyboardBuilder Builds the keyboard of the bot and checks the limits of VK,
+// the errors of the buttons are collected and returned by Build instead of panicking.
+//
+//	keyboard, err := objects.NewKeyboardBuilder(false).
+//		AddTextButton("Yes", map[string]string{"command": "yes"}, constants.ButtonGreen).
+//		AddTextButton("No", map[string]string{"command": "no"}, constants.ButtonRed).
+//		AddRow().
+//		AddCallbackButton("More", nil, "").
+//		Build()
+type KeyboardBuilder struct {
+	keyboard MessagesKeyboard
+	noBot    bool
+	err      error
+}
+
+func NewKeyboardBuilder(oneTime bool) *KeyboardBuilder {
+	return &KeyboardBuilder{keyboard: *NewMessagesKeyboard(BoolInt(oneTime))}
+}
+
+func NewKeyboardBuilderInline() *KeyboardBuilder {
+	return &KeyboardBuilder{keyboard: *NewMessagesKeyboardInline()}
+}
+
+// Bot false if the keyboard is sent by the community without the bot capabilities,
+// callback buttons are available only to bots
+func (b *KeyboardBuilder) Bot(v bool) *KeyboardBuilder {
+	b.noBot = !v
+	return b
+}
+
+// AddRow starts the new row of the buttons
+func (b *KeyboardBuilder) AddRow() *KeyboardBuilder {
+	b.keyboard.AddRow()
+	return b
+}
+
+// AddTextButton color is one of constants.ButtonBlue, ButtonWhite, ButtonRed, ButtonGreen or empty
+func (b *KeyboardBuilder) AddTextButton(label string, payload interface{}, color string) *KeyboardBuilder {
+	return b.add(newKeyboardButton(constants.ButtonText, label, color), payload)
+}
+
+func (b *KeyboardBuilder) AddOpenLinkButton(link, label string, payload interface{}) *KeyboardBuilder {
+	button := newKeyboardButton(constants.ButtonOpenLink, label, "")
+	button.Action.Link = link
+
+	return b.add(button, payload)
+}
+
+func (b *KeyboardBuilder) AddLocationButton(payload interface{}) *KeyboardBuilder {
+	return b.add(newKeyboardButton(constants.ButtonLocation, "", ""), payload)
+}
+
+func (b *KeyboardBuilder) AddVKPayButton(payload interface{}, hash string) *KeyboardBuilder {
+	button := newKeyboardButton(constants.ButtonVKPay, "", "")
+	button.Action.Hash = hash
+
+	return b.add(button, payload)
+}
+
+func (b *KeyboardBuilder) AddVKAppsButton(appID, ownerID int, payload interface{}, label, hash string) *KeyboardBuilder {
+	button := newKeyboardButton(constants.ButtonVKApp, label, "")
+	button.Action.AppID = appID
+	button.Action.OwnerID = ownerID
+	button.Action.Hash = hash
+
+	return b.add(button, payload)
+}
+
+// AddCallbackButton color is one of constants.ButtonBlue, ButtonWhite, ButtonRed, ButtonGreen or empty
+func (b *KeyboardBuilder) AddCallbackButton(label string, payload interface{}, color string) *KeyboardBuilder {
+	return b.add(newKeyboardButton(constants.ButtonCallback, label, color), payload)
+}
+
+// Build returns the keyboard or the first error of the buttons and the limits, empty rows are removed
+func (b *KeyboardBuilder) Build() (*MessagesKeyboard, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	keyboard := b.keyboard
+	keyboard.Buttons = make([][]MessagesKeyboardButton, 0, len(b.keyboard.Buttons))
+
+	for _, row := range b.keyboard.Buttons {
+		if len(row) > 0 {
+			keyboard.Buttons = append(keyboard.Buttons, append([]MessagesKeyboardButton(nil), row...))
+		}
+	}
+
+	maxRows, maxButtons := KeyboardMaxRows, KeyboardMaxButtons
+	if keyboard.Inline {
+		maxRows, maxButtons = KeyboardInlineMaxRows, KeyboardInlineMaxButtons
+	}
+
+	if len(keyboard.Buttons) > maxRows {
+		return nil, internalErrors.Error("Objects.KeyboardBuilder.Build()",
+			"keyboard has "+strconv.Itoa(len(keyboard.Buttons))+" rows, max "+strconv.Itoa(maxRows))
+	}
+
+	count := 0
+
+	for i, row := range keyboard.Buttons {
+		if len(row) > KeyboardMaxButtonsInRow {
+			return nil, internalErrors.Error("Objects.KeyboardBuilder.Build()",
+				"row "+strconv.Itoa(i+1)+" has "+strconv.Itoa(len(row))+" buttons, max "+strconv.Itoa(KeyboardMaxButtonsInRow))
+		}
+
+		for j := range row {
+			if message := validateKeyboardButton(&row[j], !b.noBot, false); message != "" {
+				return nil, internalErrors.Error("Objects.KeyboardBuilder.Build()",
+					"button "+strconv.Itoa(j+1)+" of row "+strconv.Itoa(i+1)+": "+message)
+			}
+		}
+
+		count += len(row)
+	}
+
+	if count > maxButtons {
+		return nil, internalErrors.Error("Objects.KeyboardBuilder.Build()",
+			"keyboard has "+strconv.Itoa(count)+" buttons, max "+strconv.Itoa(maxButtons))
+	}
+
+	return &keyboard, nil
+}
+
+func (b *KeyboardBuilder) add(button MessagesKeyboardButton, payload interface{}) *KeyboardBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	button.Action.Payload, b.err = keyboardPayload(payload)
+	if b.err == nil {
+		b.keyboard.appendButton(button)
+	}
+
+	return b
+}
+
+// CarouselBuilder Builds the carousel template of the message and checks the limits of VK.
+// The buttons and the action are added to the last element, every element has the same number of buttons.
+//
+//	carousel, err := objects.NewCarouselBuilder().
+//		AddElement("Coffee", "Hot and black", "-1_457239017").
+//		OpenPhoto().
+//		AddTextButton("Buy", map[string]int{"product": 1}, constants.ButtonGreen).
+//		AddElement("Tea", "Green", "-1_457239018").
+//		OpenPhoto().
+//		AddTextButton("Buy", map[string]int{"product": 2}, constants.ButtonGreen).
+//		Build()
+//
+//	Doc: https://dev.vk.com/ru/api/bots/development/messages
+type CarouselBuilder struct {
+	template MessagesTemplate
+	noBot    bool
+	err      error
+}
+
+func NewCarouselBuilder() *CarouselBuilder {
+	return &CarouselBuilder{
+		template: MessagesTemplate{
+			Type:     constants.TemplateCarousel,
+			Elements: []MessagesTemplateElement{},
+		},
+	}
+}
+
+// Bot false if the carousel is sent by the community without the bot capabilities,
+// callback buttons are available only to bots
+func (b *CarouselBuilder) Bot(v bool) *CarouselBuilder {
+	b.noBot = !v
+	return b
+}
+
+// AddElement photoID is {owner_id}_{id} of the photo with the aspect ratio 13:8, the element has the title or the photo
+func (b *CarouselBuilder) AddElement(title, description, photoID string) *CarouselBuilder {
+	element := MessagesTemplateElement{}
+	element.Title = title
+	element.Description = description
+	element.PhotoID = photoID
+
+	b.template.Elements = append(b.template.Elements, element)
+
+	return b
+}
+
+// OpenLink sets the action of the last element which opens the link
+func (b *CarouselBuilder) OpenLink(link string) *CarouselBuilder {
+	return b.setAction(MessagesTemplateElementCarouselAction{Type: constants.CarouselActionOpenLink, Link: link})
+}
+
+// OpenPhoto sets the action of the last element which opens its photo
+func (b *CarouselBuilder) OpenPhoto() *CarouselBuilder {
+	return b.setAction(MessagesTemplateElementCarouselAction{Type: constants.CarouselActionOpenPhoto})
+}
+
+// AddTextButton color is one of constants.ButtonBlue, ButtonWhite, ButtonRed, ButtonGreen or empty
+func (b *CarouselBuilder) AddTextButton(label string, payload interface{}, color string) *CarouselBuilder {
+	return b.add(newKeyboardButton(constants.ButtonText, label, color), payload)
+}
+
+func (b *CarouselBuilder) AddOpenLinkButton(link, label string, payload interface{}) *CarouselBuilder {
+	button := newKeyboardButton(constants.ButtonOpenLink, label, "")
+	button.Action.Link = link
+
+	return b.add(button, payload)
+}
+
+// AddCallbackButton color is one of constants.ButtonBlue, ButtonWhite, ButtonRed, ButtonGreen or empty
+func (b *CarouselBuilder) AddCallbackButton(label string, payload interface{}, color string) *CarouselBuilder {
+	return b.add(newKeyboardButton(constants.ButtonCallback, label, color), payload)
+}
+
+// Build returns the template for MessagesSendRequest.Template or the first error of the elements and the limits
+func (b *CarouselBuilder) Build() (MessagesTemplate, error) {
+	if b.err != nil {
+		return MessagesTemplate{}, b.err
+	}
+
+	elements := b.template.Elements
+
+	if len(elements) == 0 {
+		return MessagesTemplate{}, internalErrors.Error("Objects.CarouselBuilder.Build()", "carousel has no elements")
+	}
+
+	if len(elements) > CarouselMaxElements {
+		return MessagesTemplate{}, internalErrors.Error("Objects.CarouselBuilder.Build()",
+			"carousel has "+strconv.Itoa(len(elements))+" elements, max "+strconv.Itoa(CarouselMaxElements))
+	}
+
+	for i := range elements {
+		if message := validateCarouselElement(&elements[i], len(elements[0].Buttons), !b.noBot); message != "" {
+			return MessagesTemplate{}, internalErrors.Error("Objects.CarouselBuilder.Build()",
+				"element "+strconv.Itoa(i+1)+": "+message)
+		}
+	}
+
+	template := b.template
+	template.Elements = append([]MessagesTemplateElement(nil), elements...)
+
+	return template, nil
+}
+
+func (b *CarouselBuilder) setAction(action MessagesTemplateElementCarouselAction) *CarouselBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	if len(b.template.Elements) == 0 {
+		b.err = internalErrors.Error("Objects.CarouselBuilder.Build()", "action "+action.Type+" is set before the first element")
+		return b
+	}
+
+	b.template.Elements[len(b.template.Elements)-1].Action = action
+
+	return b
+}
+
+func (b *CarouselBuilder) add(button MessagesKeyboardButton, payload interface{}) *CarouselBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	if len(b.template.Elements) == 0 {
+		b.err = internalErrors.Error("Objects.CarouselBuilder.Build()", "button "+button.Action.Type+" is added before the first element")
+		return b
+	}
+
+	button.Action.Payload, b.err = keyboardPayload(payload)
+	if b.err == nil {
+		last := &b.template.Elements[len(b.template.Elements)-1]
+		last.Buttons = append(last.Buttons, button)
+	}
+
+	return b
+}
+
+func newKeyboardButton(kind, label, color string) MessagesKeyboardButton {
+	return MessagesKeyboardButton{
+		Action: MessagesKeyboardButtonAction{
+			Type:  kind,
+			Label: label,
+		},
+		Color: color,
+	}
+}
+
+// keyboardPayload encodes the payload of the button to JSON, nil is encoded as the empty payload
+func keyboardPayload(payload interface{}) (string, error) {
+	if payload == nil {
+		return "", nil
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", internalErrors.Error("Objects.KeyboardBuilder.Build()", "error encoding payload "+err.Error())
+	}
+
+	return string(data), nil
+}
+
+// validateKeyboardButton returns the description of the first violated limit, empty if the button is valid
+func validateKeyboardButton(button *MessagesKeyboardButton, bot, carousel bool) string {
+	action := &button.Action
+
+	switch action.Type {
+	case constants.ButtonText, constants.ButtonCallback, constants.ButtonOpenLink:
+	case constants.ButtonLocation, constants.ButtonVKPay, constants.ButtonVKApp:
+		if carousel {
+			return "button " + action.Type + " is not supported by the carousel"
+		}
+	default:
+		return "unknown type of the button " + action.Type
+	}
+
+	if action.Type == constants.ButtonCallback && !bot {
+		return "callback buttons are available only to bots"
+	}
+
+	switch action.Type {
+	case constants.ButtonText, constants.ButtonCallback, constants.ButtonOpenLink, constants.ButtonVKApp:
+		if action.Label == "" {
+			return "label of the button " + action.Type + " is empty"
+		}
+	}
+
+	if n := utf8.RuneCountInString(action.Label); n > KeyboardMaxLabelLength {
+		return "label has " + strconv.Itoa(n) + " characters, max " + strconv.Itoa(KeyboardMaxLabelLength)
+	}
+
+	if len(action.Payload) > KeyboardMaxPayloadLength {
+		return "payload has " + strconv.Itoa(len(action.Payload)) + " bytes, max " + strconv.Itoa(KeyboardMaxPayloadLength)
+	}
+
+	switch action.Type {
+	case constants.ButtonOpenLink:
+		if action.Link == "" {
+			return "link of the button open_link is empty"
+		}
+	case constants.ButtonVKPay:
+		if action.Hash == "" {
+			return "hash of the button vkpay is empty"
+		}
+	case constants.ButtonVKApp:
+		if action.AppID <= 0 {
+			return "app_id of the button open_app is undefined"
+		}
+	}
+
+	switch button.Color {
+	case "":
+	case constants.Primary, constants.Secondary, constants.Negative, constants.Positive:
+		if action.Type != constants.ButtonText && action.Type != constants.ButtonCallback {
+			return "color is supported only by the buttons text and callback"
+		}
+	default:
+		return "unknown color of the button " + button.Color
+	}
+
+	return ""
+}
+
+// validateCarouselElement returns the description of the first violated limit, empty if the element is valid
+func validateCarouselElement(element *MessagesTemplateElement, buttons int, bot bool) string {
+	if element.Title == "" && element.PhotoID == "" {
+		return "element has neither the title nor the photo"
+	}
+
+	if n := utf8.RuneCountInString(element.Title); n > CarouselMaxTitleLength {
+		return "title has " + strconv.Itoa(n) + " characters, max " + strconv.Itoa(CarouselMaxTitleLength)
+	}
+
+	if n := utf8.RuneCountInString(element.Description); n > CarouselMaxDescriptionLength {
+		return "description has " + strconv.Itoa(n) + " characters, max " + strconv.Itoa(CarouselMaxDescriptionLength)
+	}
+
+	switch element.Action.Type {
+	case "":
+		return "action of the element is undefined, set OpenLink or OpenPhoto"
+	case constants.CarouselActionOpenLink:
+		if element.Action.Link == "" {
+			return "link of the action open_link is empty"
+		}
+	case constants.CarouselActionOpenPhoto:
+		if element.PhotoID == "" {
+			return "action open_photo requires the photo"
+		}
+	default:
+		return "unknown action " + element.Action.Type
+	}
+
+	if len(element.Buttons) == 0 {
+		return "element has no buttons"
+	}
+
+	if len(element.Buttons) > CarouselMaxButtons {
+		return "element has " + strconv.Itoa(len(element.Buttons)) + " buttons, max " + strconv.Itoa(CarouselMaxButtons)
+	}
+
+	if len(element.Buttons) != buttons {
+		return "element has " + strconv.Itoa(len(element.Buttons)) + " buttons, the first element has " + strconv.Itoa(buttons)
+	}
+
+	for i := range element.Buttons {
+		if message := validateKeyboardButton(&element.Buttons[i], bot, true); message != "" {
+			return "button " + strconv.Itoa(i+1) + ": " + message
+		}
+	}
+
+	return ""
+}
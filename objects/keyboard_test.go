@@ -0,0 +1,187 @@
+package objects_test
+
+import (
+	"go-vk-sdk/constants"
+	"go-vk-sdk/objects"
+	"strings"
+	"testing"
+)
+
+func TestKeyboardBuilder(t *testing.T) {
+	keyboard, err := objects.NewKeyboardBuilder(true).
+		AddRow().
+		AddTextButton("Yes", map[string]string{"command": "yes"}, constants.ButtonGreen).
+		AddTextButton("No", nil, constants.ButtonRed).
+		AddRow().
+		AddRow().
+		AddOpenLinkButton("https://vk.com", "Open", nil).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// empty rows are removed
+	if len(keyboard.Buttons) != 2 || len(keyboard.Buttons[0]) != 2 || len(keyboard.Buttons[1]) != 1 {
+		t.Fatalf("unexpected buttons %+v", keyboard.Buttons)
+	}
+
+	if payload := keyboard.Buttons[0][0].Action.Payload; payload != `{"command":"yes"}` {
+		t.Errorf("got payload %s", payload)
+	}
+
+	if !keyboard.OneTime || keyboard.Inline {
+		t.Errorf("got one_time %v and inline %v", keyboard.OneTime, keyboard.Inline)
+	}
+}
+
+func TestKeyboardBuilderLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *objects.KeyboardBuilder
+		err     string
+	}{
+		{
+			name:    "label",
+			builder: objects.NewKeyboardBuilder(false).AddTextButton(strings.Repeat("я", 41), nil, ""),
+			err:     "label has 41 characters",
+		},
+		{
+			name:    "payload",
+			builder: objects.NewKeyboardBuilder(false).AddTextButton("A", strings.Repeat("a", 254), ""),
+			err:     "payload has 256 bytes",
+		},
+		{
+			name:    "row",
+			builder: textButtons(objects.NewKeyboardBuilder(false), 1, 6),
+			err:     "row 1 has 6 buttons",
+		},
+		{
+			name:    "rows of the inline keyboard",
+			builder: textButtons(objects.NewKeyboardBuilderInline(), 7, 1),
+			err:     "keyboard has 7 rows, max 6",
+		},
+		{
+			name:    "buttons of the inline keyboard",
+			builder: textButtons(objects.NewKeyboardBuilderInline(), 3, 4),
+			err:     "keyboard has 12 buttons, max 10",
+		},
+		{
+			name:    "color",
+			builder: objects.NewKeyboardBuilder(false).AddTextButton("A", nil, "purple"),
+			err:     "unknown color",
+		},
+		{
+			name:    "callback without the bot",
+			builder: objects.NewKeyboardBuilder(false).Bot(false).AddCallbackButton("A", nil, ""),
+			err:     "available only to bots",
+		},
+		{
+			name:    "payload encoding",
+			builder: objects.NewKeyboardBuilder(false).AddTextButton("A", make(chan int), ""),
+			err:     "error encoding payload",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.builder.Build()
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("got error %v, want %q", err, test.err)
+			}
+		})
+	}
+
+	if _, err := textButtons(objects.NewKeyboardBuilder(false), 8, 5).Build(); err != nil {
+		t.Errorf("keyboard of 40 buttons: %v", err)
+	}
+}
+
+func textButtons(b *objects.KeyboardBuilder, rows, buttons int) *objects.KeyboardBuilder {
+	for i := 0; i < rows; i++ {
+		b.AddRow()
+		for j := 0; j < buttons; j++ {
+			b.AddTextButton("A", nil, "")
+		}
+	}
+
+	return b
+}
+
+func TestCarouselBuilder(t *testing.T) {
+	template, err := objects.NewCarouselBuilder().
+		AddElement("Coffee", "Hot", "-1_1").
+		OpenPhoto().
+		AddTextButton("Buy", map[string]int{"product": 1}, constants.ButtonGreen).
+		AddElement("Tea", "", "").
+		OpenLink("https://vk.com").
+		AddOpenLinkButton("https://vk.com", "Read", nil).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if template.Type != constants.TemplateCarousel || len(template.Elements) != 2 {
+		t.Fatalf("unexpected template %+v", template)
+	}
+
+	if buttons := template.Elements[1].Buttons; len(buttons) != 1 || buttons[0].Action.Link != "https://vk.com" {
+		t.Errorf("unexpected buttons %+v", buttons)
+	}
+}
+
+func TestCarouselBuilderErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *objects.CarouselBuilder
+		err     string
+	}{
+		{
+			name:    "no elements",
+			builder: objects.NewCarouselBuilder(),
+			err:     "carousel has no elements",
+		},
+		{
+			name:    "button before the element",
+			builder: objects.NewCarouselBuilder().AddTextButton("A", nil, "").AddElement("A", "", ""),
+			err:     "is added before the first element",
+		},
+		{
+			name:    "action before the element",
+			builder: objects.NewCarouselBuilder().OpenPhoto().AddElement("A", "", ""),
+			err:     "is set before the first element",
+		},
+		{
+			name:    "no action",
+			builder: objects.NewCarouselBuilder().AddElement("A", "", "").AddTextButton("A", nil, ""),
+			err:     "action of the element is undefined",
+		},
+		{
+			name:    "open_photo without the photo",
+			builder: objects.NewCarouselBuilder().AddElement("A", "", "").OpenPhoto().AddTextButton("A", nil, ""),
+			err:     "requires the photo",
+		},
+		{
+			name: "unequal buttons",
+			builder: objects.NewCarouselBuilder().
+				AddElement("A", "", "-1_1").OpenPhoto().AddTextButton("A", nil, "").AddTextButton("B", nil, "").
+				AddElement("B", "", "-1_2").OpenPhoto().AddTextButton("A", nil, ""),
+			err: "the first element has 2",
+		},
+		{
+			name: "too many buttons",
+			builder: objects.NewCarouselBuilder().
+				AddElement("A", "", "-1_1").OpenPhoto().
+				AddTextButton("A", nil, "").AddTextButton("B", nil, "").AddTextButton("C", nil, "").AddTextButton("D", nil, ""),
+			err: "element has 4 buttons, max 3",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.builder.Build()
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("got error %v, want %q", err, test.err)
+			}
+		})
+	}
+}
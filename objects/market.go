@@ -25,7 +25,7 @@ type MarketAlbum struct {
 }
 
 func (m *MarketAlbum) ToAttachment() string {
-	return fmt.Sprintf("market_album%d_%d", m.OwnerID, m.ID)
+	return attachmentString("market_album", m.OwnerID, m.ID, "")
 }
 
 type MarketCategory struct {
@@ -99,7 +99,7 @@ func (m *MarketItem) UnmarshalJSON(data []byte) error {
 }
 
 func (m *MarketItem) ToAttachment() string {
-	return fmt.Sprintf("m%d_%d", m.OwnerID, m.ID)
+	return attachmentString("market", m.OwnerID, m.ID, m.AccessKey)
 }
 
 type MarketItemProperty struct {
@@ -2,7 +2,6 @@ package objects
 
 import (
 	"encoding/json"
-	"fmt"
 	"go-vk-sdk/constants"
 )
 
@@ -17,7 +16,7 @@ type MessagesAudioMessage struct {
 }
 
 func (doc MessagesAudioMessage) ToAttachment() string {
-	return fmt.Sprintf("doc%d_%d", doc.OwnerID, doc.ID)
+	return attachmentString("doc", doc.OwnerID, doc.ID, doc.AccessKey)
 }
 
 type MessagesGraffiti struct {
@@ -30,7 +29,7 @@ type MessagesGraffiti struct {
 }
 
 func (doc MessagesGraffiti) ToAttachment() string {
-	return fmt.Sprintf("doc%d_%d", doc.OwnerID, doc.ID)
+	return attachmentString("doc", doc.OwnerID, doc.ID, doc.AccessKey)
 }
 
 type Message struct {
@@ -68,14 +67,15 @@ type Message struct {
 	PeerID       int              `json:"peer_id"` // Peer ID
 
 	// ID used for sending messages. It returned only for outgoing messages.
-	RandomID     int    `json:"random_id"`
-	Ref          string `json:"ref"`
-	RefSource    string `json:"ref_source"`
-	Text         string `json:"text"`          // EventType text
-	UpdateTime   int    `json:"update_time"`   // Date when the message has been updated in Unixtime
-	MembersCount int    `json:"members_count"` // Members number
-	ExpireTTL    int    `json:"expire_ttl"`
-	MessageTag   string `json:"message_tag"` // for https://notify.mail.ru/
+	RandomID     int                 `json:"random_id"`
+	Ref          string              `json:"ref"`
+	RefSource    string              `json:"ref_source"`
+	Text         string              `json:"text"`                  // EventType text
+	FormatData   *MessagesFormatData `json:"format_data,omitempty"` // Styles of the text
+	UpdateTime   int                 `json:"update_time"`           // Date when the message has been updated in Unixtime
+	MembersCount int                 `json:"members_count"`         // Members number
+	ExpireTTL    int                 `json:"expire_ttl"`
+	MessageTag   string              `json:"message_tag"` // for https://notify.mail.ru/
 }
 
 type MessagesBasePayload struct {
@@ -116,6 +116,8 @@ func (keyboard *MessagesKeyboard) AddRow() *MessagesKeyboard {
 	return keyboard
 }
 
+// AddTextButton adds the button to the last row, panics if the payload cannot be encoded to JSON.
+// KeyboardBuilder returns the error instead and checks the limits of VK
 func (keyboard *MessagesKeyboard) AddTextButton(label string, payload interface{}, color string) *MessagesKeyboard {
 	b, err := json.Marshal(payload)
 	if err != nil {
@@ -131,10 +133,7 @@ func (keyboard *MessagesKeyboard) AddTextButton(label string, payload interface{
 		Color: color,
 	}
 
-	lastRow := len(keyboard.Buttons) - 1
-	keyboard.Buttons[lastRow] = append(keyboard.Buttons[lastRow], button)
-
-	return keyboard
+	return keyboard.appendButton(button)
 }
 
 func (keyboard *MessagesKeyboard) AddOpenLinkButton(link, label string, payload interface{}) *MessagesKeyboard {
@@ -152,10 +151,7 @@ func (keyboard *MessagesKeyboard) AddOpenLinkButton(link, label string, payload
 		},
 	}
 
-	lastRow := len(keyboard.Buttons) - 1
-	keyboard.Buttons[lastRow] = append(keyboard.Buttons[lastRow], button)
-
-	return keyboard
+	return keyboard.appendButton(button)
 }
 
 func (keyboard *MessagesKeyboard) AddLocationButton(payload interface{}) *MessagesKeyboard {
@@ -171,10 +167,7 @@ func (keyboard *MessagesKeyboard) AddLocationButton(payload interface{}) *Messag
 		},
 	}
 
-	lastRow := len(keyboard.Buttons) - 1
-	keyboard.Buttons[lastRow] = append(keyboard.Buttons[lastRow], button)
-
-	return keyboard
+	return keyboard.appendButton(button)
 }
 
 func (keyboard *MessagesKeyboard) AddVKPayButton(payload interface{}, hash string) *MessagesKeyboard {
@@ -191,10 +184,7 @@ func (keyboard *MessagesKeyboard) AddVKPayButton(payload interface{}, hash strin
 		},
 	}
 
-	lastRow := len(keyboard.Buttons) - 1
-	keyboard.Buttons[lastRow] = append(keyboard.Buttons[lastRow], button)
-
-	return keyboard
+	return keyboard.appendButton(button)
 }
 
 func (keyboard *MessagesKeyboard) AddVKAppsButton(
@@ -218,10 +208,7 @@ func (keyboard *MessagesKeyboard) AddVKAppsButton(
 		},
 	}
 
-	lastRow := len(keyboard.Buttons) - 1
-	keyboard.Buttons[lastRow] = append(keyboard.Buttons[lastRow], button)
-
-	return keyboard
+	return keyboard.appendButton(button)
 }
 
 func (keyboard *MessagesKeyboard) AddCallbackButton(label string, payload interface{}, color string) *MessagesKeyboard {
@@ -239,6 +226,15 @@ func (keyboard *MessagesKeyboard) AddCallbackButton(label string, payload interf
 		Color: color,
 	}
 
+	return keyboard.appendButton(button)
+}
+
+// appendButton adds the button to the last row, the first row is added if the keyboard has no rows
+func (keyboard *MessagesKeyboard) appendButton(button MessagesKeyboardButton) *MessagesKeyboard {
+	if len(keyboard.Buttons) == 0 {
+		keyboard.AddRow()
+	}
+
 	lastRow := len(keyboard.Buttons) - 1
 	keyboard.Buttons[lastRow] = append(keyboard.Buttons[lastRow], button)
 
@@ -622,6 +618,8 @@ type MessagesMessageAttachment struct {
 	Call              MessageCall     `json:"call"`
 	Story             StoriesStory    `json:"story"`
 	Podcast           PodcastsEpisode `json:"podcast"`
+
+	Raw json.RawMessage `json:"-"` // payload of the unknown kind
 }
 
 type MessageCall struct {
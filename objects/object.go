@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strconv"
 )
 
 // Doc: https://dev.vk.com/ru/reference/objects
@@ -78,6 +79,15 @@ type Attachment interface {
 	ToAttachment() string
 }
 
+// attachmentString formats the attachment as type{owner_id}_{id}, the access key of private objects is appended as _{access_key}
+func attachmentString(kind string, ownerID, id int, accessKey string) string {
+	s := kind + strconv.Itoa(ownerID) + "_" + strconv.Itoa(id)
+	if accessKey != "" {
+		s += "_" + accessKey
+	}
+	return s
+}
+
 type City IDTitle
 type Country IDTitle
 
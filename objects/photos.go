@@ -37,7 +37,7 @@ type Photo struct {
 }
 
 func (photo *Photo) ToAttachment() string {
-	return fmt.Sprintf("photo%d_%d", photo.OwnerID, photo.ID)
+	return attachmentString("photo", photo.OwnerID, photo.ID, photo.AccessKey)
 }
 
 func (photo *Photo) MaxSize() (maxPhotoSize PhotosPhotoSizes) {
@@ -193,7 +193,7 @@ type PhotosPhotoFull struct {
 }
 
 func (photo *PhotosPhotoFull) ToAttachment() string {
-	return fmt.Sprintf("photo%d_%d", photo.OwnerID, photo.ID)
+	return attachmentString("photo", photo.OwnerID, photo.ID, photo.AccessKey)
 }
 
 func (photo *PhotosPhotoFull) MaxSize() (maxPhotoSize PhotosPhotoSizes) {
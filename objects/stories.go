@@ -134,6 +134,10 @@ type StoriesStory struct {
 	ReactionSetID        string                   `json:"reaction_set_id"`
 }
 
+func (story *StoriesStory) ToAttachment() string {
+	return attachmentString("story", story.OwnerID, story.ID, story.AccessKey)
+}
+
 type StoriesFeedItem struct {
 	Type           constants.StoriesFeedItemType `json:"type"`
 	ID             string                        `json:"id"`
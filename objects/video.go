@@ -1,9 +1,5 @@
 package objects
 
-import (
-	"fmt"
-)
-
 type Video struct {
 	// Video access key.
 	AccessKey string `json:"access_key"`
@@ -88,7 +84,7 @@ type Video struct {
 }
 
 func (video *Video) ToAttachment() string {
-	return fmt.Sprintf("video%d_%d", video.OwnerID, video.ID)
+	return attachmentString("video", video.OwnerID, video.ID, video.AccessKey)
 }
 
 type VideoRestriction struct {
@@ -242,7 +238,7 @@ type VideoFull struct {
 }
 
 func (video *VideoFull) ToAttachment() string {
-	return fmt.Sprintf("video%d_%d", video.OwnerID, video.ID)
+	return attachmentString("video", video.OwnerID, video.ID, video.AccessKey)
 }
 
 type VideoTag struct {
@@ -1,5 +1,7 @@
 package objects
 
+import "encoding/json"
+
 type WallAppPost struct {
 	ID       int    `json:"id"`
 	Name     string `json:"name"`
@@ -164,6 +166,8 @@ type WallWallpostAttachment struct {
 	Video         VideoFull         `json:"video"`
 	VideoPlaylist VideoAlbumFull    `json:"video_playlist"`
 	Podcast       PodcastsEpisode   `json:"podcast"`
+
+	Raw json.RawMessage `json:"-"` // payload of the unknown kind
 }
 
 type WallWallpostToID struct {
@@ -0,0 +1,439 @@
+package request
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-vk-sdk/actor"
+	"go-vk-sdk/api"
+	"go-vk-sdk/constants"
+	internalErrors "go-vk-sdk/errors"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BatcherFlushInterval default time a call waits in the batch before the execute call is sent
+const BatcherFlushInterval = 50 * time.Millisecond
+
+// BatchFuture Result of the request sent by the Batcher
+type BatchFuture struct {
+	ctx    context.Context
+	target interface{}
+	done   chan struct{}
+	once   sync.Once
+	err    error
+}
+
+// Wait blocks until the result is received or the context is done
+func (f *BatchFuture) Wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-f.done:
+		return f.err
+	}
+}
+
+// Done is closed when the result is received
+func (f *BatchFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Err returns the error of the request, nil until the result is received.
+// VK errors of the single call are returned as *errors.APIError
+func (f *BatchFuture) Err() error {
+	select {
+	case <-f.done:
+		return f.err
+	default:
+		return nil
+	}
+}
+
+// Response returns the target the response is decoded into
+func (f *BatchFuture) Response() interface{} {
+	return f.target
+}
+
+// resolve sets the result once, the result of the execute call is dropped if the context of the call is already done
+func (f *BatchFuture) resolve(err error) {
+	f.resolveValue(reflect.Value{}, err)
+}
+
+// resolveValue copies the decoded value into the target only if the future is not resolved yet,
+// so the target is not written after the caller has received the error of its context
+func (f *BatchFuture) resolveValue(value reflect.Value, err error) {
+	f.once.Do(func() {
+		if value.IsValid() {
+			reflect.ValueOf(f.target).Elem().Set(value.Elem())
+		}
+
+		f.err = err
+		close(f.done)
+	})
+}
+
+type batchCall struct {
+	method     string
+	parameters Parameters
+	future     *BatchFuture
+}
+
+// Batcher Coalesces ordinary requests into execute calls, so up to 25 api methods are called within one request
+// and count as one call against the rate limit.
+//
+//	The batch is sent when it has Size calls or when FlushInterval has passed since the first call was added.
+//	All requests are executed with the access token of the actor of the batcher, requests with another token are rejected.
+//	Every call receives its own part of the execute response, a failed call receives *errors.APIError with the code
+//	of its execute_errors entry, a failed execute call is reported to all calls of the batch.
+//	A call whose context is done is resolved with the error of the context at once, the execute call is cancelled
+//	when the contexts of all its calls are done.
+//	Doc: https://dev.vk.com/ru/method/execute
+type Batcher struct {
+	mtx      sync.Mutex
+	api      *api.API
+	actor    actor.Actor
+	size     int
+	interval time.Duration
+	pending  []*batchCall
+	timer    *time.Timer
+	wg       sync.WaitGroup
+	isClose  bool
+}
+
+func NewBatcher(a *api.API, actor actor.Actor) *Batcher {
+	return &Batcher{
+		api:      a,
+		actor:    actor,
+		size:     api.RequestLimitExecuteMethod,
+		interval: BatcherFlushInterval,
+	}
+}
+
+// SetSize size > 0 and <= 25, number of calls in one execute request
+func (b *Batcher) SetSize(size int) *Batcher {
+	if size > 0 && size <= api.RequestLimitExecuteMethod {
+		b.mtx.Lock()
+		b.size = size
+		b.mtx.Unlock()
+	}
+	return b
+}
+
+// SetFlushInterval interval > 0, max time a call waits for other calls of the batch
+func (b *Batcher) SetFlushInterval(interval time.Duration) *Batcher {
+	if interval > 0 {
+		b.mtx.Lock()
+		b.interval = interval
+		b.mtx.Unlock()
+	}
+	return b
+}
+
+// Add queues the request, the response is decoded into the target when the batch is executed.
+//
+//	target is a pointer to the response type of the request, for example *response.UsersGetResponse.
+//	If target is nil, a new value of the type returned by the Exec method of the request is created,
+//	it is available through BatchFuture.Response. Calls whose context is done before the batch is sent are skipped,
+//	the target is not written after the context of the call is done.
+func (b *Batcher) Add(ctx context.Context, req Request, target interface{}) *BatchFuture {
+	future := &BatchFuture{ctx: ctx, target: target, done: make(chan struct{})}
+
+	if future.target == nil {
+		future.target = newExecTarget(req)
+	}
+
+	if future.target == nil {
+		future.resolve(internalErrors.Error("Request.Batcher.Add()", "undefined response target of the request "+req.GetMethod()))
+		return future
+	}
+
+	if value := reflect.ValueOf(future.target); value.Kind() != reflect.Pointer || value.IsNil() {
+		future.resolve(internalErrors.Error("Request.Batcher.Add()", "response target of the request "+req.GetMethod()+" must be a non-nil pointer"))
+		return future
+	}
+
+	call, err := b.newCall(req, future)
+	if err != nil {
+		future.resolve(err)
+		return future
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if b.isClose {
+		future.resolve(internalErrors.Error("Request.Batcher.Add()", "batcher is already closed"))
+		return future
+	}
+
+	b.pending = append(b.pending, call)
+
+	if len(b.pending) >= b.size {
+		b.flushLocked()
+	} else if b.timer == nil {
+		b.timer = time.AfterFunc(b.interval, b.Flush)
+	}
+
+	return future
+}
+
+// Flush sends the queued calls without waiting for the batch to fill up
+func (b *Batcher) Flush() {
+	b.mtx.Lock()
+	b.flushLocked()
+	b.mtx.Unlock()
+}
+
+// Close sends the queued calls and waits until all batches are executed
+func (b *Batcher) Close() error {
+	b.mtx.Lock()
+	if b.isClose {
+		b.mtx.Unlock()
+		return internalErrors.Error("Request.Batcher.Close()", "batcher is already closed")
+	}
+	b.isClose = true
+	b.flushLocked()
+	b.mtx.Unlock()
+
+	b.wg.Wait()
+
+	return nil
+}
+
+// flushLocked must be called with the lock held
+func (b *Batcher) flushLocked() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+
+	for len(b.pending) > 0 {
+		n := min(len(b.pending), b.size)
+		batch := b.pending[:n:n]
+		b.pending = b.pending[n:]
+
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			b.execute(batch)
+		}()
+	}
+
+	b.pending = nil
+}
+
+func (b *Batcher) newCall(req Request, future *BatchFuture) (*batchCall, error) {
+	method := req.GetMethod()
+	if method == "" || method == "execute" || strings.HasPrefix(method, "execute.") {
+		return nil, internalErrors.Error("Request.Batcher.Add()", "request cannot be batched: "+method)
+	}
+
+	var parameters Parameters
+	if exec, ok := req.(interface{ execParameters() Parameters }); ok {
+		parameters = exec.execParameters().Clone()
+	} else {
+		parameters = req.GetParameters().Clone()
+	}
+
+	token := ""
+	if base, ok := req.(interface{ baseRequest() *BaseRequest }); ok {
+		base := base.baseRequest()
+		base.mtx.RLock()
+		token = base.token
+		base.mtx.RUnlock()
+	}
+
+	if token != "" && token != b.actor.GetAccessToken() {
+		return nil, internalErrors.Error("Request.Batcher.Add()", "access token of the request "+method+" differs from the token of the batcher")
+	}
+
+	// execute passes its own version and language to the inner calls, the token is sent in the header
+	parameters.Remove(constants.ParameterNameVersion)
+	parameters.Remove(constants.ParameterNameLang)
+
+	return &batchCall{method: method, parameters: parameters, future: future}, nil
+}
+
+func (b *Batcher) execute(batch []*batchCall) {
+	calls := batch[:0:0]
+	for _, call := range batch {
+		if err := call.future.ctx.Err(); err != nil {
+			call.future.resolve(err)
+			continue
+		}
+		calls = append(calls, call)
+	}
+
+	if len(calls) == 0 {
+		return
+	}
+
+	code, err := batchCode(calls)
+	if err != nil {
+		resolveAll(calls, err)
+		return
+	}
+
+	ctx, stop := batchContext(calls)
+	defer stop()
+
+	var results []json.RawMessage
+	err = NewExecuteRequest(b.api, b.actor).Code(code).Exec(ctx, &results)
+
+	var executeErrors *internalErrors.ExecuteAPIErrors
+	if err != nil && !errors.As(err, &executeErrors) {
+		resolveAll(calls, err)
+		return
+	}
+
+	if len(results) != len(calls) {
+		resolveAll(calls, internalErrors.ErrorLog("Request.Batcher.execute()", "number of results of the execute call does not match the number of calls"))
+		return
+	}
+
+	for i, err := range batchErrors(calls, results, executeErrors) {
+		call := calls[i]
+		if call.future.ctx.Err() != nil {
+			// the future is already resolved with the error of the context
+			continue
+		}
+
+		if ambiguous, ok := err.(*internalErrors.ExecuteAPIErrors); ok {
+			call.future.resolve(ambiguous)
+			continue
+		}
+
+		if err != nil {
+			call.future.resolveValue(decodeBatchResult(call.future.target, "error", err))
+			continue
+		}
+
+		call.future.resolveValue(decodeBatchResult(call.future.target, "response", results[i]))
+	}
+}
+
+// batchContext returns the context of the execute call, it is cancelled when the contexts of all calls are done.
+// The call whose context is done is resolved with the error of the context at once
+func batchContext(calls []*batchCall) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var mtx sync.Mutex
+	waiting := len(calls)
+	stops := make([]func() bool, 0, len(calls))
+
+	for _, call := range calls {
+		future := call.future
+		stops = append(stops, context.AfterFunc(future.ctx, func() {
+			future.resolve(future.ctx.Err())
+
+			mtx.Lock()
+			waiting--
+			if waiting == 0 {
+				cancel()
+			}
+			mtx.Unlock()
+		}))
+	}
+
+	return ctx, func() {
+		for _, stop := range stops {
+			stop()
+		}
+		cancel()
+	}
+}
+
+// batchErrors returns the errors of the calls, nil for the successful calls.
+//
+//	Every failed call returns false and execute_errors are listed in the order of the calls,
+//	so the errors of the method are matched with its false results by position.
+//	If a method has more false results than errors, some of the results are legitimate and its failed calls
+//	cannot be told apart, then every false result of the method receives all its errors as *errors.ExecuteAPIErrors
+func batchErrors(calls []*batchCall, results []json.RawMessage, executeErrors *internalErrors.ExecuteAPIErrors) []error {
+	failed := make(map[string][]internalErrors.ExecuteAPIError)
+	if executeErrors != nil {
+		for _, executeError := range *executeErrors {
+			failed[executeError.Method] = append(failed[executeError.Method], executeError)
+		}
+	}
+
+	falseResults := make(map[string]int)
+	for i, call := range calls {
+		if string(results[i]) == "false" {
+			falseResults[call.method]++
+		}
+	}
+
+	errs := make([]error, len(calls))
+	next := make(map[string]int)
+
+	for i, call := range calls {
+		methodErrors := failed[call.method]
+		if string(results[i]) != "false" || len(methodErrors) == 0 {
+			continue
+		}
+
+		if falseResults[call.method] > len(methodErrors) {
+			ambiguous := internalErrors.ExecuteAPIErrors(methodErrors)
+			errs[i] = &ambiguous
+			continue
+		}
+
+		executeError := methodErrors[next[call.method]]
+		next[call.method]++
+
+		errs[i] = &internalErrors.APIError{Code: executeError.Code, Message: executeError.Message}
+	}
+
+	return errs
+}
+
+// batchCode returns VKScript which calls the methods and returns the array of their results
+func batchCode(calls []*batchCall) (string, error) {
+	results := make([]interface{}, 0, len(calls))
+	for _, call := range calls {
+		args := make(map[string]interface{})
+		for key, value := range *call.parameters.BuildURLValues() {
+			args[key] = strings.Join(value, ",")
+		}
+
+		results = append(results, VKScriptCall(call.method, args))
+	}
+
+	return NewVKScript().Return(VKScriptArray(results...)).Build()
+}
+
+// decodeBatchResult decodes the part of the execute response as the envelope of the single call
+// into a new value of the type of the target, the target itself is written by BatchFuture.resolveValue
+func decodeBatchResult(target interface{}, field string, value interface{}) (reflect.Value, error) {
+	data, err := json.Marshal(map[string]interface{}{field: value})
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	decoded := reflect.New(reflect.TypeOf(target).Elem())
+	if err = json.Unmarshal(data, decoded.Interface()); err != nil {
+		return reflect.Value{}, internalErrors.ErrorLog("Request.Batcher.execute()", "error decoding JSON "+err.Error())
+	}
+
+	return decoded, responseError(decoded.Interface())
+}
+
+func resolveAll(calls []*batchCall, err error) {
+	for _, call := range calls {
+		call.future.resolve(err)
+	}
+}
+
+// newExecTarget returns a pointer to a new value of the response type of the Exec method of the request
+func newExecTarget(req Request) interface{} {
+	method := reflect.ValueOf(req).MethodByName("Exec")
+	if !method.IsValid() || method.Type().NumOut() != 2 {
+		return nil
+	}
+
+	return reflect.New(method.Type().Out(0)).Interface()
+}
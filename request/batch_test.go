@@ -0,0 +1,200 @@
+package request_test
+
+import (
+	"context"
+	"errors"
+	"go-vk-sdk/actor"
+	"go-vk-sdk/api"
+	internalErrors "go-vk-sdk/errors"
+	"go-vk-sdk/request"
+	"go-vk-sdk/response"
+	"go-vk-sdk/vktest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newServer(t *testing.T) (*vktest.Server, *api.API, actor.Actor) {
+	t.Helper()
+
+	s := vktest.NewServer()
+	t.Cleanup(s.Close)
+
+	return s, s.API(), &actor.Group{ID: 1, AccessToken: "token"}
+}
+
+// handleExecute answers the execute call with the results and the errors of the inner calls
+func handleExecute(s *vktest.Server, results []interface{}, errs ...internalErrors.ExecuteAPIError) {
+	s.Handle("execute", func(*vktest.Call) (interface{}, error) {
+		return &vktest.ExecuteResult{Response: results, Errors: errs}, nil
+	})
+}
+
+func TestBatcherDemux(t *testing.T) {
+	s, a, g := newServer(t)
+
+	handleExecute(s, []interface{}{
+		[]map[string]interface{}{{"id": 1, "first_name": "Павел"}},
+		map[string]interface{}{"type": "group", "object_id": 1},
+	})
+
+	b := request.NewBatcher(a, g).SetFlushInterval(time.Hour)
+
+	users := request.NewUsersGetRequest(a, g)
+	_ = users.GetParameters().Set("user_ids", "1")
+
+	usersFuture := b.Add(context.Background(), users, nil)
+	resolveFuture := b.Add(context.Background(), request.NewUtilsResolveScreenNameRequest(a, g).ScreenName("apiclub"), nil)
+
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := usersFuture.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := resolveFuture.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	usersResponse := usersFuture.Response().(*response.UsersGetResponse)
+	if len(usersResponse.Response) != 1 || usersResponse.Response[0].FirstName != "Павел" {
+		t.Errorf("unexpected users %+v", usersResponse.Response)
+	}
+
+	resolveResponse := resolveFuture.Response().(*response.UtilsResolveScreenNameResponse)
+	if resolveResponse.Response.Type != "group" || resolveResponse.Response.ObjectID != 1 {
+		t.Errorf("unexpected resolved name %+v", resolveResponse.Response)
+	}
+
+	calls := s.CallsOf("execute")
+	if len(calls) != 1 {
+		t.Fatalf("got %d execute calls, want 1", len(calls))
+	}
+
+	code := calls[0].Get("code")
+	if !strings.Contains(code, "API.users.get(") || !strings.Contains(code, "API.utils.resolveScreenName(") {
+		t.Errorf("unexpected code %s", code)
+	}
+}
+
+func TestBatcherErrorsByPosition(t *testing.T) {
+	s, a, g := newServer(t)
+
+	handleExecute(s,
+		[]interface{}{false, map[string]interface{}{"type": "user", "object_id": 1}, false},
+		internalErrors.ExecuteAPIError{Code: 113, Method: "utils.resolveScreenName", Message: "first"},
+		internalErrors.ExecuteAPIError{Code: 100, Method: "utils.resolveScreenName", Message: "third"},
+	)
+
+	b := request.NewBatcher(a, g).SetFlushInterval(time.Hour)
+
+	futures := make([]*request.BatchFuture, 0, 3)
+	for _, name := range []string{"a", "durov", "c"} {
+		futures = append(futures, b.Add(context.Background(), request.NewUtilsResolveScreenNameRequest(a, g).ScreenName(name), nil))
+	}
+
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range []int{113, 0, 100} {
+		err := futures[i].Wait(context.Background())
+		if want == 0 {
+			if err != nil {
+				t.Errorf("call %d: %v", i, err)
+			}
+			continue
+		}
+
+		var apiError *internalErrors.APIError
+		if !errors.As(err, &apiError) || apiError.Code != want {
+			t.Errorf("call %d: got error %v, want %d", i, err, want)
+		}
+	}
+}
+
+func TestBatcherAmbiguousErrors(t *testing.T) {
+	s, a, g := newServer(t)
+
+	// one of the false results is legitimate, the failed call cannot be told apart
+	handleExecute(s,
+		[]interface{}{false, false},
+		internalErrors.ExecuteAPIError{Code: 113, Method: "utils.resolveScreenName", Message: "invalid"},
+	)
+
+	b := request.NewBatcher(a, g).SetFlushInterval(time.Hour)
+
+	first := b.Add(context.Background(), request.NewUtilsResolveScreenNameRequest(a, g).ScreenName("a"), nil)
+	second := b.Add(context.Background(), request.NewUtilsResolveScreenNameRequest(a, g).ScreenName("b"), nil)
+
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, future := range []*request.BatchFuture{first, second} {
+		var executeErrors *internalErrors.ExecuteAPIErrors
+		if err := future.Wait(context.Background()); !errors.As(err, &executeErrors) || len(*executeErrors) != 1 {
+			t.Errorf("got error %v, want *errors.ExecuteAPIErrors", err)
+		}
+	}
+}
+
+func TestBatcherCancel(t *testing.T) {
+	s, a, g := newServer(t)
+
+	release := make(chan struct{})
+	var releaseOnce sync.Once
+	unblock := func() { releaseOnce.Do(func() { close(release) }) }
+	t.Cleanup(unblock)
+
+	s.Handle("execute", func(*vktest.Call) (interface{}, error) {
+		<-release
+		return &vktest.ExecuteResult{Response: []interface{}{map[string]interface{}{"type": "user", "object_id": 1}}}, nil
+	})
+
+	b := request.NewBatcher(a, g).SetFlushInterval(time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	target := &response.UtilsResolveScreenNameResponse{}
+	future := b.Add(ctx, request.NewUtilsResolveScreenNameRequest(a, g).ScreenName("apiclub"), target)
+	b.Flush()
+
+	for len(s.CallsOf("execute")) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+
+	select {
+	case <-future.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("the future is not resolved after its context is done")
+	}
+
+	if err := future.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+
+	// the execute call is cancelled when all its callers are gone
+	closed := make(chan error, 1)
+	go func() { closed <- b.Close() }()
+
+	unblock()
+
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the execute call is not cancelled")
+	}
+
+	// the target is not written after the caller received the error of its context
+	if target.Response.ObjectID != 0 {
+		t.Errorf("target is written after the cancel: %+v", target.Response)
+	}
+}
@@ -0,0 +1,164 @@
+package request_test
+
+import (
+	"context"
+	"errors"
+	"go-vk-sdk/actor"
+	"go-vk-sdk/api"
+	internalErrors "go-vk-sdk/errors"
+	"go-vk-sdk/request"
+	"go-vk-sdk/transport"
+	"testing"
+)
+
+// newCassetteAPI returns the api which serves the calls from testdata/cassette.json
+func newCassetteAPI(t *testing.T) (*api.API, actor.Actor) {
+	t.Helper()
+
+	cassette, err := transport.NewCassetteReplayer("testdata/cassette.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := api.NewAPI()
+	a.Client = cassette
+
+	return a, &actor.Group{ID: 1, AccessToken: "token"}
+}
+
+func TestCassetteUsersGet(t *testing.T) {
+	a, g := newCassetteAPI(t)
+
+	req := request.NewUsersGetRequest(a, g)
+	_ = req.GetParameters().Set("user_ids", "1")
+	_ = req.GetParameters().Set("fields", "screen_name,sex")
+
+	resp, err := req.Exec(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Response) != 1 {
+		t.Fatalf("got %d users, want 1", len(resp.Response))
+	}
+
+	user := resp.Response[0]
+	if user.ID != 1 || user.FirstName != "Павел" || user.LastName != "Дуров" || user.ScreenName != "id1" {
+		t.Errorf("unexpected user %d %q %q %q", user.ID, user.FirstName, user.LastName, user.ScreenName)
+	}
+}
+
+func TestCassetteUsersGetError(t *testing.T) {
+	a, g := newCassetteAPI(t)
+
+	req := request.NewUsersGetRequest(a, g)
+	_ = req.GetParameters().Set("user_ids", "0")
+
+	resp, err := req.Exec(context.Background())
+
+	var apiError *internalErrors.APIError
+	if !errors.As(err, &apiError) {
+		t.Fatalf("got error %v, want *errors.APIError", err)
+	}
+
+	if apiError.Code != 113 || resp.Error.Code != 113 {
+		t.Errorf("got codes %d and %d, want 113", apiError.Code, resp.Error.Code)
+	}
+
+	if len(apiError.RequestParams) != 4 {
+		t.Errorf("got %d request params, want 4", len(apiError.RequestParams))
+	}
+}
+
+func TestCassetteGroupsGetByID(t *testing.T) {
+	a, g := newCassetteAPI(t)
+
+	resp, err := request.NewGroupsGetByIDRequest(a, g).GroupID("apiclub").Exec(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Response.Groups) != 1 {
+		t.Fatalf("got %d groups, want 1", len(resp.Response.Groups))
+	}
+
+	group := resp.Response.Groups[0]
+	if group.ID != 1 || group.Name != "VK API" || group.ScreenName != "apiclub" || group.Type != "group" {
+		t.Errorf("unexpected group %d %q %q %q", group.ID, group.Name, group.ScreenName, group.Type)
+	}
+}
+
+func TestCassetteWallGet(t *testing.T) {
+	a, g := newCassetteAPI(t)
+
+	req := request.NewWallGetRequest(a, g)
+	_ = req.GetParameters().Set("owner_id", "-1")
+	_ = req.GetParameters().Set("count", "2")
+
+	resp, err := req.Exec(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Response.Count != 1542 || len(resp.Response.Items) != 2 {
+		t.Fatalf("got count %d and %d items, want 1542 and 2", resp.Response.Count, len(resp.Response.Items))
+	}
+
+	post := resp.Response.Items[0]
+	if post.ID != 1234 || post.OwnerID != -1 || post.Likes.Count != 345 || post.Views.Count != 20000 {
+		t.Errorf("unexpected post %d %d %d %d", post.ID, post.OwnerID, post.Likes.Count, post.Views.Count)
+	}
+
+	if len(post.Attachments) != 3 {
+		t.Fatalf("got %d attachments, want 3", len(post.Attachments))
+	}
+
+	if photo, ok := post.Attachments[0].AsPhoto(); !ok || photo.ID != 457239017 || len(photo.Sizes) != 1 {
+		t.Errorf("unexpected photo attachment %+v", post.Attachments[0])
+	}
+
+	if link, ok := post.Attachments[1].AsLink(); !ok || link.URL != "https://dev.vk.com/ru/reference/versions" {
+		t.Errorf("unexpected link attachment %+v", post.Attachments[1])
+	}
+
+	if unknown := post.Attachments[2]; unknown.Type != "textlive" || len(unknown.Raw) == 0 {
+		t.Errorf("unknown attachment kind is not kept raw: %q %s", unknown.Type, unknown.Raw)
+	}
+}
+
+func TestCassetteUtilsResolveScreenName(t *testing.T) {
+	a, g := newCassetteAPI(t)
+
+	resp, err := request.NewUtilsResolveScreenNameRequest(a, g).ScreenName("apiclub").Exec(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Response.Type != "group" || resp.Response.ObjectID != 1 {
+		t.Errorf("got %q %d, want group 1", resp.Response.Type, resp.Response.ObjectID)
+	}
+
+	// VK answers with an empty array for the unknown names
+	resp, err = request.NewUtilsResolveScreenNameRequest(a, g).ScreenName("no_such_name_42").Exec(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Response.Type != "" || resp.Response.ObjectID != 0 {
+		t.Errorf("got %q %d for the unknown name, want empty", resp.Response.Type, resp.Response.ObjectID)
+	}
+}
+
+func TestCassetteMessagesSend(t *testing.T) {
+	a, g := newCassetteAPI(t)
+
+	// random_id is generated for every send, the cassette ignores it
+	resp, err := request.NewMessagesSendRequest(a, g).PeerID(2000000001).Message("привет").Exec(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Response != 42 {
+		t.Errorf("got message id %d, want 42", resp.Response)
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"go-vk-sdk/actor"
 	"go-vk-sdk/api"
+	"go-vk-sdk/constants"
 	"go-vk-sdk/response"
+	"strconv"
+	"strings"
 )
 
 // Doc: https://dev.vk.com/ru/method/docs
@@ -123,6 +126,18 @@ func (r *DocsGetMessagesUploadServerRequest) Exec(ctx context.Context) (response
 	return
 }
 
+// Type Type of the document: doc, audio_message or graffiti
+func (r *DocsGetMessagesUploadServerRequest) Type(v string) *DocsGetMessagesUploadServerRequest {
+	r.parameters.Set(constants.ParameterNameType, v)
+	return r
+}
+
+// PeerID Destination ID of the message the document is uploaded for
+func (r *DocsGetMessagesUploadServerRequest) PeerID(id int) *DocsGetMessagesUploadServerRequest {
+	r.parameters.Set(constants.ParameterNamePeerID, strconv.Itoa(id))
+	return r
+}
+
 // DocsGetTypesRequest defines the request for docs.getTypes
 //
 // The method returns available document types for the user.
@@ -161,6 +176,12 @@ func (r *DocsGetUploadServerRequest) Exec(ctx context.Context) (response respons
 	return
 }
 
+// GroupID ID of the community the document is uploaded to
+func (r *DocsGetUploadServerRequest) GroupID(id int) *DocsGetUploadServerRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
 // DocsGetWallUploadServerRequest defines the request for docs.getWallUploadServer
 //
 // The method gets the server address for uploading a document to the Sent folder for further posting on the wall or in a private message.
@@ -180,6 +201,12 @@ func (r *DocsGetWallUploadServerRequest) Exec(ctx context.Context) (response res
 	return
 }
 
+// GroupID ID of the community to whose wall the document is uploaded
+func (r *DocsGetWallUploadServerRequest) GroupID(id int) *DocsGetWallUploadServerRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
 // DocsSaveRequest defines the request for docs.save
 //
 // The method saves the file after a successful upload to the server.
@@ -199,6 +226,34 @@ func (r *DocsSaveRequest) Exec(ctx context.Context) (response response.DocsSaveR
 	return
 }
 
+// File Parameter file returned by the upload server
+func (r *DocsSaveRequest) File(v string) *DocsSaveRequest {
+	r.parameters.Set(constants.ParameterNameFile, v)
+	return r
+}
+
+// Title Title of the document
+func (r *DocsSaveRequest) Title(v string) *DocsSaveRequest {
+	r.parameters.Set(constants.ParameterNameTitle, v)
+	return r
+}
+
+// Tags Tags for the search
+func (r *DocsSaveRequest) Tags(v ...string) *DocsSaveRequest {
+	r.parameters.Set(constants.ParameterNameTags, strings.Join(v, ","))
+	return r
+}
+
+// ReturnTags Return the tags of the document
+func (r *DocsSaveRequest) ReturnTags(v bool) *DocsSaveRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameReturnTags, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameReturnTags, "0")
+	}
+	return r
+}
+
 // DocsSearchRequest defines the request for docs.search
 //
 // The method returns search results for documents.
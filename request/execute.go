@@ -3,10 +3,13 @@ package request
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"go-vk-sdk/actor"
 	"go-vk-sdk/api"
 	"go-vk-sdk/constants"
+	internalErrors "go-vk-sdk/errors"
 	"go-vk-sdk/response"
+	"strconv"
 )
 
 // Doc: https://dev.vk.com/ru/method/execute
@@ -16,26 +19,52 @@ import (
 //	Doc: https://dev.vk.com/ru/method/execute
 type ExecuteRequest struct {
 	BaseRequest
+	nameErr error // invalid name of the stored procedure, returned by Exec
+	err     error // error of the script builder, returned by Exec
 }
 
 func NewExecuteRequest(a *api.API, actor actor.Actor) *ExecuteRequest {
-	return &ExecuteRequest{*NewMethodBaseRequest(a, actor, "execute")}
+	return &ExecuteRequest{BaseRequest: *NewMethodBaseRequest(a, actor, "execute")}
 }
 
+// NewExecuteProcedureRequest creates a new request for the stored procedure execute.<name>,
+// its arguments are passed by Arg and are available to the code as Args.<name>.
+// The name must be a VKScript identifier, otherwise Exec returns an error without calling the api
+//
+//	Doc: https://dev.vk.com/ru/method/execute
+func NewExecuteProcedureRequest(a *api.API, actor actor.Actor, name string) *ExecuteRequest {
+	r := &ExecuteRequest{BaseRequest: *NewMethodBaseRequest(a, actor, "execute."+name)}
+	if !vkscriptIdentifier.MatchString(name) {
+		r.nameErr = internalErrors.Error("Request.NewExecuteProcedureRequest()", "invalid procedure name "+strconv.Quote(name))
+	}
+
+	return r
+}
+
+// Exec executes the code and unmarshals the returned value into the target.
+//
+//	If some of the inner calls failed, the value is still unmarshalled and *errors.ExecuteAPIErrors is returned
 func (r *ExecuteRequest) Exec(ctx context.Context, target interface{}) error {
+	if r.nameErr != nil {
+		return r.nameErr
+	}
+
+	if r.err != nil {
+		return r.err
+	}
+
 	resp := &response.ExecuteResponse{}
 	err := r.PostUnmarshal(ctx, resp)
-	if err != nil {
-		return err
-	}
 
-	err = json.Unmarshal(resp.Response, &target)
-	if err != nil {
+	var executeErrors *internalErrors.ExecuteAPIErrors
+	if err != nil && !errors.As(err, &executeErrors) {
 		return err
 	}
 
-	if resp.ExecuteErrors != nil {
-		return &resp.ExecuteErrors
+	if len(resp.Response) > 0 && target != nil {
+		if decodeErr := json.Unmarshal(resp.Response, target); decodeErr != nil {
+			return decodeErr
+		}
 	}
 
 	return err
@@ -47,3 +76,21 @@ func (r *ExecuteRequest) Code(code string) *ExecuteRequest {
 	}
 	return r
 }
+
+// Script sets the code built by the VKScript builder, the error of the builder is returned by Exec
+func (r *ExecuteRequest) Script(script *VKScript) *ExecuteRequest {
+	code, err := script.Build()
+	if err != nil {
+		r.err = err
+		return r
+	}
+
+	r.err = nil
+	return r.Code(code)
+}
+
+// Arg argument of the stored procedure
+func (r *ExecuteRequest) Arg(name string, value string) *ExecuteRequest {
+	r.parameters.Set(name, value)
+	return r
+}
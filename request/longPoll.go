@@ -27,7 +27,7 @@ func NewLongPollUserRequest(a *api.API, url string) *LongPollUserRequest {
 		url:        url,
 		transport:  a.Client,
 		header:     &http.Header{},
-		parameters: &BaseParameters{},
+		parameters: NewBaseRequestParameters(),
 	}}
 
 	r.parameters.Set(constants.ParameterNameAct, "a_check")
@@ -106,7 +106,7 @@ func NewLongPollGroupRequest(a *api.API, url string) *LongPollGroupRequest {
 		url:        url,
 		transport:  a.Client,
 		header:     &http.Header{},
-		parameters: &BaseParameters{},
+		parameters: NewBaseRequestParameters(),
 	}}
 
 	r.parameters.Set(constants.ParameterNameAct, "a_check")
@@ -6,6 +6,7 @@ import (
 	"go-vk-sdk/api"
 	"go-vk-sdk/constants"
 	"go-vk-sdk/response"
+	"strconv"
 )
 
 // Doc: https://dev.vk.com/ru/method/market
@@ -605,6 +606,12 @@ func (r *MarketGetProductPhotoUploadServerRequest) Exec(ctx context.Context) (re
 	return
 }
 
+// GroupID ID of the community the photo of the product is uploaded to
+func (r *MarketGetProductPhotoUploadServerRequest) GroupID(id int) *MarketGetProductPhotoUploadServerRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
 // MarketGetPropertiesRequest defines the request for market.getProperties
 //
 // Retrieves a list of properties for the specified community.
@@ -795,6 +802,12 @@ func (r *MarketSaveProductPhotoRequest) Exec(ctx context.Context) (response resp
 	return
 }
 
+// UploadResponse Whole response of the upload server
+func (r *MarketSaveProductPhotoRequest) UploadResponse(v string) *MarketSaveProductPhotoRequest {
+	r.parameters.Set(constants.ParameterNameUploadResponse, v)
+	return r
+}
+
 // MarketSearchRequest defines the request for market.search
 //
 // Retrieves items from the community catalog.
@@ -5,8 +5,11 @@ import (
 	"go-vk-sdk/actor"
 	"go-vk-sdk/api"
 	"go-vk-sdk/constants"
+	"go-vk-sdk/objects"
 	"go-vk-sdk/response"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // Doc: https://dev.vk.com/ru/method/messages
@@ -36,6 +39,24 @@ func (r *MessagesAddChatUserRequest) Exec(ctx context.Context) (response respons
 	return
 }
 
+// ChatID sets the chat ID
+func (r *MessagesAddChatUserRequest) ChatID(id int) *MessagesAddChatUserRequest {
+	r.parameters.Set(constants.ParameterNameChatID, strconv.Itoa(id))
+	return r
+}
+
+// UserID sets the ID of the user to be added to the chat
+func (r *MessagesAddChatUserRequest) UserID(id int) *MessagesAddChatUserRequest {
+	r.parameters.Set(constants.ParameterNameUserID, strconv.Itoa(id))
+	return r
+}
+
+// VisibleMessagesCount sets the number of the last messages of the chat visible to the new user
+func (r *MessagesAddChatUserRequest) VisibleMessagesCount(v uint) *MessagesAddChatUserRequest {
+	r.parameters.Set(constants.ParameterNameVisibleMessagesCount, strconv.Itoa(int(v)))
+	return r
+}
+
 // MessagesAllowMessagesFromGroupRequest defines the request for messages.allowMessagesFromGroup
 //
 // Allows a community to send messages to the current user.
@@ -55,6 +76,18 @@ func (r *MessagesAllowMessagesFromGroupRequest) Exec(ctx context.Context) (respo
 	return
 }
 
+// GroupID sets the community ID
+func (r *MessagesAllowMessagesFromGroupRequest) GroupID(id int) *MessagesAllowMessagesFromGroupRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
+// Key sets an arbitrary string, it is passed in the message_allow event of the Callback API
+func (r *MessagesAllowMessagesFromGroupRequest) Key(v string) *MessagesAllowMessagesFromGroupRequest {
+	r.parameters.Set(constants.ParameterNameKey, v)
+	return r
+}
+
 // MessagesCreateChatRequest defines the request for messages.createChat
 //
 // Creates a chat with multiple participants.
@@ -74,6 +107,24 @@ func (r *MessagesCreateChatRequest) Exec(ctx context.Context) (response response
 	return
 }
 
+// UserIDs sets the IDs of the users to be added to the chat
+func (r *MessagesCreateChatRequest) UserIDs(ids []int) *MessagesCreateChatRequest {
+	r.parameters.Set(constants.ParameterNameUserIDs, joinInts(ids))
+	return r
+}
+
+// Title sets the chat title
+func (r *MessagesCreateChatRequest) Title(v string) *MessagesCreateChatRequest {
+	r.parameters.Set(constants.ParameterNameTitle, v)
+	return r
+}
+
+// GroupID sets the community ID (for chats created by a community)
+func (r *MessagesCreateChatRequest) GroupID(id int) *MessagesCreateChatRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
 // MessagesDeleteRequest defines the request for messages.delete
 //
 // Deletes a message.
@@ -93,6 +144,50 @@ func (r *MessagesDeleteRequest) Exec(ctx context.Context) (response response.Mes
 	return
 }
 
+// MessageIDs sets the IDs of the messages
+func (r *MessagesDeleteRequest) MessageIDs(ids []int) *MessagesDeleteRequest {
+	r.parameters.Set(constants.ParameterNameMessageIDs, joinInts(ids))
+	return r
+}
+
+// CMIDs sets the conversation message IDs
+func (r *MessagesDeleteRequest) CMIDs(ids []int) *MessagesDeleteRequest {
+	r.parameters.Set(constants.ParameterNameCMIDs, joinInts(ids))
+	return r
+}
+
+// PeerID sets the destination ID. For a user: user ID, for a chat: 2000000000 + chat ID, for a community: -community ID
+func (r *MessagesDeleteRequest) PeerID(id int) *MessagesDeleteRequest {
+	r.parameters.Set(constants.ParameterNamePeerID, strconv.Itoa(id))
+	return r
+}
+
+// Spam determines whether to mark the messages as spam
+func (r *MessagesDeleteRequest) Spam(v bool) *MessagesDeleteRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameSpam, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameSpam, "0")
+	}
+	return r
+}
+
+// DeleteForAll determines whether to delete the messages for all recipients, possible within 24 hours after sending
+func (r *MessagesDeleteRequest) DeleteForAll(v bool) *MessagesDeleteRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameDeleteForAll, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameDeleteForAll, "0")
+	}
+	return r
+}
+
+// GroupID sets the community ID (for community messages with a user access key)
+func (r *MessagesDeleteRequest) GroupID(id int) *MessagesDeleteRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
 // MessagesDeleteChatPhotoRequest defines the request for messages.deleteChatPhoto
 //
 // Deletes the chat photo.
@@ -112,6 +207,18 @@ func (r *MessagesDeleteChatPhotoRequest) Exec(ctx context.Context) (response res
 	return
 }
 
+// ChatID sets the chat ID
+func (r *MessagesDeleteChatPhotoRequest) ChatID(id int) *MessagesDeleteChatPhotoRequest {
+	r.parameters.Set(constants.ParameterNameChatID, strconv.Itoa(id))
+	return r
+}
+
+// GroupID sets the community ID (for community messages with a user access key)
+func (r *MessagesDeleteChatPhotoRequest) GroupID(id int) *MessagesDeleteChatPhotoRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
 // MessagesDeleteConversationRequest defines the request for messages.deleteConversation
 //
 // Deletes a conversation.
@@ -131,6 +238,24 @@ func (r *MessagesDeleteConversationRequest) Exec(ctx context.Context) (response
 	return
 }
 
+// UserID sets the user ID
+func (r *MessagesDeleteConversationRequest) UserID(id int) *MessagesDeleteConversationRequest {
+	r.parameters.Set(constants.ParameterNameUserID, strconv.Itoa(id))
+	return r
+}
+
+// PeerID sets the destination ID. For a user: user ID, for a chat: 2000000000 + chat ID, for a community: -community ID
+func (r *MessagesDeleteConversationRequest) PeerID(id int) *MessagesDeleteConversationRequest {
+	r.parameters.Set(constants.ParameterNamePeerID, strconv.Itoa(id))
+	return r
+}
+
+// GroupID sets the community ID (for community messages with a user access key)
+func (r *MessagesDeleteConversationRequest) GroupID(id int) *MessagesDeleteConversationRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
 // MessagesDeleteReactionRequest defines the request for messages.deleteReaction
 //
 // Deletes a previously set reaction.
@@ -150,6 +275,18 @@ func (r *MessagesDeleteReactionRequest) Exec(ctx context.Context) (response resp
 	return
 }
 
+// PeerID sets the destination ID. For a user: user ID, for a chat: 2000000000 + chat ID, for a community: -community ID
+func (r *MessagesDeleteReactionRequest) PeerID(id int) *MessagesDeleteReactionRequest {
+	r.parameters.Set(constants.ParameterNamePeerID, strconv.Itoa(id))
+	return r
+}
+
+// CMID sets the conversation message ID
+func (r *MessagesDeleteReactionRequest) CMID(id int) *MessagesDeleteReactionRequest {
+	r.parameters.Set(constants.ParameterNameCMID, strconv.Itoa(id))
+	return r
+}
+
 // MessagesDenyMessagesFromGroupRequest defines the request for messages.denyMessagesFromGroup
 //
 // Blocks a community from sending messages to the current user.
@@ -169,6 +306,12 @@ func (r *MessagesDenyMessagesFromGroupRequest) Exec(ctx context.Context) (respon
 	return
 }
 
+// GroupID sets the community ID
+func (r *MessagesDenyMessagesFromGroupRequest) GroupID(id int) *MessagesDenyMessagesFromGroupRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
 // MessagesEditRequest defines the request for messages.edit
 //
 // Edits a message.
@@ -188,6 +331,127 @@ func (r *MessagesEditRequest) Exec(ctx context.Context) (response response.Messa
 	return
 }
 
+// PeerID sets the destination ID. For a user: user ID, for a chat: 2000000000 + chat ID, for a community: -community ID
+func (r *MessagesEditRequest) PeerID(id int) *MessagesEditRequest {
+	r.parameters.Set(constants.ParameterNamePeerID, strconv.Itoa(id))
+	return r
+}
+
+// MessageID sets the ID of the message
+func (r *MessagesEditRequest) MessageID(id int) *MessagesEditRequest {
+	r.parameters.Set(constants.ParameterNameMessageID, strconv.Itoa(id))
+	return r
+}
+
+// ConversationMessageID sets the ID of the message in the conversation
+func (r *MessagesEditRequest) ConversationMessageID(id int) *MessagesEditRequest {
+	r.parameters.Set(constants.ParameterNameConversationMessageID, strconv.Itoa(id))
+	return r
+}
+
+// Message sets the text of the message, up to 4096 characters. Required if attachment is not set
+func (r *MessagesEditRequest) Message(v string) *MessagesEditRequest {
+	r.parameters.Set(constants.ParameterNameMessage, v)
+	return r
+}
+
+// FormatData sets the styles of the text of the message, empty format_data removes the parameter
+func (r *MessagesEditRequest) FormatData(format objects.MessagesFormatData) *MessagesEditRequest {
+	if len(format.Items) == 0 {
+		r.parameters.Remove(constants.ParameterNameFormatData)
+	} else {
+		r.parameters.Set(constants.ParameterNameFormatData, format.ToJSON())
+	}
+	return r
+}
+
+// FormattedMessage sets the text of the message and its format_data
+func (r *MessagesEditRequest) FormattedMessage(text *objects.MessagesTextBuilder) *MessagesEditRequest {
+	r.Message(text.String())
+	return r.FormatData(text.FormatData())
+}
+
+// Location Geographical latitude and longitude of the place
+func (r *MessagesEditRequest) Location(lat, long float64) *MessagesEditRequest {
+	r.parameters.Set(constants.ParameterNameLat, strconv.FormatFloat(lat, 'f', -1, 64))
+	r.parameters.Set(constants.ParameterNameLong, strconv.FormatFloat(long, 'f', -1, 64))
+	return r
+}
+
+// Attachment sets the media attachments of the message, objects are formatted as type{owner_id}_{id}
+func (r *MessagesEditRequest) Attachment(attachments ...objects.Attachment) *MessagesEditRequest {
+	r.parameters.Set(constants.ParameterNameAttachment, joinAttachments(attachments))
+	return r
+}
+
+// AttachmentString sets the media attachments of the message in the format type{owner_id}_{id}_{access_key}, for example photo100172_166443618
+func (r *MessagesEditRequest) AttachmentString(attachments ...string) *MessagesEditRequest {
+	r.parameters.Set(constants.ParameterNameAttachment, strings.Join(attachments, ","))
+	return r
+}
+
+// KeepForwardMessages determines whether to keep the forwarded messages
+func (r *MessagesEditRequest) KeepForwardMessages(v bool) *MessagesEditRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameKeepForwardMessages, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameKeepForwardMessages, "0")
+	}
+	return r
+}
+
+// KeepSnippets determines whether to keep the attached snippets
+func (r *MessagesEditRequest) KeepSnippets(v bool) *MessagesEditRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameKeepSnippets, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameKeepSnippets, "0")
+	}
+	return r
+}
+
+// Keyboard sets the keyboard of the bot, nil removes the parameter. To hide the keyboard send a keyboard without buttons
+func (r *MessagesEditRequest) Keyboard(keyboard *objects.MessagesKeyboard) *MessagesEditRequest {
+	if keyboard == nil {
+		r.parameters.Remove(constants.ParameterNameKeyboard)
+	} else {
+		r.parameters.Set(constants.ParameterNameKeyboard, keyboard.ToJSON())
+	}
+	return r
+}
+
+// Template sets the message template, for example a carousel
+func (r *MessagesEditRequest) Template(template objects.MessagesTemplate) *MessagesEditRequest {
+	r.parameters.Set(constants.ParameterNameTemplate, template.ToJSON())
+	return r
+}
+
+// DontParseLinks determines whether to skip the snippet of the link in the message
+func (r *MessagesEditRequest) DontParseLinks(v bool) *MessagesEditRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameDontParseLinks, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameDontParseLinks, "0")
+	}
+	return r
+}
+
+// DisableMentions determines whether to disable notifications about mentions in the message
+func (r *MessagesEditRequest) DisableMentions(v bool) *MessagesEditRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameDisableMentions, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameDisableMentions, "0")
+	}
+	return r
+}
+
+// GroupID sets the community ID (for community messages with a user access key)
+func (r *MessagesEditRequest) GroupID(id int) *MessagesEditRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
 // MessagesEditChatRequest defines the request for messages.editChat
 //
 // Changes the chat name.
@@ -207,6 +471,18 @@ func (r *MessagesEditChatRequest) Exec(ctx context.Context) (response response.M
 	return
 }
 
+// ChatID sets the chat ID
+func (r *MessagesEditChatRequest) ChatID(id int) *MessagesEditChatRequest {
+	r.parameters.Set(constants.ParameterNameChatID, strconv.Itoa(id))
+	return r
+}
+
+// Title sets the new title of the chat
+func (r *MessagesEditChatRequest) Title(v string) *MessagesEditChatRequest {
+	r.parameters.Set(constants.ParameterNameTitle, v)
+	return r
+}
+
 // MessagesGetByConversationMessageIdRequest defines the request for messages.getByConversationMessageId
 //
 // Returns messages by conversation_message_id.
@@ -226,6 +502,40 @@ func (r *MessagesGetByConversationMessageIdRequest) Exec(ctx context.Context) (r
 	return
 }
 
+// PeerID sets the destination ID. For a user: user ID, for a chat: 2000000000 + chat ID, for a community: -community ID
+func (r *MessagesGetByConversationMessageIdRequest) PeerID(id int) *MessagesGetByConversationMessageIdRequest {
+	r.parameters.Set(constants.ParameterNamePeerID, strconv.Itoa(id))
+	return r
+}
+
+// ConversationMessageIDs sets the IDs of the messages in the conversation, up to 100
+func (r *MessagesGetByConversationMessageIdRequest) ConversationMessageIDs(ids []int) *MessagesGetByConversationMessageIdRequest {
+	r.parameters.Set(constants.ParameterNameConversationMessageIDs, joinInts(ids))
+	return r
+}
+
+// Extended determines whether to return additional fields of the users and communities
+func (r *MessagesGetByConversationMessageIdRequest) Extended(v bool) *MessagesGetByConversationMessageIdRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameExtended, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameExtended, "0")
+	}
+	return r
+}
+
+// Fields sets the list of additional profile fields and community fields to return
+func (r *MessagesGetByConversationMessageIdRequest) Fields(v ...string) *MessagesGetByConversationMessageIdRequest {
+	r.parameters.Set(constants.ParameterNameFields, strings.Join(v, ","))
+	return r
+}
+
+// GroupID sets the community ID (for community messages with a user access key)
+func (r *MessagesGetByConversationMessageIdRequest) GroupID(id int) *MessagesGetByConversationMessageIdRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
 // MessagesGetByIdRequest defines the request for messages.getById
 //
 // Returns messages by their IDs.
@@ -245,6 +555,52 @@ func (r *MessagesGetByIdRequest) Exec(ctx context.Context) (response response.Me
 	return
 }
 
+// MessageIDs sets the IDs of the messages, up to 100
+func (r *MessagesGetByIdRequest) MessageIDs(ids []int) *MessagesGetByIdRequest {
+	r.parameters.Set(constants.ParameterNameMessageIDs, joinInts(ids))
+	return r
+}
+
+// CMIDs sets the conversation message IDs
+func (r *MessagesGetByIdRequest) CMIDs(ids []int) *MessagesGetByIdRequest {
+	r.parameters.Set(constants.ParameterNameCMIDs, joinInts(ids))
+	return r
+}
+
+// PeerID sets the destination ID. For a user: user ID, for a chat: 2000000000 + chat ID, for a community: -community ID
+func (r *MessagesGetByIdRequest) PeerID(id int) *MessagesGetByIdRequest {
+	r.parameters.Set(constants.ParameterNamePeerID, strconv.Itoa(id))
+	return r
+}
+
+// PreviewLength sets the number of characters after which to truncate a previewed message, 0 does not truncate
+func (r *MessagesGetByIdRequest) PreviewLength(v uint) *MessagesGetByIdRequest {
+	r.parameters.Set(constants.ParameterNamePreviewLength, strconv.Itoa(int(v)))
+	return r
+}
+
+// Extended determines whether to return additional fields of the users and communities
+func (r *MessagesGetByIdRequest) Extended(v bool) *MessagesGetByIdRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameExtended, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameExtended, "0")
+	}
+	return r
+}
+
+// Fields sets the list of additional profile fields and community fields to return
+func (r *MessagesGetByIdRequest) Fields(v ...string) *MessagesGetByIdRequest {
+	r.parameters.Set(constants.ParameterNameFields, strings.Join(v, ","))
+	return r
+}
+
+// GroupID sets the community ID (for community messages with a user access key)
+func (r *MessagesGetByIdRequest) GroupID(id int) *MessagesGetByIdRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
 // MessagesGetChatRequest defines the request for messages.getChat
 //
 // Returns information about a chat.
@@ -264,6 +620,30 @@ func (r *MessagesGetChatRequest) Exec(ctx context.Context) (response response.Me
 	return
 }
 
+// ChatID sets the chat ID
+func (r *MessagesGetChatRequest) ChatID(id int) *MessagesGetChatRequest {
+	r.parameters.Set(constants.ParameterNameChatID, strconv.Itoa(id))
+	return r
+}
+
+// ChatIDs sets the IDs of the chats
+func (r *MessagesGetChatRequest) ChatIDs(ids []int) *MessagesGetChatRequest {
+	r.parameters.Set(constants.ParameterNameChatIDs, joinInts(ids))
+	return r
+}
+
+// Fields sets the list of additional profile fields and community fields to return
+func (r *MessagesGetChatRequest) Fields(v ...string) *MessagesGetChatRequest {
+	r.parameters.Set(constants.ParameterNameFields, strings.Join(v, ","))
+	return r
+}
+
+// NameCase sets the case for declension of the user name and surname, constants.NameCase*
+func (r *MessagesGetChatRequest) NameCase(v string) *MessagesGetChatRequest {
+	r.parameters.Set(constants.ParameterNameNameCase, v)
+	return r
+}
+
 // MessagesGetChatPreviewRequest defines the request for messages.getChatPreview
 //
 // Gets data for a chat preview via invitation link.
@@ -283,6 +663,24 @@ func (r *MessagesGetChatPreviewRequest) Exec(ctx context.Context) (response resp
 	return
 }
 
+// PeerID sets the destination ID. For a user: user ID, for a chat: 2000000000 + chat ID, for a community: -community ID
+func (r *MessagesGetChatPreviewRequest) PeerID(id int) *MessagesGetChatPreviewRequest {
+	r.parameters.Set(constants.ParameterNamePeerID, strconv.Itoa(id))
+	return r
+}
+
+// Link sets the invitation link
+func (r *MessagesGetChatPreviewRequest) Link(v string) *MessagesGetChatPreviewRequest {
+	r.parameters.Set(constants.ParameterNameLink, v)
+	return r
+}
+
+// Fields sets the list of additional profile fields and community fields to return
+func (r *MessagesGetChatPreviewRequest) Fields(v ...string) *MessagesGetChatPreviewRequest {
+	r.parameters.Set(constants.ParameterNameFields, strings.Join(v, ","))
+	return r
+}
+
 // MessagesGetConversationMembersRequest defines the request for messages.getConversationMembers
 //
 // Returns the list of conversation participants.
@@ -302,6 +700,46 @@ func (r *MessagesGetConversationMembersRequest) Exec(ctx context.Context) (respo
 	return
 }
 
+// PeerID sets the destination ID. For a user: user ID, for a chat: 2000000000 + chat ID, for a community: -community ID
+func (r *MessagesGetConversationMembersRequest) PeerID(id int) *MessagesGetConversationMembersRequest {
+	r.parameters.Set(constants.ParameterNamePeerID, strconv.Itoa(id))
+	return r
+}
+
+// Offset sets the offset needed to return a specific subset of the items
+func (r *MessagesGetConversationMembersRequest) Offset(v uint) *MessagesGetConversationMembersRequest {
+	r.parameters.Set(constants.ParameterNameOffset, strconv.Itoa(int(v)))
+	return r
+}
+
+// Count sets the number of the members to return
+func (r *MessagesGetConversationMembersRequest) Count(v uint) *MessagesGetConversationMembersRequest {
+	r.parameters.Set(constants.ParameterNameCount, strconv.Itoa(int(v)))
+	return r
+}
+
+// Extended determines whether to return additional fields of the users and communities
+func (r *MessagesGetConversationMembersRequest) Extended(v bool) *MessagesGetConversationMembersRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameExtended, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameExtended, "0")
+	}
+	return r
+}
+
+// Fields sets the list of additional profile fields and community fields to return
+func (r *MessagesGetConversationMembersRequest) Fields(v ...string) *MessagesGetConversationMembersRequest {
+	r.parameters.Set(constants.ParameterNameFields, strings.Join(v, ","))
+	return r
+}
+
+// GroupID sets the community ID (for community messages with a user access key)
+func (r *MessagesGetConversationMembersRequest) GroupID(id int) *MessagesGetConversationMembersRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
 // MessagesGetConversationsRequest defines the request for messages.getConversations
 //
 // Returns the user's conversation list.
@@ -321,30 +759,104 @@ func (r *MessagesGetConversationsRequest) Exec(ctx context.Context) (response re
 	return
 }
 
-// MessagesGetConversationsByIdRequest defines the request for messages.getConversationsById
-//
-// Returns a conversation by its ID.
-// Doc: https://dev.vk.com/method/messages.getConversationsById
-type MessagesGetConversationsByIdRequest struct {
-	BaseRequest
+// Offset sets the offset needed to return a specific subset of the items
+func (r *MessagesGetConversationsRequest) Offset(v uint) *MessagesGetConversationsRequest {
+	r.parameters.Set(constants.ParameterNameOffset, strconv.Itoa(int(v)))
+	return r
 }
 
-// NewMessagesGetConversationsByIdRequest creates a new request for messages.getConversationsById
-func NewMessagesGetConversationsByIdRequest(a *api.API, actor actor.Actor) *MessagesGetConversationsByIdRequest {
-	return &MessagesGetConversationsByIdRequest{*NewMethodBaseRequest(a, actor, "messages.getConversationsById")}
+// Count sets the number of the conversations to return, up to 200. By default 20
+func (r *MessagesGetConversationsRequest) Count(v uint) *MessagesGetConversationsRequest {
+	r.parameters.Set(constants.ParameterNameCount, strconv.Itoa(int(v)))
+	return r
 }
 
-// Exec executes the request and unmarshals the response into MessagesGetConversationsByIdResponse
-func (r *MessagesGetConversationsByIdRequest) Exec(ctx context.Context) (response response.MessagesGetConversationsByIDResponse, err error) {
-	err = r.PostUnmarshal(ctx, &response)
-	return
+// Filter sets the filter of the conversations, constants.MessagesConversationsFilter*
+func (r *MessagesGetConversationsRequest) Filter(v string) *MessagesGetConversationsRequest {
+	r.parameters.Set(constants.ParameterNameFilter, v)
+	return r
 }
 
-// MessagesGetHistoryRequest defines the request for messages.getHistory
-//
-// Returns the message history for a specified dialogue.
-// Doc: https://dev.vk.com/method/messages.getHistory
-type MessagesGetHistoryRequest struct {
+// Extended determines whether to return additional fields of the users and communities
+func (r *MessagesGetConversationsRequest) Extended(v bool) *MessagesGetConversationsRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameExtended, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameExtended, "0")
+	}
+	return r
+}
+
+// StartMessageID sets the ID of the message from which to start
+func (r *MessagesGetConversationsRequest) StartMessageID(id int) *MessagesGetConversationsRequest {
+	r.parameters.Set(constants.ParameterNameStartMessageID, strconv.Itoa(id))
+	return r
+}
+
+// Fields sets the list of additional profile fields and community fields to return
+func (r *MessagesGetConversationsRequest) Fields(v ...string) *MessagesGetConversationsRequest {
+	r.parameters.Set(constants.ParameterNameFields, strings.Join(v, ","))
+	return r
+}
+
+// GroupID sets the community ID (for community messages with a user access key)
+func (r *MessagesGetConversationsRequest) GroupID(id int) *MessagesGetConversationsRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
+// MessagesGetConversationsByIdRequest defines the request for messages.getConversationsById
+//
+// Returns a conversation by its ID.
+// Doc: https://dev.vk.com/method/messages.getConversationsById
+type MessagesGetConversationsByIdRequest struct {
+	BaseRequest
+}
+
+// NewMessagesGetConversationsByIdRequest creates a new request for messages.getConversationsById
+func NewMessagesGetConversationsByIdRequest(a *api.API, actor actor.Actor) *MessagesGetConversationsByIdRequest {
+	return &MessagesGetConversationsByIdRequest{*NewMethodBaseRequest(a, actor, "messages.getConversationsById")}
+}
+
+// Exec executes the request and unmarshals the response into MessagesGetConversationsByIdResponse
+func (r *MessagesGetConversationsByIdRequest) Exec(ctx context.Context) (response response.MessagesGetConversationsByIDResponse, err error) {
+	err = r.PostUnmarshal(ctx, &response)
+	return
+}
+
+// PeerIDs sets the destination IDs, up to 100
+func (r *MessagesGetConversationsByIdRequest) PeerIDs(ids []int) *MessagesGetConversationsByIdRequest {
+	r.parameters.Set(constants.ParameterNamePeerIDs, joinInts(ids))
+	return r
+}
+
+// Extended determines whether to return additional fields of the users and communities
+func (r *MessagesGetConversationsByIdRequest) Extended(v bool) *MessagesGetConversationsByIdRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameExtended, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameExtended, "0")
+	}
+	return r
+}
+
+// Fields sets the list of additional profile fields and community fields to return
+func (r *MessagesGetConversationsByIdRequest) Fields(v ...string) *MessagesGetConversationsByIdRequest {
+	r.parameters.Set(constants.ParameterNameFields, strings.Join(v, ","))
+	return r
+}
+
+// GroupID sets the community ID (for community messages with a user access key)
+func (r *MessagesGetConversationsByIdRequest) GroupID(id int) *MessagesGetConversationsByIdRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
+// MessagesGetHistoryRequest defines the request for messages.getHistory
+//
+// Returns the message history for a specified dialogue.
+// Doc: https://dev.vk.com/method/messages.getHistory
+type MessagesGetHistoryRequest struct {
 	BaseRequest
 }
 
@@ -359,6 +871,68 @@ func (r *MessagesGetHistoryRequest) Exec(ctx context.Context) (response response
 	return
 }
 
+// PeerID sets the destination ID. For a user: user ID, for a chat: 2000000000 + chat ID, for a community: -community ID
+func (r *MessagesGetHistoryRequest) PeerID(id int) *MessagesGetHistoryRequest {
+	r.parameters.Set(constants.ParameterNamePeerID, strconv.Itoa(id))
+	return r
+}
+
+// UserID sets the ID of the user whose message history is returned
+func (r *MessagesGetHistoryRequest) UserID(id int) *MessagesGetHistoryRequest {
+	r.parameters.Set(constants.ParameterNameUserID, strconv.Itoa(id))
+	return r
+}
+
+// Offset sets the offset needed to return a specific subset of the messages, may be negative with start_message_id
+func (r *MessagesGetHistoryRequest) Offset(v int) *MessagesGetHistoryRequest {
+	r.parameters.Set(constants.ParameterNameOffset, strconv.Itoa(v))
+	return r
+}
+
+// Count sets the number of the messages to return, up to 200. By default 20
+func (r *MessagesGetHistoryRequest) Count(v uint) *MessagesGetHistoryRequest {
+	r.parameters.Set(constants.ParameterNameCount, strconv.Itoa(int(v)))
+	return r
+}
+
+// StartMessageID sets the ID of the message from which to start
+func (r *MessagesGetHistoryRequest) StartMessageID(id int) *MessagesGetHistoryRequest {
+	r.parameters.Set(constants.ParameterNameStartMessageID, strconv.Itoa(id))
+	return r
+}
+
+// Rev determines whether to return the messages in chronological order
+func (r *MessagesGetHistoryRequest) Rev(v bool) *MessagesGetHistoryRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameRev, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameRev, "0")
+	}
+	return r
+}
+
+// Extended determines whether to return additional fields of the users and communities
+func (r *MessagesGetHistoryRequest) Extended(v bool) *MessagesGetHistoryRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameExtended, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameExtended, "0")
+	}
+	return r
+}
+
+// Fields sets the list of additional profile fields and community fields to return
+func (r *MessagesGetHistoryRequest) Fields(v ...string) *MessagesGetHistoryRequest {
+	r.parameters.Set(constants.ParameterNameFields, strings.Join(v, ","))
+	return r
+}
+
+// GroupID sets the community ID (for community messages with a user access key)
+func (r *MessagesGetHistoryRequest) GroupID(id int) *MessagesGetHistoryRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
 // MessagesGetHistoryAttachmentsRequest defines the request for messages.getHistoryAttachments
 //
 // Returns attachments from a dialogue or conversation.
@@ -378,6 +952,68 @@ func (r *MessagesGetHistoryAttachmentsRequest) Exec(ctx context.Context) (respon
 	return
 }
 
+// PeerID sets the destination ID. For a user: user ID, for a chat: 2000000000 + chat ID, for a community: -community ID
+func (r *MessagesGetHistoryAttachmentsRequest) PeerID(id int) *MessagesGetHistoryAttachmentsRequest {
+	r.parameters.Set(constants.ParameterNamePeerID, strconv.Itoa(id))
+	return r
+}
+
+// MediaType sets the type of the attachments to return, constants.MessagesMediaType*
+func (r *MessagesGetHistoryAttachmentsRequest) MediaType(v string) *MessagesGetHistoryAttachmentsRequest {
+	r.parameters.Set(constants.ParameterNameMediaType, v)
+	return r
+}
+
+// StartFrom sets the offset needed to return a specific subset of the attachments, next_from of the previous response
+func (r *MessagesGetHistoryAttachmentsRequest) StartFrom(v string) *MessagesGetHistoryAttachmentsRequest {
+	r.parameters.Set(constants.ParameterNameStartFrom, v)
+	return r
+}
+
+// Count sets the number of the attachments to return, up to 200. By default 30
+func (r *MessagesGetHistoryAttachmentsRequest) Count(v uint) *MessagesGetHistoryAttachmentsRequest {
+	r.parameters.Set(constants.ParameterNameCount, strconv.Itoa(int(v)))
+	return r
+}
+
+// PhotoSizes determines whether to return the photo sizes in a special format
+func (r *MessagesGetHistoryAttachmentsRequest) PhotoSizes(v bool) *MessagesGetHistoryAttachmentsRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNamePhotoSizes, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNamePhotoSizes, "0")
+	}
+	return r
+}
+
+// Fields sets the list of additional profile fields and community fields to return
+func (r *MessagesGetHistoryAttachmentsRequest) Fields(v ...string) *MessagesGetHistoryAttachmentsRequest {
+	r.parameters.Set(constants.ParameterNameFields, strings.Join(v, ","))
+	return r
+}
+
+// GroupID sets the community ID (for community messages with a user access key)
+func (r *MessagesGetHistoryAttachmentsRequest) GroupID(id int) *MessagesGetHistoryAttachmentsRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
+// PreserveOrder determines whether to return the attachments in the order of the messages
+func (r *MessagesGetHistoryAttachmentsRequest) PreserveOrder(v bool) *MessagesGetHistoryAttachmentsRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNamePreserveOrder, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNamePreserveOrder, "0")
+	}
+	return r
+}
+
+// MaxForwardsLevel sets the max nesting level of the forwarded messages to search in, up to 45
+func (r *MessagesGetHistoryAttachmentsRequest) MaxForwardsLevel(v uint) *MessagesGetHistoryAttachmentsRequest {
+	r.parameters.Set(constants.ParameterNameMaxForwardsLevel, strconv.Itoa(int(v)))
+	return r
+}
+
 // MessagesGetImportantMessagesRequest defines the request for messages.getImportantMessages
 //
 // Returns a list of important messages for the user.
@@ -397,6 +1033,52 @@ func (r *MessagesGetImportantMessagesRequest) Exec(ctx context.Context) (respons
 	return
 }
 
+// Count sets the number of the messages to return, up to 200. By default 20
+func (r *MessagesGetImportantMessagesRequest) Count(v uint) *MessagesGetImportantMessagesRequest {
+	r.parameters.Set(constants.ParameterNameCount, strconv.Itoa(int(v)))
+	return r
+}
+
+// Offset sets the offset needed to return a specific subset of the items
+func (r *MessagesGetImportantMessagesRequest) Offset(v uint) *MessagesGetImportantMessagesRequest {
+	r.parameters.Set(constants.ParameterNameOffset, strconv.Itoa(int(v)))
+	return r
+}
+
+// StartMessageID sets the ID of the message from which to start
+func (r *MessagesGetImportantMessagesRequest) StartMessageID(id int) *MessagesGetImportantMessagesRequest {
+	r.parameters.Set(constants.ParameterNameStartMessageID, strconv.Itoa(id))
+	return r
+}
+
+// PreviewLength sets the number of characters after which to truncate a previewed message, 0 does not truncate
+func (r *MessagesGetImportantMessagesRequest) PreviewLength(v uint) *MessagesGetImportantMessagesRequest {
+	r.parameters.Set(constants.ParameterNamePreviewLength, strconv.Itoa(int(v)))
+	return r
+}
+
+// Fields sets the list of additional profile fields and community fields to return
+func (r *MessagesGetImportantMessagesRequest) Fields(v ...string) *MessagesGetImportantMessagesRequest {
+	r.parameters.Set(constants.ParameterNameFields, strings.Join(v, ","))
+	return r
+}
+
+// Extended determines whether to return additional fields of the users and communities
+func (r *MessagesGetImportantMessagesRequest) Extended(v bool) *MessagesGetImportantMessagesRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameExtended, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameExtended, "0")
+	}
+	return r
+}
+
+// GroupID sets the community ID (for community messages with a user access key)
+func (r *MessagesGetImportantMessagesRequest) GroupID(id int) *MessagesGetImportantMessagesRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
 // MessagesGetIntentUsersRequest defines the request for messages.getIntentUsers
 //
 // Returns users who subscribed to specific intents.
@@ -416,6 +1098,52 @@ func (r *MessagesGetIntentUsersRequest) Exec(ctx context.Context) (response resp
 	return
 }
 
+// Intent sets the intent of the subscription, constants.MessagesIntent*
+func (r *MessagesGetIntentUsersRequest) Intent(v string) *MessagesGetIntentUsersRequest {
+	r.parameters.Set(constants.ParameterNameIntent, v)
+	return r
+}
+
+// SubscribeID sets the ID of the subscription for the intents confirmed_notification and non_promo_newsletter
+func (r *MessagesGetIntentUsersRequest) SubscribeID(id int) *MessagesGetIntentUsersRequest {
+	r.parameters.Set(constants.ParameterNameSubscribeID, strconv.Itoa(id))
+	return r
+}
+
+// Offset sets the offset needed to return a specific subset of the items
+func (r *MessagesGetIntentUsersRequest) Offset(v uint) *MessagesGetIntentUsersRequest {
+	r.parameters.Set(constants.ParameterNameOffset, strconv.Itoa(int(v)))
+	return r
+}
+
+// Count sets the number of the users to return, up to 200. By default 20
+func (r *MessagesGetIntentUsersRequest) Count(v uint) *MessagesGetIntentUsersRequest {
+	r.parameters.Set(constants.ParameterNameCount, strconv.Itoa(int(v)))
+	return r
+}
+
+// Extended determines whether to return additional fields of the users and communities
+func (r *MessagesGetIntentUsersRequest) Extended(v bool) *MessagesGetIntentUsersRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameExtended, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameExtended, "0")
+	}
+	return r
+}
+
+// NameCase sets the cases for declension of the user names, constants.NameCase*
+func (r *MessagesGetIntentUsersRequest) NameCase(v ...string) *MessagesGetIntentUsersRequest {
+	r.parameters.Set(constants.ParameterNameNameCase, strings.Join(v, ","))
+	return r
+}
+
+// Fields sets the list of additional profile fields and community fields to return
+func (r *MessagesGetIntentUsersRequest) Fields(v ...string) *MessagesGetIntentUsersRequest {
+	r.parameters.Set(constants.ParameterNameFields, strings.Join(v, ","))
+	return r
+}
+
 // MessagesGetInviteLinkRequest defines the request for messages.getInviteLink
 //
 // Gets an invite link for the conversation.
@@ -435,6 +1163,28 @@ func (r *MessagesGetInviteLinkRequest) Exec(ctx context.Context) (response respo
 	return
 }
 
+// PeerID sets the destination ID. For a user: user ID, for a chat: 2000000000 + chat ID, for a community: -community ID
+func (r *MessagesGetInviteLinkRequest) PeerID(id int) *MessagesGetInviteLinkRequest {
+	r.parameters.Set(constants.ParameterNamePeerID, strconv.Itoa(id))
+	return r
+}
+
+// Reset determines whether to generate a new link, the previous one stops working
+func (r *MessagesGetInviteLinkRequest) Reset(v bool) *MessagesGetInviteLinkRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameReset, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameReset, "0")
+	}
+	return r
+}
+
+// GroupID sets the community ID (for community messages with a user access key)
+func (r *MessagesGetInviteLinkRequest) GroupID(id int) *MessagesGetInviteLinkRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
 // MessagesGetLastActivityRequest defines the request for messages.getLastActivity
 //
 // Gets the current status and the date of the user's last activity.
@@ -454,6 +1204,12 @@ func (r *MessagesGetLastActivityRequest) Exec(ctx context.Context) (response res
 	return
 }
 
+// UserID sets the user ID
+func (r *MessagesGetLastActivityRequest) UserID(id int) *MessagesGetLastActivityRequest {
+	r.parameters.Set(constants.ParameterNameUserID, strconv.Itoa(id))
+	return r
+}
+
 // MessagesGetLongPollHistoryRequest defines the request for messages.getLongPollHistory
 //
 // Returns updates in the user's private messages.
@@ -473,6 +1229,96 @@ func (r *MessagesGetLongPollHistoryRequest) Exec(ctx context.Context) (response
 	return
 }
 
+// Ts sets the last value of the ts parameter returned by the Long Poll server or messages.getLongPollServer
+func (r *MessagesGetLongPollHistoryRequest) Ts(v int) *MessagesGetLongPollHistoryRequest {
+	r.parameters.Set(constants.ParameterNameTs, strconv.Itoa(v))
+	return r
+}
+
+// Pts sets the last value of the new_pts parameter returned by the Long Poll server
+func (r *MessagesGetLongPollHistoryRequest) Pts(v int) *MessagesGetLongPollHistoryRequest {
+	r.parameters.Set(constants.ParameterNamePts, strconv.Itoa(v))
+	return r
+}
+
+// PreviewLength sets the number of characters after which to truncate a previewed message, 0 does not truncate
+func (r *MessagesGetLongPollHistoryRequest) PreviewLength(v uint) *MessagesGetLongPollHistoryRequest {
+	r.parameters.Set(constants.ParameterNamePreviewLength, strconv.Itoa(int(v)))
+	return r
+}
+
+// Onlines determines whether to return the history with the online and offline events
+func (r *MessagesGetLongPollHistoryRequest) Onlines(v bool) *MessagesGetLongPollHistoryRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameOnlines, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameOnlines, "0")
+	}
+	return r
+}
+
+// Fields sets the list of additional profile fields and community fields to return
+func (r *MessagesGetLongPollHistoryRequest) Fields(v ...string) *MessagesGetLongPollHistoryRequest {
+	r.parameters.Set(constants.ParameterNameFields, strings.Join(v, ","))
+	return r
+}
+
+// EventsLimit sets the max number of the events to return, at least 1000. By default 1000
+func (r *MessagesGetLongPollHistoryRequest) EventsLimit(v uint) *MessagesGetLongPollHistoryRequest {
+	r.parameters.Set(constants.ParameterNameEventsLimit, strconv.Itoa(int(v)))
+	return r
+}
+
+// MsgsLimit sets the max number of the messages to return, at least 200. By default 200
+func (r *MessagesGetLongPollHistoryRequest) MsgsLimit(v uint) *MessagesGetLongPollHistoryRequest {
+	r.parameters.Set(constants.ParameterNameMsgsLimit, strconv.Itoa(int(v)))
+	return r
+}
+
+// MaxMsgID sets the max ID of the message among the messages already available on the device
+func (r *MessagesGetLongPollHistoryRequest) MaxMsgID(id int) *MessagesGetLongPollHistoryRequest {
+	r.parameters.Set(constants.ParameterNameMaxMsgID, strconv.Itoa(id))
+	return r
+}
+
+// GroupID sets the community ID (for community messages with a user access key)
+func (r *MessagesGetLongPollHistoryRequest) GroupID(id int) *MessagesGetLongPollHistoryRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
+// LpVersion sets the version for connection to Long Poll. Current version: 3
+func (r *MessagesGetLongPollHistoryRequest) LpVersion(v int) *MessagesGetLongPollHistoryRequest {
+	r.parameters.Set(constants.ParameterNameLPVersion, strconv.Itoa(v))
+	return r
+}
+
+// LastN sets the number of the last messages of every conversation to return, up to 2000. By default 0
+func (r *MessagesGetLongPollHistoryRequest) LastN(v uint) *MessagesGetLongPollHistoryRequest {
+	r.parameters.Set(constants.ParameterNameLastN, strconv.Itoa(int(v)))
+	return r
+}
+
+// Credentials determines whether to return the key, ts and server of the Long Poll server
+func (r *MessagesGetLongPollHistoryRequest) Credentials(v bool) *MessagesGetLongPollHistoryRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameCredentials, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameCredentials, "0")
+	}
+	return r
+}
+
+// Extended determines whether to return additional fields of the users and communities
+func (r *MessagesGetLongPollHistoryRequest) Extended(v bool) *MessagesGetLongPollHistoryRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameExtended, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameExtended, "0")
+	}
+	return r
+}
+
 // MessagesGetLongPollServerRequest defines the request for messages.getLongPollServer
 //
 // Returns data required for connecting to the Long Poll server.
@@ -492,7 +1338,7 @@ func (r *MessagesGetLongPollServerRequest) Exec(ctx context.Context) (response r
 	return
 }
 
-// NeedPts Return the pts field required for the messages.getLongPollHistory method to work
+// NeedPts determines whether to return the pts field required for the messages.getLongPollHistory method to work
 func (r *MessagesGetLongPollServerRequest) NeedPts(v bool) *MessagesGetLongPollServerRequest {
 	if v {
 		r.parameters.Set(constants.ParameterNameNeedPts, "1")
@@ -502,13 +1348,13 @@ func (r *MessagesGetLongPollServerRequest) NeedPts(v bool) *MessagesGetLongPollS
 	return r
 }
 
-// GroupID Community ID (for community posts with a user access key)
+// GroupID sets the community ID (for community posts with a user access key)
 func (r *MessagesGetLongPollServerRequest) GroupID(id int) *MessagesGetLongPollServerRequest {
 	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
 	return r
 }
 
-// LpVersion version for connection to Long Poll. Current version: 3
+// LpVersion sets the version for connection to Long Poll. Current version: 3
 func (r *MessagesGetLongPollServerRequest) LpVersion(v int) *MessagesGetLongPollServerRequest {
 	r.parameters.Set(constants.ParameterNameLPVersion, strconv.Itoa(v))
 	return r
@@ -533,6 +1379,18 @@ func (r *MessagesGetMessagesReactionsRequest) Exec(ctx context.Context) (respons
 	return
 }
 
+// PeerID sets the destination ID. For a user: user ID, for a chat: 2000000000 + chat ID, for a community: -community ID
+func (r *MessagesGetMessagesReactionsRequest) PeerID(id int) *MessagesGetMessagesReactionsRequest {
+	r.parameters.Set(constants.ParameterNamePeerID, strconv.Itoa(id))
+	return r
+}
+
+// CMIDs sets the conversation message IDs
+func (r *MessagesGetMessagesReactionsRequest) CMIDs(ids []int) *MessagesGetMessagesReactionsRequest {
+	r.parameters.Set(constants.ParameterNameCMIDs, joinInts(ids))
+	return r
+}
+
 // MessagesGetReactedPeersRequest defines the request for messages.getReactedPeers
 //
 // Gets the list of users and communities that reacted to a message.
@@ -552,6 +1410,24 @@ func (r *MessagesGetReactedPeersRequest) Exec(ctx context.Context) (response res
 	return
 }
 
+// PeerID sets the destination ID. For a user: user ID, for a chat: 2000000000 + chat ID, for a community: -community ID
+func (r *MessagesGetReactedPeersRequest) PeerID(id int) *MessagesGetReactedPeersRequest {
+	r.parameters.Set(constants.ParameterNamePeerID, strconv.Itoa(id))
+	return r
+}
+
+// CMID sets the conversation message ID
+func (r *MessagesGetReactedPeersRequest) CMID(id int) *MessagesGetReactedPeersRequest {
+	r.parameters.Set(constants.ParameterNameCMID, strconv.Itoa(id))
+	return r
+}
+
+// ReactionID sets the ID of the reaction, all reactions if not set
+func (r *MessagesGetReactedPeersRequest) ReactionID(id int) *MessagesGetReactedPeersRequest {
+	r.parameters.Set(constants.ParameterNameReactionID, strconv.Itoa(id))
+	return r
+}
+
 // MessagesGetReactionsAssetsRequest defines the request for messages.getReactionsAssets
 //
 // Gets reaction assets.
@@ -571,6 +1447,12 @@ func (r *MessagesGetReactionsAssetsRequest) Exec(ctx context.Context) (response
 	return
 }
 
+// ClientVersion sets the version of the assets already available on the client
+func (r *MessagesGetReactionsAssetsRequest) ClientVersion(v int) *MessagesGetReactionsAssetsRequest {
+	r.parameters.Set(constants.ParameterNameClientVersion, strconv.Itoa(v))
+	return r
+}
+
 // MessagesIsMessagesFromGroupAllowedRequest defines the request for messages.isMessagesFromGroupAllowed
 //
 // Checks if sending messages from a group to the user is allowed.
@@ -590,6 +1472,18 @@ func (r *MessagesIsMessagesFromGroupAllowedRequest) Exec(ctx context.Context) (r
 	return
 }
 
+// GroupID sets the community ID
+func (r *MessagesIsMessagesFromGroupAllowedRequest) GroupID(id int) *MessagesIsMessagesFromGroupAllowedRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
+// UserID sets the user ID
+func (r *MessagesIsMessagesFromGroupAllowedRequest) UserID(id int) *MessagesIsMessagesFromGroupAllowedRequest {
+	r.parameters.Set(constants.ParameterNameUserID, strconv.Itoa(id))
+	return r
+}
+
 // MessagesJoinChatByInviteLinkRequest defines the request for messages.joinChatByInviteLink
 //
 // Allows joining a chat by invitation link.
@@ -609,6 +1503,12 @@ func (r *MessagesJoinChatByInviteLinkRequest) Exec(ctx context.Context) (respons
 	return
 }
 
+// Link sets the invitation link
+func (r *MessagesJoinChatByInviteLinkRequest) Link(v string) *MessagesJoinChatByInviteLinkRequest {
+	r.parameters.Set(constants.ParameterNameLink, v)
+	return r
+}
+
 // MessagesMarkAsAnsweredConversationRequest defines the request for messages.markAsAnsweredConversation
 //
 // Marks a conversation as answered or removes the mark.
@@ -628,6 +1528,28 @@ func (r *MessagesMarkAsAnsweredConversationRequest) Exec(ctx context.Context) (r
 	return
 }
 
+// PeerID sets the destination ID. For a user: user ID, for a chat: 2000000000 + chat ID, for a community: -community ID
+func (r *MessagesMarkAsAnsweredConversationRequest) PeerID(id int) *MessagesMarkAsAnsweredConversationRequest {
+	r.parameters.Set(constants.ParameterNamePeerID, strconv.Itoa(id))
+	return r
+}
+
+// Answered determines whether to mark the conversation as answered or unanswered
+func (r *MessagesMarkAsAnsweredConversationRequest) Answered(v bool) *MessagesMarkAsAnsweredConversationRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameAnswered, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameAnswered, "0")
+	}
+	return r
+}
+
+// GroupID sets the community ID (for community messages with a user access key)
+func (r *MessagesMarkAsAnsweredConversationRequest) GroupID(id int) *MessagesMarkAsAnsweredConversationRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
 // MessagesMarkAsImportantRequest defines the request for messages.markAsImportant
 //
 // Marks messages as important or removes the mark.
@@ -647,6 +1569,22 @@ func (r *MessagesMarkAsImportantRequest) Exec(ctx context.Context) (response res
 	return
 }
 
+// MessageIDs sets the IDs of the messages
+func (r *MessagesMarkAsImportantRequest) MessageIDs(ids []int) *MessagesMarkAsImportantRequest {
+	r.parameters.Set(constants.ParameterNameMessageIDs, joinInts(ids))
+	return r
+}
+
+// Important determines whether to mark the messages as important or remove the mark
+func (r *MessagesMarkAsImportantRequest) Important(v bool) *MessagesMarkAsImportantRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameImportant, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameImportant, "0")
+	}
+	return r
+}
+
 // MessagesMarkAsImportantConversationRequest defines the request for messages.markAsImportantConversation
 //
 // Marks a conversation as important or removes the mark.
@@ -660,10 +1598,32 @@ func NewMessagesMarkAsImportantConversationRequest(a *api.API, actor actor.Actor
 	return &MessagesMarkAsImportantConversationRequest{*NewMethodBaseRequest(a, actor, "messages.markAsImportantConversation")}
 }
 
-// Exec executes the request and unmarshals the response into MessagesMarkAsImportantConversationResponse
-func (r *MessagesMarkAsImportantConversationRequest) Exec(ctx context.Context) (response response.MessagesMarkAsImportantConversationResponse, err error) {
-	err = r.PostUnmarshal(ctx, &response)
-	return
+// Exec executes the request and unmarshals the response into MessagesMarkAsImportantConversationResponse
+func (r *MessagesMarkAsImportantConversationRequest) Exec(ctx context.Context) (response response.MessagesMarkAsImportantConversationResponse, err error) {
+	err = r.PostUnmarshal(ctx, &response)
+	return
+}
+
+// PeerID sets the destination ID. For a user: user ID, for a chat: 2000000000 + chat ID, for a community: -community ID
+func (r *MessagesMarkAsImportantConversationRequest) PeerID(id int) *MessagesMarkAsImportantConversationRequest {
+	r.parameters.Set(constants.ParameterNamePeerID, strconv.Itoa(id))
+	return r
+}
+
+// Important determines whether to mark the conversation as important or remove the mark
+func (r *MessagesMarkAsImportantConversationRequest) Important(v bool) *MessagesMarkAsImportantConversationRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameImportant, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameImportant, "0")
+	}
+	return r
+}
+
+// GroupID sets the community ID (for community messages with a user access key)
+func (r *MessagesMarkAsImportantConversationRequest) GroupID(id int) *MessagesMarkAsImportantConversationRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
 }
 
 // MessagesMarkAsReadRequest defines the request for messages.markAsRead
@@ -685,6 +1645,34 @@ func (r *MessagesMarkAsReadRequest) Exec(ctx context.Context) (response response
 	return
 }
 
+// PeerID sets the destination ID. For a user: user ID, for a chat: 2000000000 + chat ID, for a community: -community ID
+func (r *MessagesMarkAsReadRequest) PeerID(id int) *MessagesMarkAsReadRequest {
+	r.parameters.Set(constants.ParameterNamePeerID, strconv.Itoa(id))
+	return r
+}
+
+// StartMessageID sets the ID of the message from which to start
+func (r *MessagesMarkAsReadRequest) StartMessageID(id int) *MessagesMarkAsReadRequest {
+	r.parameters.Set(constants.ParameterNameStartMessageID, strconv.Itoa(id))
+	return r
+}
+
+// GroupID sets the community ID (for community messages with a user access key)
+func (r *MessagesMarkAsReadRequest) GroupID(id int) *MessagesMarkAsReadRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
+// MarkConversationAsRead determines whether to mark the whole conversation as read
+func (r *MessagesMarkAsReadRequest) MarkConversationAsRead(v bool) *MessagesMarkAsReadRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameMarkConversationAsRead, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameMarkConversationAsRead, "0")
+	}
+	return r
+}
+
 // MessagesMarkReactionsAsReadRequest defines the request for messages.markReactionsAsRead
 //
 // Marks all reactions on messages as read with the specified cmids.
@@ -704,6 +1692,18 @@ func (r *MessagesMarkReactionsAsReadRequest) Exec(ctx context.Context) (response
 	return
 }
 
+// PeerID sets the destination ID. For a user: user ID, for a chat: 2000000000 + chat ID, for a community: -community ID
+func (r *MessagesMarkReactionsAsReadRequest) PeerID(id int) *MessagesMarkReactionsAsReadRequest {
+	r.parameters.Set(constants.ParameterNamePeerID, strconv.Itoa(id))
+	return r
+}
+
+// CMIDs sets the conversation message IDs
+func (r *MessagesMarkReactionsAsReadRequest) CMIDs(ids []int) *MessagesMarkReactionsAsReadRequest {
+	r.parameters.Set(constants.ParameterNameCMIDs, joinInts(ids))
+	return r
+}
+
 // MessagesPinRequest defines the request for messages.pin
 //
 // Pins a message.
@@ -723,6 +1723,24 @@ func (r *MessagesPinRequest) Exec(ctx context.Context) (response response.Messag
 	return
 }
 
+// PeerID sets the destination ID. For a user: user ID, for a chat: 2000000000 + chat ID, for a community: -community ID
+func (r *MessagesPinRequest) PeerID(id int) *MessagesPinRequest {
+	r.parameters.Set(constants.ParameterNamePeerID, strconv.Itoa(id))
+	return r
+}
+
+// MessageID sets the ID of the message
+func (r *MessagesPinRequest) MessageID(id int) *MessagesPinRequest {
+	r.parameters.Set(constants.ParameterNameMessageID, strconv.Itoa(id))
+	return r
+}
+
+// ConversationMessageID sets the ID of the message in the conversation
+func (r *MessagesPinRequest) ConversationMessageID(id int) *MessagesPinRequest {
+	r.parameters.Set(constants.ParameterNameConversationMessageID, strconv.Itoa(id))
+	return r
+}
+
 // MessagesRemoveChatUserRequest defines the request for messages.removeChatUser
 //
 // Excludes a user from a chat if the current user or community is the admin or the current user invited the excluded user.
@@ -742,6 +1760,24 @@ func (r *MessagesRemoveChatUserRequest) Exec(ctx context.Context) (response resp
 	return
 }
 
+// ChatID sets the chat ID
+func (r *MessagesRemoveChatUserRequest) ChatID(id int) *MessagesRemoveChatUserRequest {
+	r.parameters.Set(constants.ParameterNameChatID, strconv.Itoa(id))
+	return r
+}
+
+// UserID sets the ID of the user to be removed from the chat
+func (r *MessagesRemoveChatUserRequest) UserID(id int) *MessagesRemoveChatUserRequest {
+	r.parameters.Set(constants.ParameterNameUserID, strconv.Itoa(id))
+	return r
+}
+
+// MemberID sets the ID of the member to be removed from the chat, negative for communities
+func (r *MessagesRemoveChatUserRequest) MemberID(id int) *MessagesRemoveChatUserRequest {
+	r.parameters.Set(constants.ParameterNameMemberID, strconv.Itoa(id))
+	return r
+}
+
 // MessagesRestoreRequest defines the request for messages.restore
 //
 // Restores a deleted message.
@@ -761,6 +1797,18 @@ func (r *MessagesRestoreRequest) Exec(ctx context.Context) (response response.Me
 	return
 }
 
+// MessageID sets the ID of the deleted message
+func (r *MessagesRestoreRequest) MessageID(id int) *MessagesRestoreRequest {
+	r.parameters.Set(constants.ParameterNameMessageID, strconv.Itoa(id))
+	return r
+}
+
+// GroupID sets the community ID (for community messages with a user access key)
+func (r *MessagesRestoreRequest) GroupID(id int) *MessagesRestoreRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
 // MessagesSearchRequest defines the request for messages.search
 //
 // Returns a list of found messages based on the search query.
@@ -780,6 +1828,64 @@ func (r *MessagesSearchRequest) Exec(ctx context.Context) (response response.Mes
 	return
 }
 
+// Query sets the search query
+func (r *MessagesSearchRequest) Query(v string) *MessagesSearchRequest {
+	r.parameters.Set(constants.ParameterNameQuery, v)
+	return r
+}
+
+// PeerID sets the destination ID. For a user: user ID, for a chat: 2000000000 + chat ID, for a community: -community ID
+func (r *MessagesSearchRequest) PeerID(id int) *MessagesSearchRequest {
+	r.parameters.Set(constants.ParameterNamePeerID, strconv.Itoa(id))
+	return r
+}
+
+// Date sets the date, only the messages sent before it are searched
+func (r *MessagesSearchRequest) Date(date time.Time) *MessagesSearchRequest {
+	r.parameters.Set(constants.ParameterNameDate, date.Format("02012006"))
+	return r
+}
+
+// PreviewLength sets the number of characters after which to truncate a previewed message, 0 does not truncate
+func (r *MessagesSearchRequest) PreviewLength(v uint) *MessagesSearchRequest {
+	r.parameters.Set(constants.ParameterNamePreviewLength, strconv.Itoa(int(v)))
+	return r
+}
+
+// Offset sets the offset needed to return a specific subset of the items
+func (r *MessagesSearchRequest) Offset(v uint) *MessagesSearchRequest {
+	r.parameters.Set(constants.ParameterNameOffset, strconv.Itoa(int(v)))
+	return r
+}
+
+// Count sets the number of the messages to return, up to 100. By default 20
+func (r *MessagesSearchRequest) Count(v uint) *MessagesSearchRequest {
+	r.parameters.Set(constants.ParameterNameCount, strconv.Itoa(int(v)))
+	return r
+}
+
+// Extended determines whether to return additional fields of the users and communities
+func (r *MessagesSearchRequest) Extended(v bool) *MessagesSearchRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameExtended, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameExtended, "0")
+	}
+	return r
+}
+
+// Fields sets the list of additional profile fields and community fields to return
+func (r *MessagesSearchRequest) Fields(v ...string) *MessagesSearchRequest {
+	r.parameters.Set(constants.ParameterNameFields, strings.Join(v, ","))
+	return r
+}
+
+// GroupID sets the community ID (for community messages with a user access key)
+func (r *MessagesSearchRequest) GroupID(id int) *MessagesSearchRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
 // MessagesSearchConversationsRequest defines the request for messages.searchConversations
 //
 // Allows searching for conversations.
@@ -799,25 +1905,270 @@ func (r *MessagesSearchConversationsRequest) Exec(ctx context.Context) (response
 	return
 }
 
+// Query sets the search query
+func (r *MessagesSearchConversationsRequest) Query(v string) *MessagesSearchConversationsRequest {
+	r.parameters.Set(constants.ParameterNameQuery, v)
+	return r
+}
+
+// Count sets the number of the conversations to return, up to 255. By default 20
+func (r *MessagesSearchConversationsRequest) Count(v uint) *MessagesSearchConversationsRequest {
+	r.parameters.Set(constants.ParameterNameCount, strconv.Itoa(int(v)))
+	return r
+}
+
+// Extended determines whether to return additional fields of the users and communities
+func (r *MessagesSearchConversationsRequest) Extended(v bool) *MessagesSearchConversationsRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameExtended, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameExtended, "0")
+	}
+	return r
+}
+
+// Fields sets the list of additional profile fields and community fields to return
+func (r *MessagesSearchConversationsRequest) Fields(v ...string) *MessagesSearchConversationsRequest {
+	r.parameters.Set(constants.ParameterNameFields, strings.Join(v, ","))
+	return r
+}
+
+// GroupID sets the community ID (for community messages with a user access key)
+func (r *MessagesSearchConversationsRequest) GroupID(id int) *MessagesSearchConversationsRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
 // MessagesSendRequest defines the request for messages.send
 //
 // Sends a message.
+// If random_id is not set, every call of Exec sends the message with a new random_id,
+// which is kept when the transport retries the call, so VK does not deliver the message twice.
 // Doc: https://dev.vk.com/method/messages.send
 type MessagesSendRequest struct {
 	BaseRequest
+	window *RandomIDWindow
 }
 
 // NewMessagesSendRequest creates a new request for messages.send
 func NewMessagesSendRequest(a *api.API, actor actor.Actor) *MessagesSendRequest {
-	return &MessagesSendRequest{*NewMethodBaseRequest(a, actor, "messages.send")}
+	return &MessagesSendRequest{BaseRequest: *NewMethodBaseRequest(a, actor, "messages.send")}
 }
 
 // Exec executes the request and unmarshals the response into MessagesSendResponse
 func (r *MessagesSendRequest) Exec(ctx context.Context) (response response.MessagesSendResponse, err error) {
-	err = r.PostUnmarshal(ctx, &response)
+	err = r.postUnmarshalParameters(ctx, r.execParameters(), &response)
 	return
 }
 
+// execParameters returns the parameters of the call with the random_id assigned and remembered by the window
+func (r *MessagesSendRequest) execParameters() Parameters {
+	parameters := r.GetParameters()
+
+	if !parameters.Has(constants.ParameterNameRandomID) {
+		parameters = parameters.Clone()
+		_ = parameters.Set(constants.ParameterNameRandomID, strconv.Itoa(NewRandomID()))
+	}
+
+	// the echo of the message may come before the response, so the random_id is remembered before the call
+	if r.window != nil {
+		id, _ := strconv.Atoi(parameters.Get(constants.ParameterNameRandomID))
+		r.window.Add(id)
+	}
+
+	return parameters
+}
+
+// IdempotencyKey sets the random_id derived from the key, the messages sent with the same key are delivered once.
+// Different keys may collide, see RandomIDFromKey
+func (r *MessagesSendRequest) IdempotencyKey(key string) *MessagesSendRequest {
+	r.parameters.Set(constants.ParameterNameRandomID, strconv.Itoa(RandomIDFromKey(key)))
+	return r
+}
+
+// Track sets the window which remembers the random_id of the sent messages to recognise their echoes in the incoming events
+func (r *MessagesSendRequest) Track(window *RandomIDWindow) *MessagesSendRequest {
+	r.window = window
+	return r
+}
+
+// UserID sets the ID of the user who receives the message
+func (r *MessagesSendRequest) UserID(id int) *MessagesSendRequest {
+	r.parameters.Set(constants.ParameterNameUserID, strconv.Itoa(id))
+	return r
+}
+
+// PeerID sets the destination ID. For a user: user ID, for a chat: 2000000000 + chat ID, for a community: -community ID
+func (r *MessagesSendRequest) PeerID(id int) *MessagesSendRequest {
+	r.parameters.Set(constants.ParameterNamePeerID, strconv.Itoa(id))
+	return r
+}
+
+// PeerIDs sets the destination IDs for sending the message to several conversations, up to 100. Only for the community token
+func (r *MessagesSendRequest) PeerIDs(ids []int) *MessagesSendRequest {
+	r.parameters.Set(constants.ParameterNamePeerIDs, joinInts(ids))
+	return r
+}
+
+// Domain sets the short address of the user, for example illarionov
+func (r *MessagesSendRequest) Domain(v string) *MessagesSendRequest {
+	r.parameters.Set(constants.ParameterNameDomain, v)
+	return r
+}
+
+// ChatID sets the ID of the chat
+func (r *MessagesSendRequest) ChatID(id int) *MessagesSendRequest {
+	r.parameters.Set(constants.ParameterNameChatID, strconv.Itoa(id))
+	return r
+}
+
+// RandomID sets the unique identifier of the message within the app, the message with the same random_id is not sent twice.
+// 0 disables the check, by default a new random_id is generated for every call of Exec
+func (r *MessagesSendRequest) RandomID(id int) *MessagesSendRequest {
+	r.parameters.Set(constants.ParameterNameRandomID, strconv.Itoa(id))
+	return r
+}
+
+// Message sets the text of the message, up to 4096 characters. Required if attachment is not set
+func (r *MessagesSendRequest) Message(v string) *MessagesSendRequest {
+	r.parameters.Set(constants.ParameterNameMessage, v)
+	return r
+}
+
+// FormatData sets the styles of the text of the message, empty format_data removes the parameter
+func (r *MessagesSendRequest) FormatData(format objects.MessagesFormatData) *MessagesSendRequest {
+	if len(format.Items) == 0 {
+		r.parameters.Remove(constants.ParameterNameFormatData)
+	} else {
+		r.parameters.Set(constants.ParameterNameFormatData, format.ToJSON())
+	}
+	return r
+}
+
+// FormattedMessage sets the text of the message and its format_data
+func (r *MessagesSendRequest) FormattedMessage(text *objects.MessagesTextBuilder) *MessagesSendRequest {
+	r.Message(text.String())
+	return r.FormatData(text.FormatData())
+}
+
+// Location Geographical latitude and longitude of the place
+func (r *MessagesSendRequest) Location(lat, long float64) *MessagesSendRequest {
+	r.parameters.Set(constants.ParameterNameLat, strconv.FormatFloat(lat, 'f', -1, 64))
+	r.parameters.Set(constants.ParameterNameLong, strconv.FormatFloat(long, 'f', -1, 64))
+	return r
+}
+
+// Attachment sets the media attachments of the message, objects are formatted as type{owner_id}_{id}
+func (r *MessagesSendRequest) Attachment(attachments ...objects.Attachment) *MessagesSendRequest {
+	r.parameters.Set(constants.ParameterNameAttachment, joinAttachments(attachments))
+	return r
+}
+
+// AttachmentString sets the media attachments of the message in the format type{owner_id}_{id}_{access_key}, for example photo100172_166443618
+func (r *MessagesSendRequest) AttachmentString(attachments ...string) *MessagesSendRequest {
+	r.parameters.Set(constants.ParameterNameAttachment, strings.Join(attachments, ","))
+	return r
+}
+
+// ReplyTo sets the ID of the message to reply to
+func (r *MessagesSendRequest) ReplyTo(v int) *MessagesSendRequest {
+	r.parameters.Set(constants.ParameterNameReplyTo, strconv.Itoa(v))
+	return r
+}
+
+// ForwardMessages sets the IDs of the forwarded messages
+func (r *MessagesSendRequest) ForwardMessages(ids []int) *MessagesSendRequest {
+	r.parameters.Set(constants.ParameterNameForwardMessages, joinInts(ids))
+	return r
+}
+
+// Forward sets the messages to forward or to reply to, it replaces forward_messages and reply_to
+func (r *MessagesSendRequest) Forward(forward objects.MessagesForward) *MessagesSendRequest {
+	r.parameters.Set(constants.ParameterNameForward, forward.ToJSON())
+	return r
+}
+
+// StickerID sets the ID of the sticker
+func (r *MessagesSendRequest) StickerID(id int) *MessagesSendRequest {
+	r.parameters.Set(constants.ParameterNameStickerID, strconv.Itoa(id))
+	return r
+}
+
+// GroupID sets the community ID (for community messages with a user access key)
+func (r *MessagesSendRequest) GroupID(id int) *MessagesSendRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
+// Keyboard sets the keyboard of the bot, nil removes the parameter. To hide the keyboard send a keyboard without buttons
+func (r *MessagesSendRequest) Keyboard(keyboard *objects.MessagesKeyboard) *MessagesSendRequest {
+	if keyboard == nil {
+		r.parameters.Remove(constants.ParameterNameKeyboard)
+	} else {
+		r.parameters.Set(constants.ParameterNameKeyboard, keyboard.ToJSON())
+	}
+	return r
+}
+
+// Template sets the message template, for example a carousel
+func (r *MessagesSendRequest) Template(template objects.MessagesTemplate) *MessagesSendRequest {
+	r.parameters.Set(constants.ParameterNameTemplate, template.ToJSON())
+	return r
+}
+
+// Payload sets the payload of the message, a JSON string up to 1000 characters
+func (r *MessagesSendRequest) Payload(v string) *MessagesSendRequest {
+	r.parameters.Set(constants.ParameterNamePayload, v)
+	return r
+}
+
+// ContentSource sets the source of the content of the message for checking the intent of the community, type message
+func (r *MessagesSendRequest) ContentSource(source objects.MessageContentSourceMessage) *MessagesSendRequest {
+	r.parameters.Set(constants.ParameterNameContentSource, objects.MessageContentSource{
+		Type:                        "message",
+		MessageContentSourceMessage: source,
+	}.ToJSON())
+	return r
+}
+
+// ContentSourceURL sets the source of the content of the message for checking the intent of the community, type url
+func (r *MessagesSendRequest) ContentSourceURL(u string) *MessagesSendRequest {
+	r.parameters.Set(constants.ParameterNameContentSource, objects.NewMessageContentSourceURL(u).ToJSON())
+	return r
+}
+
+// DontParseLinks determines whether to skip the snippet of the link in the message
+func (r *MessagesSendRequest) DontParseLinks(v bool) *MessagesSendRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameDontParseLinks, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameDontParseLinks, "0")
+	}
+	return r
+}
+
+// DisableMentions determines whether to disable notifications about mentions in the message
+func (r *MessagesSendRequest) DisableMentions(v bool) *MessagesSendRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameDisableMentions, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameDisableMentions, "0")
+	}
+	return r
+}
+
+// Intent sets the intent of the message sent by the community, constants.MessagesIntent*
+func (r *MessagesSendRequest) Intent(v string) *MessagesSendRequest {
+	r.parameters.Set(constants.ParameterNameIntent, v)
+	return r
+}
+
+// SubscribeID sets the ID of the subscription for the intents confirmed_notification and non_promo_newsletter
+func (r *MessagesSendRequest) SubscribeID(id int) *MessagesSendRequest {
+	r.parameters.Set(constants.ParameterNameSubscribeID, strconv.Itoa(id))
+	return r
+}
+
 // MessagesSendMessageEventAnswerRequest defines the request for messages.sendMessageEventAnswer
 //
 // Sends an event with an action that occurs when a callback button is pressed.
@@ -837,6 +2188,34 @@ func (r *MessagesSendMessageEventAnswerRequest) Exec(ctx context.Context) (respo
 	return
 }
 
+// EventID sets a random string, event_id of the message_event
+func (r *MessagesSendMessageEventAnswerRequest) EventID(v string) *MessagesSendMessageEventAnswerRequest {
+	r.parameters.Set(constants.ParameterNameEventID, v)
+	return r
+}
+
+// UserID sets the ID of the user who pressed the button
+func (r *MessagesSendMessageEventAnswerRequest) UserID(id int) *MessagesSendMessageEventAnswerRequest {
+	r.parameters.Set(constants.ParameterNameUserID, strconv.Itoa(id))
+	return r
+}
+
+// PeerID sets the destination ID. For a user: user ID, for a chat: 2000000000 + chat ID, for a community: -community ID
+func (r *MessagesSendMessageEventAnswerRequest) PeerID(id int) *MessagesSendMessageEventAnswerRequest {
+	r.parameters.Set(constants.ParameterNamePeerID, strconv.Itoa(id))
+	return r
+}
+
+// EventData sets the action performed after the button is pressed
+func (r *MessagesSendMessageEventAnswerRequest) EventData(eventData *objects.MessagesEventData) *MessagesSendMessageEventAnswerRequest {
+	if eventData == nil {
+		r.parameters.Remove(constants.ParameterNameEventData)
+	} else {
+		r.parameters.Set(constants.ParameterNameEventData, eventData.ToJSON())
+	}
+	return r
+}
+
 // MessagesSendReactionRequest defines the request for messages.sendReaction
 //
 // Sets a reaction to a message.
@@ -856,6 +2235,24 @@ func (r *MessagesSendReactionRequest) Exec(ctx context.Context) (response respon
 	return
 }
 
+// PeerID sets the destination ID. For a user: user ID, for a chat: 2000000000 + chat ID, for a community: -community ID
+func (r *MessagesSendReactionRequest) PeerID(id int) *MessagesSendReactionRequest {
+	r.parameters.Set(constants.ParameterNamePeerID, strconv.Itoa(id))
+	return r
+}
+
+// CMID sets the conversation message ID
+func (r *MessagesSendReactionRequest) CMID(id int) *MessagesSendReactionRequest {
+	r.parameters.Set(constants.ParameterNameCMID, strconv.Itoa(id))
+	return r
+}
+
+// ReactionID sets the ID of the reaction
+func (r *MessagesSendReactionRequest) ReactionID(id int) *MessagesSendReactionRequest {
+	r.parameters.Set(constants.ParameterNameReactionID, strconv.Itoa(id))
+	return r
+}
+
 // MessagesSetActivityRequest defines the request for messages.setActivity
 //
 // Changes the typing status of the user in a dialogue.
@@ -875,6 +2272,30 @@ func (r *MessagesSetActivityRequest) Exec(ctx context.Context) (response respons
 	return
 }
 
+// UserID sets the user ID
+func (r *MessagesSetActivityRequest) UserID(id int) *MessagesSetActivityRequest {
+	r.parameters.Set(constants.ParameterNameUserID, strconv.Itoa(id))
+	return r
+}
+
+// Type sets the type of the activity, constants.MessagesActivity*
+func (r *MessagesSetActivityRequest) Type(v string) *MessagesSetActivityRequest {
+	r.parameters.Set(constants.ParameterNameType, v)
+	return r
+}
+
+// PeerID sets the destination ID. For a user: user ID, for a chat: 2000000000 + chat ID, for a community: -community ID
+func (r *MessagesSetActivityRequest) PeerID(id int) *MessagesSetActivityRequest {
+	r.parameters.Set(constants.ParameterNamePeerID, strconv.Itoa(id))
+	return r
+}
+
+// GroupID sets the community ID (for community messages with a user access key)
+func (r *MessagesSetActivityRequest) GroupID(id int) *MessagesSetActivityRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
 // MessagesSetChatPhotoRequest defines the request for messages.setChatPhoto
 //
 // The method saves the cover of the conversation after it has been successfully uploaded to the server.
@@ -894,6 +2315,12 @@ func (r *MessagesSetChatPhotoRequest) Exec(ctx context.Context) (response respon
 	return
 }
 
+// File sets the upload result of the photo uploaded to the address from photos.getChatUploadServer
+func (r *MessagesSetChatPhotoRequest) File(v string) *MessagesSetChatPhotoRequest {
+	r.parameters.Set(constants.ParameterNameFile, v)
+	return r
+}
+
 // MessagesUnpinRequest defines the request for messages.unpin
 //
 // Unpins a message.
@@ -912,3 +2339,15 @@ func (r *MessagesUnpinRequest) Exec(ctx context.Context) (response response.Mess
 	err = r.PostUnmarshal(ctx, &response)
 	return
 }
+
+// PeerID sets the destination ID. For a user: user ID, for a chat: 2000000000 + chat ID, for a community: -community ID
+func (r *MessagesUnpinRequest) PeerID(id int) *MessagesUnpinRequest {
+	r.parameters.Set(constants.ParameterNamePeerID, strconv.Itoa(id))
+	return r
+}
+
+// GroupID sets the community ID (for community messages with a user access key)
+func (r *MessagesUnpinRequest) GroupID(id int) *MessagesUnpinRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
@@ -0,0 +1,141 @@
+package request_test
+
+import (
+	"context"
+	"encoding/json"
+	"go-vk-sdk/constants"
+	"go-vk-sdk/objects"
+	"go-vk-sdk/request"
+	"go-vk-sdk/vktest"
+	"testing"
+)
+
+func TestMessagesSendSetters(t *testing.T) {
+	s, a, g := newServer(t)
+	s.Handle("messages.send", func(*vktest.Call) (interface{}, error) {
+		return 1, nil
+	})
+
+	keyboard, err := objects.NewKeyboardBuilder(true).
+		AddRow().
+		AddTextButton("Yes", nil, constants.ButtonGreen).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = request.NewMessagesSendRequest(a, g).
+		PeerIDs([]int{1, 2, 2000000001}).
+		ForwardMessages([]int{10, 11}).
+		Attachment(
+			&objects.Photo{OwnerID: 1, ID: 2, AccessKey: "key"},
+			&objects.MarketItem{OwnerID: -1, ID: 3, AccessKey: "market"},
+			&objects.MarketAlbum{OwnerID: -1, ID: 4},
+		).
+		Forward(objects.MessagesForward{PeerID: 2000000001, ConversationMessageIDs: []int{5, 6}, IsReply: true}).
+		Keyboard(keyboard).
+		Template(objects.MessagesTemplate{
+			Type: "carousel",
+			Elements: []objects.MessagesTemplateElement{{MessagesTemplateElementCarousel: objects.MessagesTemplateElementCarousel{
+				Title:   "title",
+				PhotoID: "-1_2",
+				Action:  objects.MessagesTemplateElementCarouselAction{Type: "open_photo"},
+			}}},
+		}).
+		ContentSource(objects.MessageContentSourceMessage{OwnerID: -1, PeerID: 2, ConversationMessageID: 3}).
+		DontParseLinks(true).
+		DisableMentions(false).
+		Location(55.75, 37.6175).
+		Exec(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	call := s.LastCall("messages.send")
+
+	want := map[string]string{
+		"peer_ids":         "1,2,2000000001",
+		"forward_messages": "10,11",
+		"attachment":       "photo1_2_key,market-1_3_market,market_album-1_4",
+		"forward":          `{"peer_id":2000000001,"conversation_message_ids":[5,6],"is_reply":true}`,
+		"template":         `{"type":"carousel","elements":[{"title":"title","action":{"type":"open_photo"},"photo_id":"-1_2"}]}`,
+		"content_source":   `{"type":"message","owner_id":-1,"peer_id":2,"conversation_message_id":3}`,
+		"dont_parse_links": "1",
+		"disable_mentions": "0",
+		"lat":              "55.75",
+		"long":             "37.6175",
+	}
+
+	for name, value := range want {
+		if got := call.Get(name); got != value {
+			t.Errorf("%s: got %s, want %s", name, got, value)
+		}
+	}
+
+	var sent struct {
+		OneTime bool `json:"one_time"`
+		Buttons [][]struct {
+			Action struct {
+				Type  string `json:"type"`
+				Label string `json:"label"`
+			} `json:"action"`
+			Color string `json:"color"`
+		} `json:"buttons"`
+	}
+	if err = json.Unmarshal([]byte(call.Get("keyboard")), &sent); err != nil {
+		t.Fatal(err)
+	}
+
+	if !sent.OneTime || len(sent.Buttons) != 1 || len(sent.Buttons[0]) != 1 ||
+		sent.Buttons[0][0].Action.Label != "Yes" || sent.Buttons[0][0].Action.Type != "text" || sent.Buttons[0][0].Color != constants.ButtonGreen {
+		t.Errorf("unexpected keyboard %s", call.Get("keyboard"))
+	}
+
+	if call.Get("random_id") == "" {
+		t.Error("random_id is not assigned")
+	}
+}
+
+func TestMessagesSendParameterRemoval(t *testing.T) {
+	_, a, g := newServer(t)
+
+	r := request.NewMessagesSendRequest(a, g).
+		Keyboard(objects.NewMessagesKeyboard(false)).
+		Keyboard(nil).
+		ContentSourceURL("https://vk.com/wall-1_2")
+
+	parameters := r.GetParameters()
+	if parameters.Has("keyboard") {
+		t.Error("nil keyboard does not remove the parameter")
+	}
+
+	if got := parameters.Get("content_source"); got != `{"type":"url","url":"https://vk.com/wall-1_2"}` {
+		t.Errorf("got content_source %s", got)
+	}
+}
+
+func TestMessagesIDListSetters(t *testing.T) {
+	_, a, g := newServer(t)
+
+	tests := []struct {
+		name  string
+		param string
+		req   request.Request
+		want  string
+	}{
+		{name: "delete message_ids", param: "message_ids", req: request.NewMessagesDeleteRequest(a, g).MessageIDs([]int{1, 2}), want: "1,2"},
+		{name: "delete cmids", param: "cmids", req: request.NewMessagesDeleteRequest(a, g).CMIDs([]int{3}), want: "3"},
+		{name: "getById message_ids", param: "message_ids", req: request.NewMessagesGetByIdRequest(a, g).MessageIDs([]int{-1, 0, 5}), want: "-1,0,5"},
+		{name: "createChat user_ids", param: "user_ids", req: request.NewMessagesCreateChatRequest(a, g).UserIDs([]int{7, 8}), want: "7,8"},
+		{name: "getConversationsById peer_ids", param: "peer_ids", req: request.NewMessagesGetConversationsByIdRequest(a, g).PeerIDs([]int{2000000001}), want: "2000000001"},
+		{name: "empty list", param: "chat_ids", req: request.NewMessagesGetChatRequest(a, g).ChatIDs(nil), want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.req.GetParameters().Get(test.param); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package request
+
+import (
+	"bytes"
+	internalErrors "go-vk-sdk/errors"
+	"go-vk-sdk/objects"
+	"go-vk-sdk/transport"
+	"io"
+	"mime/multipart"
+	"sort"
+)
+
+// multipartFile File of the multipart form with the name of its form field
+type multipartFile struct {
+	field string
+	file  *objects.UploadFile
+}
+
+// multipartBody Multipart form which streams the data of the files instead of copying it into memory.
+//
+//	Only the boundaries and the headers of the parts are kept in memory: segments[i] precedes the data of files[i],
+//	the last segment contains the ordinary fields and the closing boundary.
+type multipartBody struct {
+	contentType string
+	segments    [][]byte
+	files       []io.Reader
+}
+
+func newMultipartBody(files []multipartFile, fields map[string]string) (*multipartBody, error) {
+	buf := new(bytes.Buffer)
+	writer := multipart.NewWriter(buf)
+
+	body := &multipartBody{contentType: writer.FormDataContentType()}
+
+	for _, f := range files {
+		if f.file == nil || f.file.Data == nil {
+			return nil, internalErrors.Error("Request.newMultipartBody()", "data of the file "+f.field+" is undefined")
+		}
+
+		if _, err := writer.CreateFormFile(f.field, f.file.Name); err != nil {
+			return nil, err
+		}
+
+		body.segments = append(body.segments, bytes.Clone(buf.Bytes()))
+		body.files = append(body.files, f.file.Data)
+		buf.Reset()
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := writer.WriteField(name, fields[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	body.segments = append(body.segments, bytes.Clone(buf.Bytes()))
+
+	return body, nil
+}
+
+// reader returns the body of the request and the function which releases it after the request.
+//
+//	If the data of all files implements io.Seeker, the body is *transport.ReplayableBody with the known length,
+//	so the request is sent with Content-Length and retried requests rewind the files.
+//	Otherwise the body is streamed through io.Pipe with the chunked transfer encoding and is sent only once.
+func (b *multipartBody) reader() (io.Reader, func(), error) {
+	offsets, length, ok := b.seekable()
+	if ok {
+		body, err := transport.NewReplayableBody(func() (io.Reader, error) {
+			for i, file := range b.files {
+				if _, err := file.(io.Seeker).Seek(offsets[i], io.SeekStart); err != nil {
+					return nil, err
+				}
+			}
+
+			return b.multiReader(), nil
+		}, length)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return body, func() {}, nil
+	}
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		_, err := io.Copy(pw, b.multiReader())
+		pw.CloseWithError(err)
+	}()
+
+	// closing the reader stops the copying if the request has not read the whole body
+	return pr, func() { pr.Close() }, nil
+}
+
+// seekable returns the current offsets of the files and the length of the body, ok is false if any file is not io.Seeker
+func (b *multipartBody) seekable() (offsets []int64, length int64, ok bool) {
+	for _, segment := range b.segments {
+		length += int64(len(segment))
+	}
+
+	for _, file := range b.files {
+		seeker, isSeeker := file.(io.Seeker)
+		if !isSeeker {
+			return nil, -1, false
+		}
+
+		offset, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, -1, false
+		}
+
+		end, err := seeker.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, -1, false
+		}
+
+		if _, err = seeker.Seek(offset, io.SeekStart); err != nil {
+			return nil, -1, false
+		}
+
+		offsets = append(offsets, offset)
+		length += end - offset
+	}
+
+	return offsets, length, true
+}
+
+func (b *multipartBody) multiReader() io.Reader {
+	readers := make([]io.Reader, 0, len(b.segments)+len(b.files))
+	for i, file := range b.files {
+		readers = append(readers, bytes.NewReader(b.segments[i]), file)
+	}
+
+	return io.MultiReader(append(readers, bytes.NewReader(b.segments[len(b.files)]))...)
+}
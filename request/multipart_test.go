@@ -0,0 +1,177 @@
+package request
+
+import (
+	"bytes"
+	"errors"
+	"go-vk-sdk/objects"
+	"go-vk-sdk/transport"
+	"io"
+	"mime"
+	"mime/multipart"
+	"strings"
+	"testing"
+)
+
+// readMultipart reads the body and returns the parts by the names of their fields
+func readMultipart(t *testing.T, contentType string, body []byte) map[string]string {
+	t.Helper()
+
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.HasSuffix(body, []byte("\r\n--"+params["boundary"]+"--\r\n")) {
+		t.Fatalf("body %q has no closing boundary", body)
+	}
+
+	parts := make(map[string]string)
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return parts
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		name := part.FormName()
+		if part.FileName() != "" {
+			name += ":" + part.FileName()
+		}
+		parts[name] = string(data)
+	}
+}
+
+func TestMultipartBodySeekable(t *testing.T) {
+	photo := bytes.NewReader([]byte("skipped photo data"))
+	_, _ = photo.Seek(8, io.SeekStart)
+
+	body, err := newMultipartBody([]multipartFile{
+		{field: "photo", file: objects.NewUploadFile("photo.png", photo)},
+		{field: "file", file: objects.NewUploadFile("doc.txt", strings.NewReader("document"))},
+	}, map[string]string{"title": "report"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader, release, err := body.reader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	replayable, ok := reader.(*transport.ReplayableBody)
+	if !ok {
+		t.Fatalf("got body %T, want *transport.ReplayableBody", reader)
+	}
+
+	data, err := io.ReadAll(replayable)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Content-Length is the exact size of the body, the data before the offset of the file is not sent
+	if replayable.Len() != int64(len(data)) {
+		t.Errorf("got length %d, the body has %d bytes", replayable.Len(), len(data))
+	}
+
+	parts := readMultipart(t, body.contentType, data)
+	if parts["photo:photo.png"] != "photo data" || parts["file:doc.txt"] != "document" || parts["title"] != "report" {
+		t.Errorf("unexpected parts %q", parts)
+	}
+}
+
+func TestMultipartBodyPipe(t *testing.T) {
+	body, err := newMultipartBody([]multipartFile{
+		{field: "file", file: objects.NewUploadFile("doc.txt", io.MultiReader(strings.NewReader("docu"), strings.NewReader("ment")))},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader, release, err := body.reader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	if _, ok := reader.(*io.PipeReader); !ok {
+		t.Fatalf("got body %T, want *io.PipeReader for the data without io.Seeker", reader)
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parts := readMultipart(t, body.contentType, data); len(parts) != 1 || parts["file:doc.txt"] != "document" {
+		t.Errorf("unexpected parts %q", parts)
+	}
+}
+
+type failingReader struct {
+	err error
+}
+
+func (r failingReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+func TestMultipartBodyPipeError(t *testing.T) {
+	readErr := errors.New("disk is gone")
+
+	body, err := newMultipartBody([]multipartFile{
+		{field: "file", file: objects.NewUploadFile("doc.txt", io.MultiReader(strings.NewReader("docu"), failingReader{err: readErr}))},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader, release, err := body.reader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	// the request reading the pipe gets the error of the source instead of the truncated body
+	if _, err = io.ReadAll(reader); !errors.Is(err, readErr) {
+		t.Errorf("got error %v, want the error of the file", err)
+	}
+}
+
+func TestMultipartBodyReleaseStopsCopying(t *testing.T) {
+	body, err := newMultipartBody([]multipartFile{
+		{field: "file", file: objects.NewUploadFile("doc.txt", io.MultiReader(strings.NewReader(strings.Repeat("a", 1<<20))))},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader, release, err := body.reader()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = reader.Read(make([]byte, 16)); err != nil {
+		t.Fatal(err)
+	}
+
+	release()
+
+	if _, err = reader.Read(make([]byte, 16)); !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("got error %v after the release, want io.ErrClosedPipe", err)
+	}
+}
+
+func TestMultipartBodyUndefinedFile(t *testing.T) {
+	if _, err := newMultipartBody([]multipartFile{{field: "file", file: &objects.UploadFile{Name: "doc.txt"}}}, nil); err == nil {
+		t.Error("the file without data is accepted")
+	}
+}
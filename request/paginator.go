@@ -0,0 +1,314 @@
+package request
+
+import (
+	"context"
+	"go-vk-sdk/constants"
+	internalErrors "go-vk-sdk/errors"
+	"go-vk-sdk/response"
+	"strconv"
+	"sync"
+)
+
+// PaginatorPageSize default number of items requested per page, most list methods allow at least 100
+const PaginatorPageSize = 100
+
+type PaginationMode int
+
+const (
+	PaginationOffset PaginationMode = iota + 1 // offset and count parameters, the response has count and items
+	PaginationCursor                           // start_from and count parameters, the response has items and next_from
+)
+
+// Page Response of the list methods
+type Page[T any] struct {
+	response.BaseResponse
+	Response struct {
+		Count    int    `json:"count"`
+		Items    []T    `json:"items"`
+		NextFrom string `json:"next_from,omitempty"`
+	} `json:"response"`
+}
+
+// parametersPoster is implemented by the requests of the package, they can be sent with a copy of their parameters
+type parametersPoster interface {
+	postUnmarshalParameters(ctx context.Context, parameters Parameters, target interface{}) error
+}
+
+type pageResult[T any] struct {
+	page Page[T]
+	err  error
+}
+
+// Paginator Lazy iterator over the items of the list method.
+//
+//	Pages are requested with copies of the parameters of the request, so the request itself is not modified.
+//	The initial offset or start_from of the request is respected. Every page passes the rate limiter of the api,
+//	so prefetched pages are requested as fast as the limit allows. Close must be called if the iteration is stopped early.
+//	The request must be created by this package, other implementations of Request cannot be paginated.
+//	Next must not be called concurrently, Item, Err, Count and Close can be called while a page is requested.
+//
+//	p := NewPaginator[objects.WallWallpost](NewWallGetRequest(a, user), PaginationOffset).PageSize(100)
+//	for p.Next(ctx) {
+//		post := p.Item()
+//	}
+//	if err := p.Err(); err != nil {}
+type Paginator[T any] struct {
+	req      Request
+	mode     PaginationMode
+	pageSize int
+	prefetch int
+
+	nextMtx  sync.Mutex // serializes Next, mtx is released while a page is requested
+	mtx      sync.Mutex
+	ctx      context.Context
+	cancel   context.CancelFunc
+	started  bool
+	done     bool
+	closed   bool
+	offset   int
+	count    int
+	nextFrom string
+	queue    []chan pageResult[T]
+	items    []T
+	index    int
+	item     T
+	err      error
+}
+
+func NewPaginator[T any](req Request, mode PaginationMode) *Paginator[T] {
+	return &Paginator[T]{
+		req:      req,
+		mode:     mode,
+		pageSize: PaginatorPageSize,
+		count:    -1,
+	}
+}
+
+// PageSize size > 0, it must not exceed the max count of the method
+func (p *Paginator[T]) PageSize(size int) *Paginator[T] {
+	if size > 0 {
+		p.pageSize = size
+	}
+	return p
+}
+
+// Prefetch number of pages requested concurrently ahead of the iteration, 0 disables prefetching.
+// Only offset pagination is prefetched, the cursor of the next page is known only from the previous one
+func (p *Paginator[T]) Prefetch(pages int) *Paginator[T] {
+	if pages >= 0 {
+		p.prefetch = pages
+	}
+	return p
+}
+
+// Next advances to the next item, it returns false when the items are over, the context is done or an error occurred
+func (p *Paginator[T]) Next(ctx context.Context) bool {
+	p.nextMtx.Lock()
+	defer p.nextMtx.Unlock()
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if !p.started {
+		p.start(ctx)
+	}
+
+	for {
+		if p.err != nil {
+			return false
+		}
+
+		if err := ctx.Err(); err != nil {
+			p.fail(err)
+			return false
+		}
+
+		if p.index < len(p.items) {
+			p.item = p.items[p.index]
+			p.index++
+			return true
+		}
+
+		if p.done {
+			p.cancel()
+			return false
+		}
+
+		var result pageResult[T]
+		if p.mode == PaginationCursor {
+			result = p.nextCursor(ctx)
+		} else {
+			result = p.nextOffset(ctx)
+		}
+
+		if p.closed {
+			// Close was called while the page was requested
+			return false
+		}
+
+		if result.err != nil {
+			p.fail(result.err)
+			return false
+		}
+
+		p.items = result.page.Response.Items
+		p.index = 0
+	}
+}
+
+// Item returns the current item
+func (p *Paginator[T]) Item() T {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return p.item
+}
+
+// Err returns the error which stopped the iteration
+func (p *Paginator[T]) Err() error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return p.err
+}
+
+// Count returns the total number of items reported by the first page, -1 before it or in the cursor mode
+func (p *Paginator[T]) Count() int {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return p.count
+}
+
+// Close stops the prefetching of pages
+func (p *Paginator[T]) Close() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.done = true
+	p.closed = true
+	p.items = nil
+	if p.cancel != nil {
+		p.cancel()
+	}
+}
+
+// start must be called with the lock held
+func (p *Paginator[T]) start(ctx context.Context) {
+	p.started = true
+	p.ctx, p.cancel = context.WithCancel(ctx)
+
+	parameters := p.req.GetParameters()
+	p.offset, _ = strconv.Atoi(parameters.Get(constants.ParameterNameOffset))
+	p.nextFrom = parameters.Get(constants.ParameterNameStartFrom)
+
+	if p.mode != PaginationOffset && p.mode != PaginationCursor {
+		p.err = internalErrors.Error("Request.Paginator.Next()", "unknown pagination mode "+strconv.Itoa(int(p.mode)))
+	}
+
+	if _, ok := p.req.(parametersPoster); !ok {
+		p.err = internalErrors.Error("Request.Paginator.Next()", "request "+p.req.GetMethod()+" cannot be paginated")
+	}
+}
+
+// fail must be called with the lock held
+func (p *Paginator[T]) fail(err error) {
+	p.err = err
+	p.done = true
+	p.cancel()
+}
+
+// nextOffset must be called with the lock held
+func (p *Paginator[T]) nextOffset(ctx context.Context) pageResult[T] {
+	// the first page is requested alone to learn the total count
+	if p.count < 0 {
+		result := p.fetchUnlocked(ctx, strconv.Itoa(p.offset))
+		if result.err == nil {
+			p.count = result.page.Response.Count
+			p.offset += p.pageSize
+			p.done = len(result.page.Response.Items) == 0 || p.offset >= p.count
+		}
+		return result
+	}
+
+	if p.prefetch == 0 {
+		result := p.fetchUnlocked(ctx, strconv.Itoa(p.offset))
+		if result.err == nil {
+			p.offset += p.pageSize
+			p.done = len(result.page.Response.Items) == 0 || p.offset >= p.count
+		}
+		return result
+	}
+
+	for len(p.queue) < p.prefetch && p.offset < p.count {
+		ch := make(chan pageResult[T], 1)
+		offset := strconv.Itoa(p.offset)
+		go func() {
+			ch <- p.fetch(p.ctx, offset)
+		}()
+
+		p.queue = append(p.queue, ch)
+		p.offset += p.pageSize
+	}
+
+	ch := p.queue[0]
+	p.queue = p.queue[1:]
+
+	var result pageResult[T]
+	p.mtx.Unlock()
+	select {
+	case result = <-ch:
+	case <-ctx.Done():
+		result.err = ctx.Err()
+	case <-p.ctx.Done():
+		result.err = p.ctx.Err()
+	}
+	p.mtx.Lock()
+
+	if result.err == nil {
+		p.done = len(result.page.Response.Items) == 0 || (len(p.queue) == 0 && p.offset >= p.count)
+	}
+
+	return result
+}
+
+// nextCursor must be called with the lock held
+func (p *Paginator[T]) nextCursor(ctx context.Context) pageResult[T] {
+	result := p.fetchUnlocked(ctx, p.nextFrom)
+	if result.err == nil {
+		p.nextFrom = result.page.Response.NextFrom
+		p.done = p.nextFrom == "" || len(result.page.Response.Items) == 0
+	}
+
+	return result
+}
+
+// fetchUnlocked must be called with the lock held, the lock is released while the page is requested,
+// the request is cancelled by Close
+func (p *Paginator[T]) fetchUnlocked(ctx context.Context, from string) pageResult[T] {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stop := context.AfterFunc(p.ctx, cancel)
+	defer stop()
+
+	p.mtx.Unlock()
+	defer p.mtx.Lock()
+
+	return p.fetch(ctx, from)
+}
+
+// fetch requests the page at the offset or the cursor
+func (p *Paginator[T]) fetch(ctx context.Context, from string) pageResult[T] {
+	parameters := p.req.GetParameters().Clone()
+	_ = parameters.Set(constants.ParameterNameCount, strconv.Itoa(p.pageSize))
+
+	if p.mode == PaginationCursor {
+		parameters.Remove(constants.ParameterNameStartFrom)
+		_ = parameters.SetIfNotEmpty(constants.ParameterNameStartFrom, from)
+	} else {
+		_ = parameters.Set(constants.ParameterNameOffset, from)
+	}
+
+	var result pageResult[T]
+	result.err = p.req.(parametersPoster).postUnmarshalParameters(ctx, parameters, &result.page)
+
+	return result
+}
@@ -0,0 +1,84 @@
+package request_test
+
+import (
+	"context"
+	"go-vk-sdk/objects"
+	"go-vk-sdk/request"
+	"go-vk-sdk/vktest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestPaginatorOffset(t *testing.T) {
+	s, a, g := newServer(t)
+
+	s.Handle("wall.get", func(call *vktest.Call) (interface{}, error) {
+		offset, _ := strconv.Atoi(call.Get("offset"))
+		count, _ := strconv.Atoi(call.Get("count"))
+
+		items := make([]map[string]int, 0, count)
+		for id := offset + 1; id <= min(offset+count, 5); id++ {
+			items = append(items, map[string]int{"id": id})
+		}
+
+		return map[string]interface{}{"count": 5, "items": items}, nil
+	})
+
+	p := request.NewPaginator[objects.WallWallpost](request.NewWallGetRequest(a, g), request.PaginationOffset).PageSize(2)
+	defer p.Close()
+
+	var ids []int
+	for p.Next(context.Background()) {
+		ids = append(ids, p.Item().ID)
+	}
+
+	if err := p.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ids) != 5 || ids[0] != 1 || ids[4] != 5 || p.Count() != 5 {
+		t.Errorf("got ids %v and count %d", ids, p.Count())
+	}
+
+	if calls := len(s.CallsOf("wall.get")); calls != 3 {
+		t.Errorf("got %d calls, want 3", calls)
+	}
+}
+
+func TestPaginatorCloseDuringFetch(t *testing.T) {
+	s, a, g := newServer(t)
+
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	s.Handle("wall.get", func(*vktest.Call) (interface{}, error) {
+		<-release
+		return map[string]interface{}{"count": 0, "items": []int{}}, nil
+	})
+
+	p := request.NewPaginator[objects.WallWallpost](request.NewWallGetRequest(a, g), request.PaginationOffset)
+
+	next := make(chan bool, 1)
+	go func() { next <- p.Next(context.Background()) }()
+
+	for len(s.CallsOf("wall.get")) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// the lock is not held while the page is requested
+	if count := p.Count(); count != -1 {
+		t.Errorf("got count %d, want -1", count)
+	}
+
+	p.Close()
+
+	select {
+	case ok := <-next:
+		if ok || p.Err() != nil {
+			t.Errorf("got %v and error %v after Close", ok, p.Err())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the request is not cancelled by Close")
+	}
+}
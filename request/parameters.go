@@ -2,7 +2,10 @@ package request
 
 import (
 	internalError "go-vk-sdk/errors"
+	"go-vk-sdk/objects"
 	"net/url"
+	"strconv"
+	"strings"
 	"sync"
 )
 
@@ -199,3 +202,23 @@ func (p *BaseParameters) resetCache() {
 	p.urlValues = nil
 	p.urlValuesEncode = ""
 }
+
+// joinInts formats the ids as a comma-separated list
+func joinInts(ids []int) string {
+	strIDs := make([]string, len(ids))
+	for i, id := range ids {
+		strIDs[i] = strconv.Itoa(id)
+	}
+	return strings.Join(strIDs, ",")
+}
+
+// joinAttachments formats the attachments as a comma-separated list of type{owner_id}_{id}
+func joinAttachments(attachments []objects.Attachment) string {
+	strAttachments := make([]string, 0, len(attachments))
+	for _, attachment := range attachments {
+		if attachment != nil {
+			strAttachments = append(strAttachments, attachment.ToAttachment())
+		}
+	}
+	return strings.Join(strAttachments, ",")
+}
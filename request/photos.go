@@ -475,6 +475,12 @@ func (r *PhotosGetMessagesUploadServerRequest) Exec(ctx context.Context) (respon
 	return
 }
 
+// PeerID Destination ID of the message the photo is uploaded for
+func (r *PhotosGetMessagesUploadServerRequest) PeerID(id int) *PhotosGetMessagesUploadServerRequest {
+	r.parameters.Set(constants.ParameterNamePeerID, strconv.Itoa(id))
+	return r
+}
+
 // PhotosGetNewTagsRequest defines the request for photos.getNewTags
 //
 // Returns a list of photos with unviewed tags.
@@ -630,6 +636,12 @@ func (r *PhotosGetWallUploadServerRequest) Exec(ctx context.Context) (response r
 	return
 }
 
+// GroupID ID of the community to whose wall the photo is uploaded
+func (r *PhotosGetWallUploadServerRequest) GroupID(id int) *PhotosGetWallUploadServerRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
 // PhotosMakeCoverRequest defines the request for photos.makeCover
 //
 // Makes a photo the album cover.
@@ -887,6 +899,24 @@ func (r *PhotosSaveMessagesPhotoRequest) Exec(ctx context.Context) (response res
 	return
 }
 
+// Photo Parameter photo returned by the upload server
+func (r *PhotosSaveMessagesPhotoRequest) Photo(v string) *PhotosSaveMessagesPhotoRequest {
+	r.parameters.Set(constants.ParameterNamePhoto, v)
+	return r
+}
+
+// Server Parameter server returned by the upload server
+func (r *PhotosSaveMessagesPhotoRequest) Server(v int) *PhotosSaveMessagesPhotoRequest {
+	r.parameters.Set(constants.ParameterNameServer, strconv.Itoa(v))
+	return r
+}
+
+// Hash Parameter hash returned by the upload server
+func (r *PhotosSaveMessagesPhotoRequest) Hash(v string) *PhotosSaveMessagesPhotoRequest {
+	r.parameters.Set(constants.ParameterNameHash, v)
+	return r
+}
+
 // PhotosSaveOwnerCoverPhotoRequest defines the request for photos.saveOwnerCoverPhoto
 //
 // Saves a community or profile cover after it has been successfully uploaded.
@@ -944,6 +974,36 @@ func (r *PhotosSaveWallPhotoRequest) Exec(ctx context.Context) (response respons
 	return
 }
 
+// UserID ID of the user to whose wall the photo is saved
+func (r *PhotosSaveWallPhotoRequest) UserID(id int) *PhotosSaveWallPhotoRequest {
+	r.parameters.Set(constants.ParameterNameUserID, strconv.Itoa(id))
+	return r
+}
+
+// Photo Parameter photo returned by the upload server
+func (r *PhotosSaveWallPhotoRequest) Photo(v string) *PhotosSaveWallPhotoRequest {
+	r.parameters.Set(constants.ParameterNamePhoto, v)
+	return r
+}
+
+// Server Parameter server returned by the upload server
+func (r *PhotosSaveWallPhotoRequest) Server(v int) *PhotosSaveWallPhotoRequest {
+	r.parameters.Set(constants.ParameterNameServer, strconv.Itoa(v))
+	return r
+}
+
+// Hash Parameter hash returned by the upload server
+func (r *PhotosSaveWallPhotoRequest) Hash(v string) *PhotosSaveWallPhotoRequest {
+	r.parameters.Set(constants.ParameterNameHash, v)
+	return r
+}
+
+// Caption Description of the photo, up to 2048 characters
+func (r *PhotosSaveWallPhotoRequest) Caption(v string) *PhotosSaveWallPhotoRequest {
+	r.parameters.Set(constants.ParameterNameCaption, v)
+	return r
+}
+
 func (r *PhotosSaveWallPhotoRequest) GroupID(id int) *PhotosSaveWallPhotoRequest {
 	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
 	return r
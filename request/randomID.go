@@ -0,0 +1,139 @@
+package request
+
+import (
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Doc: https://dev.vk.com/ru/method/messages.send
+
+const (
+	RandomIDWindowSize = 1000            // default number of the random_id values remembered by the window
+	RandomIDWindowTTL  = 5 * time.Minute // default time the random_id is remembered by the window
+)
+
+// NewRandomID returns a random non-zero random_id for messages.send
+func NewRandomID() int {
+	return int(rand.Int32N(math.MaxInt32)) + 1
+}
+
+// RandomIDFromKey returns the random_id derived from the idempotency key, the same key always gives the same random_id,
+// so VK does not send the message twice even if the request is repeated by another process.
+//
+//	The key is hashed with 32-bit FNV-1a into the 31 bits of random_id, so different keys may give the same random_id
+//	and VK drops the later message as a repeat. Among n keys sent to one peer the chance of a collision is about n²/2³²,
+//	1% for 6 500 keys and 50% for 55 000 keys. High-volume senders should use NewRandomID, the default of messages.send,
+//	and keep the key only for the messages which are really retried.
+func RandomIDFromKey(key string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+
+	id := int(h.Sum32() & math.MaxInt32)
+	if id == 0 {
+		id = 1
+	}
+
+	return id
+}
+
+type randomIDEntry struct {
+	id   int
+	time time.Time
+}
+
+// RandomIDWindow Remembers the random_id of the messages sent by the app for a limited time.
+//
+//	The outgoing messages received by the long poll or the callback server (message_reply, message_new with out = 1,
+//	EventMessageNew of the user long poll with the mode ExtraOptionsModeReturnRandomID) contain random_id,
+//	Contains reports whether the message is the echo of the message sent with MessagesSendRequest.Track.
+type RandomIDWindow struct {
+	mtx     sync.Mutex
+	size    int
+	ttl     time.Duration
+	ids     map[int]int // random_id -> number of entries
+	entries []randomIDEntry
+}
+
+// NewRandomIDWindow size > 0 and ttl > 0, otherwise the defaults are used
+func NewRandomIDWindow(size int, ttl time.Duration) *RandomIDWindow {
+	if size <= 0 {
+		size = RandomIDWindowSize
+	}
+
+	if ttl <= 0 {
+		ttl = RandomIDWindowTTL
+	}
+
+	return &RandomIDWindow{
+		size: size,
+		ttl:  ttl,
+		ids:  make(map[int]int),
+	}
+}
+
+// Add remembers the random_id, the oldest values are forgotten when the window is full
+func (w *RandomIDWindow) Add(id int) {
+	if id == 0 {
+		return
+	}
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	w.expire(time.Now())
+
+	if len(w.entries) >= w.size {
+		w.remove(1)
+	}
+
+	w.entries = append(w.entries, randomIDEntry{id: id, time: time.Now()})
+	w.ids[id]++
+}
+
+// Contains reports whether the random_id was added to the window and has not expired yet
+func (w *RandomIDWindow) Contains(id int) bool {
+	if id == 0 {
+		return false
+	}
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	w.expire(time.Now())
+
+	return w.ids[id] > 0
+}
+
+// Len returns the number of the remembered values
+func (w *RandomIDWindow) Len() int {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	w.expire(time.Now())
+
+	return len(w.entries)
+}
+
+// expire must be called with the lock held
+func (w *RandomIDWindow) expire(now time.Time) {
+	n := 0
+	for n < len(w.entries) && now.Sub(w.entries[n].time) >= w.ttl {
+		n++
+	}
+
+	w.remove(n)
+}
+
+// remove must be called with the lock held, it forgets the n oldest entries
+func (w *RandomIDWindow) remove(n int) {
+	for _, entry := range w.entries[:n] {
+		if w.ids[entry.id]--; w.ids[entry.id] <= 0 {
+			delete(w.ids, entry.id)
+		}
+	}
+
+	w.entries = w.entries[n:]
+}
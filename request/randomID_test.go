@@ -0,0 +1,97 @@
+package request_test
+
+import (
+	"context"
+	"go-vk-sdk/request"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRandomIDFromKey(t *testing.T) {
+	id := request.RandomIDFromKey("order-42")
+	if id <= 0 {
+		t.Fatalf("got random_id %d, want > 0", id)
+	}
+
+	if again := request.RandomIDFromKey("order-42"); again != id {
+		t.Errorf("got %d and %d for the same key", id, again)
+	}
+
+	if other := request.RandomIDFromKey("order-43"); other == id {
+		t.Errorf("got the same random_id %d for different keys", id)
+	}
+}
+
+func TestRandomIDWindowSize(t *testing.T) {
+	w := request.NewRandomIDWindow(2, time.Hour)
+
+	w.Add(0)
+	w.Add(1)
+	w.Add(2)
+	w.Add(2)
+
+	if w.Len() != 2 {
+		t.Errorf("got %d entries, want 2", w.Len())
+	}
+
+	// the oldest value is forgotten when the window is full
+	if w.Contains(1) || !w.Contains(2) || w.Contains(0) {
+		t.Errorf("got contains 1=%v 2=%v 0=%v", w.Contains(1), w.Contains(2), w.Contains(0))
+	}
+
+	w.Add(3)
+
+	// one of the two entries of 2 is still remembered
+	if !w.Contains(2) || !w.Contains(3) {
+		t.Errorf("got contains 2=%v 3=%v", w.Contains(2), w.Contains(3))
+	}
+}
+
+func TestRandomIDWindowTTL(t *testing.T) {
+	w := request.NewRandomIDWindow(10, 20*time.Millisecond)
+
+	w.Add(1)
+	if !w.Contains(1) {
+		t.Fatal("the added value is not remembered")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if w.Contains(1) || w.Len() != 0 {
+		t.Errorf("the expired value is remembered, len %d", w.Len())
+	}
+}
+
+func TestMessagesSendTrack(t *testing.T) {
+	s, a, g := newServer(t)
+	s.Respond("messages.send", 1)
+	s.Respond("messages.send", 2)
+
+	w := request.NewRandomIDWindow(0, 0)
+
+	req := request.NewMessagesSendRequest(a, g).PeerID(2).Message("hello").Track(w)
+	if _, err := req.Exec(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := req.IdempotencyKey("key").Exec(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := s.CallsOf("messages.send")
+	if len(calls) != 2 {
+		t.Fatalf("got %d calls, want 2", len(calls))
+	}
+
+	for _, call := range calls {
+		id, err := strconv.Atoi(call.Get("random_id"))
+		if err != nil || !w.Contains(id) {
+			t.Errorf("random_id %q is not tracked", call.Get("random_id"))
+		}
+	}
+
+	if got := calls[1].Get("random_id"); got != strconv.Itoa(request.RandomIDFromKey("key")) {
+		t.Errorf("got random_id %s, want the one of the key", got)
+	}
+}
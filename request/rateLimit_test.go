@@ -0,0 +1,62 @@
+package request_test
+
+import (
+	"context"
+	"go-vk-sdk/actor"
+	"go-vk-sdk/request"
+	"go-vk-sdk/vktest"
+	"sync"
+	"testing"
+)
+
+type limiterCall struct {
+	token string
+	limit int
+}
+
+// recordingLimiter records the calls of the rate limiter without waiting
+type recordingLimiter struct {
+	mtx   sync.Mutex
+	calls []limiterCall
+}
+
+func (l *recordingLimiter) Wait(_ context.Context, token string, limit int) error {
+	l.mtx.Lock()
+	l.calls = append(l.calls, limiterCall{token: token, limit: limit})
+	l.mtx.Unlock()
+	return nil
+}
+
+func TestRateLimitByActorType(t *testing.T) {
+	s, a, _ := newServer(t)
+	s.Handle("users.get", func(*vktest.Call) (interface{}, error) {
+		return []interface{}{}, nil
+	})
+
+	limiter := &recordingLimiter{}
+	a.RateLimiter = limiter
+	a.UserRequestLimit, a.GroupRequestLimit, a.ServiceRequestLimit = 3, 20, 5
+
+	actors := []actor.Actor{
+		&actor.User{ID: 1, AccessToken: "user"},
+		&actor.Group{ID: 1, AccessToken: "group"},
+		&actor.Service{AccessToken: "service"},
+	}
+
+	for _, act := range actors {
+		if _, err := request.NewUsersGetRequest(a, act).Exec(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []limiterCall{{token: "user", limit: 3}, {token: "group", limit: 20}, {token: "service", limit: 5}}
+	if len(limiter.calls) != len(want) {
+		t.Fatalf("got limiter calls %+v, want %+v", limiter.calls, want)
+	}
+
+	for i := range want {
+		if limiter.calls[i] != want[i] {
+			t.Errorf("got limiter call %+v, want %+v", limiter.calls[i], want[i])
+		}
+	}
+}
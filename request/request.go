@@ -3,13 +3,17 @@ package request
 import (
 	"bytes"
 	"context"
+	"errors"
 	"go-vk-sdk/actor"
 	"go-vk-sdk/api"
 	"go-vk-sdk/constants"
 	internalErrors "go-vk-sdk/errors"
+	"go-vk-sdk/logger"
 	"go-vk-sdk/transport"
 	"io"
 	"net/http"
+	"net/url"
+	"reflect"
 	"strings"
 	"sync"
 )
@@ -21,6 +25,7 @@ type Request interface {
 	PostData(ctx context.Context, data io.Reader) (*http.Response, error)
 	PostUnmarshal(ctx context.Context, target interface{}) error
 	GetHeader() *http.Header
+	GetMethod() string
 	GetParameters() Parameters
 	SetParameters(Parameters)
 }
@@ -33,20 +38,33 @@ type Request interface {
 type BaseRequest struct {
 	mtx        sync.RWMutex
 	url        string
+	method     string
 	header     *http.Header
 	parameters Parameters
 	transport  transport.Client
+	limiter    api.RateLimiter
+	token      string
+	limit      int
+
+	captcha         api.CaptchaHandler
+	captchaAttempts int
+
+	constraints    *UploadConstraints // constraints of the upload request, nil uses the constraints of the request type
+	skipValidation bool
 }
 
 func NewAuthBaseRequest(api *api.API, method string) *BaseRequest {
 	r := &BaseRequest{
 		url:       api.AuthEndpoint + method,
+		method:    method,
 		transport: api.Client,
 		header: &http.Header{
-			"Content-EventType": []string{constants.ContentTypeFormURLEncoded},
-			"Accept-Encoding":   []string{constants.AcceptEncodingGzip},
+			"Content-Type":    []string{constants.ContentTypeFormURLEncoded},
+			"Accept-Encoding": []string{constants.AcceptEncodingGzip},
 		},
-		parameters: NewBaseRequestParameters(),
+		parameters:      NewBaseRequestParameters(),
+		captcha:         api.CaptchaHandler,
+		captchaAttempts: api.CaptchaAttempts,
 	}
 
 	r.Version(api.Version)
@@ -58,12 +76,17 @@ func NewAuthBaseRequest(api *api.API, method string) *BaseRequest {
 func NewMethodBaseRequest(api *api.API, actor actor.Actor, method string) *BaseRequest {
 	r := &BaseRequest{
 		url:       api.MethodEndpoint + method,
+		method:    method,
 		transport: api.Client,
 		header: &http.Header{
-			"Content-EventType": []string{constants.ContentTypeFormURLEncoded},
-			"Accept-Encoding":   []string{constants.AcceptEncodingGzip},
+			"Content-Type":    []string{constants.ContentTypeFormURLEncoded},
+			"Accept-Encoding": []string{constants.AcceptEncodingGzip},
 		},
-		parameters: NewBaseRequestParameters(),
+		parameters:      NewBaseRequestParameters(),
+		limiter:         api.RateLimiter,
+		limit:           requestLimit(api, actor.GetType()),
+		captcha:         api.CaptchaHandler,
+		captchaAttempts: api.CaptchaAttempts,
 	}
 
 	r.AccessToken(actor.GetAccessToken())
@@ -73,6 +96,49 @@ func NewMethodBaseRequest(api *api.API, actor actor.Actor, method string) *BaseR
 	return r
 }
 
+// requestLimit returns the number of requests per second allowed for the access token of the actor type
+func requestLimit(a *api.API, t actor.Type) int {
+	switch t {
+	case actor.UserType, actor.UserVKIDType:
+		return a.UserRequestLimit
+	case actor.GroupType:
+		return a.GroupRequestLimit
+	case actor.ServiceType:
+		return a.ServiceRequestLimit
+	default:
+		return 0
+	}
+}
+
+// wait blocks until the call fits into the rate limit of the access token
+func (r *BaseRequest) wait(ctx context.Context) error {
+	r.mtx.RLock()
+	limiter, token, limit := r.limiter, r.token, r.limit
+	r.mtx.RUnlock()
+
+	if limiter == nil {
+		return nil
+	}
+
+	err := limiter.Wait(ctx, token, limit)
+	if err != nil {
+		return internalErrors.ErrorLog("Request.wait()", "Rate limit wait interrupted: "+err.Error())
+	}
+
+	return nil
+}
+
+// withCallInfo attaches the method and a copy of the parameters to the context for the transport middlewares,
+// the retries of the transport wait for the rate limiter as the first attempt does
+func (r *BaseRequest) withCallInfo(ctx context.Context, parameters Parameters) context.Context {
+	values := url.Values{}
+	for key, value := range *parameters.BuildURLValues() {
+		values[key] = append([]string(nil), value...)
+	}
+
+	return transport.WithCallInfo(ctx, &transport.CallInfo{Method: r.method, Parameters: values, Wait: r.wait})
+}
+
 func NewUploadBaseRequest(api *api.API, actor actor.Actor) *BaseRequest {
 	r := &BaseRequest{
 		url:        "",
@@ -100,7 +166,11 @@ func (r *BaseRequest) Get(ctx context.Context) (*http.Response, error) {
 
 	r.mtx.RUnlock()
 
-	resp, err := r.transport.Get(ctx, u, r.header)
+	if err := r.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	resp, err := r.transport.Get(r.withCallInfo(ctx, r.parameters), u, r.header)
 	if err != nil {
 		return nil, internalErrors.ErrorLog("Request.Get()", "Error GET request "+u+": "+err.Error())
 	}
@@ -109,65 +179,190 @@ func (r *BaseRequest) Get(ctx context.Context) (*http.Response, error) {
 }
 
 func (r *BaseRequest) GetUnmarshal(ctx context.Context, target interface{}) error {
-	r.mtx.RLock()
+	return r.execCaptcha(ctx, r.GetParameters(), target, r.getUnmarshal)
+}
+
+func (r *BaseRequest) getUnmarshal(ctx context.Context, parameters Parameters, target interface{}) error {
+	if err := r.wait(ctx); err != nil {
+		return err
+	}
 
+	r.mtx.RLock()
 	u := r.url
+	header := r.header
+	client := r.transport
+	r.mtx.RUnlock()
 
 	if strings.Contains(u, "?") {
-		u += "&" + r.parameters.BuildURLValuesEncode()
+		u += "&" + parameters.BuildURLValuesEncode()
 	} else {
-		u += "?" + r.parameters.BuildURLValuesEncode()
+		u += "?" + parameters.BuildURLValuesEncode()
 	}
 
-	httpResponse, err := r.transport.GetDecodeJSON(ctx, u, target, r.header)
+	httpResponse, err := client.GetDecodeJSON(r.withCallInfo(ctx, parameters), u, target, header)
 	if err != nil {
 		return internalErrors.ErrorLog("Request.GetUnmarshal()", "Error GET and unmarshal JSON request "+u+": "+err.Error())
 	}
 	defer httpResponse.Body.Close() // default http client api closes the response body itself
 
-	return nil
+	return responseError(target)
 }
 
 func (r *BaseRequest) Post(ctx context.Context) (*http.Response, error) {
+	if err := r.wait(ctx); err != nil {
+		return nil, err
+	}
+
 	r.mtx.RLock()
-	defer r.mtx.RUnlock()
+	u := r.url
+	header := r.header
+	parameters := r.parameters
+	client := r.transport
+	r.mtx.RUnlock()
 
-	resp, err := r.transport.Post(ctx, r.url, bytes.NewBufferString(r.parameters.BuildURLValuesEncode()), r.header)
+	resp, err := client.Post(r.withCallInfo(ctx, parameters), u, bytes.NewBufferString(parameters.BuildURLValuesEncode()), header)
 	if err != nil {
-		return nil, internalErrors.ErrorLog("Request.Post()", "Error POST request "+r.url+": "+err.Error())
+		return nil, internalErrors.ErrorLog("Request.Post()", "Error POST request "+u+": "+err.Error())
 	}
 
 	return resp, nil
 }
 
 func (r *BaseRequest) PostData(ctx context.Context, data io.Reader) (*http.Response, error) {
+	if err := r.wait(ctx); err != nil {
+		return nil, err
+	}
+
 	r.mtx.RLock()
-	defer r.mtx.RUnlock()
+	u := r.url
+	header := r.header
+	parameters := r.parameters
+	client := r.transport
+	r.mtx.RUnlock()
 
-	resp, err := r.transport.Post(ctx, r.url, data, r.header)
+	resp, err := client.Post(r.withCallInfo(ctx, parameters), u, data, header)
 	if err != nil {
-		return nil, internalErrors.ErrorLog("Request.PostData()", "Error POST request "+r.url+": "+err.Error())
+		return nil, internalErrors.ErrorLog("Request.PostData()", "Error POST request "+u+": "+err.Error())
 	}
 
 	return resp, nil
 }
 
 func (r *BaseRequest) PostUnmarshal(ctx context.Context, target interface{}) error {
+	return r.execCaptcha(ctx, r.GetParameters(), target, r.postUnmarshal)
+}
+
+// postUnmarshalParameters sends the request with the given parameters instead of the parameters of the request,
+// for example with a modified copy of them for every page of a list
+func (r *BaseRequest) postUnmarshalParameters(ctx context.Context, parameters Parameters, target interface{}) error {
+	if parameters == nil {
+		return internalErrors.ErrorLog("Request.postUnmarshalParameters()", "parameters are undefined")
+	}
+
+	return r.execCaptcha(ctx, parameters, target, r.postUnmarshal)
+}
+
+func (r *BaseRequest) postUnmarshal(ctx context.Context, parameters Parameters, target interface{}) error {
+	if err := r.wait(ctx); err != nil {
+		return err
+	}
+
 	r.mtx.RLock()
-	defer r.mtx.RUnlock()
+	u := r.url
+	header := r.header
+	client := r.transport
+	r.mtx.RUnlock()
 
-	httpResponse, err := r.transport.PostDecodeJSON(
-		ctx,
-		r.url,
-		bytes.NewBufferString(r.parameters.BuildURLValuesEncode()),
+	httpResponse, err := client.PostDecodeJSON(
+		r.withCallInfo(ctx, parameters),
+		u,
+		bytes.NewBufferString(parameters.BuildURLValuesEncode()),
 		target,
-		r.header,
+		header,
 	)
 	if err != nil {
-		return internalErrors.ErrorLog("Request.PostUnmarshal()", "Error POST and unmarshal JSON request "+r.url+": "+err.Error())
+		return internalErrors.ErrorLog("Request.PostUnmarshal()", "Error POST and unmarshal JSON request "+u+": "+err.Error())
 	}
 	defer httpResponse.Body.Close() // default http client api closes the response body itself
 
+	return responseError(target)
+}
+
+// execCaptcha executes the call and, while VK requires a captcha, solves it by the captcha handler of the api
+// and repeats the call with captcha_sid and captcha_key.
+//
+//	Repeated calls use a copy of the parameters, so the captcha is never left in the parameters of the request,
+//	which may be shared between goroutines. If the captcha cannot be solved, the captcha error is returned.
+func (r *BaseRequest) execCaptcha(ctx context.Context, parameters Parameters, target interface{}, exec func(context.Context, Parameters, interface{}) error) error {
+	r.mtx.RLock()
+	handler := r.captcha
+	attempts := r.captchaAttempts
+	r.mtx.RUnlock()
+
+	err := exec(ctx, parameters, target)
+
+	for attempt := 0; handler != nil && attempt < attempts; attempt++ {
+		sid, img, ok := captchaError(err)
+		if !ok {
+			break
+		}
+
+		key, solveErr := handler.SolveCaptcha(ctx, sid, img)
+		if solveErr != nil {
+			logger.Log("Request.execCaptcha()", "Error solve captcha "+sid+": "+solveErr.Error())
+			return err
+		}
+
+		if ctx.Err() != nil {
+			return err
+		}
+
+		parameters = parameters.Clone()
+		_ = parameters.Set(constants.ParameterNameCaptchaSID, sid)
+		_ = parameters.Set(constants.ParameterNameCaptchaKey, key)
+
+		resetTarget(target)
+		err = exec(ctx, parameters, target)
+	}
+
+	return err
+}
+
+// captchaError returns captcha_sid and captcha_img of the error 14 or the need_captcha error of the direct auth
+func captchaError(err error) (string, string, bool) {
+	var apiError *internalErrors.APIError
+	if errors.As(err, &apiError) && apiError.Code == int(internalErrors.CaptchaCode) && apiError.CaptchaSID != "" {
+		return apiError.CaptchaSID, apiError.CaptchaImg, true
+	}
+
+	var authError *internalErrors.AuthDirectError
+	if errors.As(err, &authError) && authError.Type == internalErrors.AuthNeedCaptcha && authError.CaptchaSID != "" {
+		return authError.CaptchaSID, authError.CaptchaImg, true
+	}
+
+	return "", "", false
+}
+
+// resetTarget sets the target to the zero value before it is decoded again, so fields of the previous response are not kept
+func resetTarget(target interface{}) {
+	v := reflect.ValueOf(target)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v.Elem().Set(reflect.Zero(v.Elem().Type()))
+	}
+}
+
+// baseRequest gives the Batcher access to the request embedding BaseRequest
+func (r *BaseRequest) baseRequest() *BaseRequest {
+	return r
+}
+
+// responseError returns the VK error decoded into the target, for example *errors.APIError of the response envelope.
+// The response keeps the error as well
+func responseError(target interface{}) error {
+	if r, ok := target.(interface{ Err() error }); ok {
+		return r.Err()
+	}
+
 	return nil
 }
 
@@ -177,6 +372,13 @@ func (r *BaseRequest) GetHeader() *http.Header {
 	return r.header
 }
 
+// GetMethod returns the api method name, empty for upload and long poll requests
+func (r *BaseRequest) GetMethod() string {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	return r.method
+}
+
 func (r *BaseRequest) GetParameters() Parameters {
 	r.mtx.RLock()
 	defer r.mtx.RUnlock()
@@ -237,6 +439,7 @@ func (r *BaseRequest) AccessToken(token string) *BaseRequest {
 	if token != "" {
 		r.mtx.Lock()
 		r.header.Set("Authorization", "Bearer "+token)
+		r.token = token
 		r.mtx.Unlock()
 	}
 	return r
@@ -251,6 +454,18 @@ func (r *BaseRequest) SetContentType(t string) *BaseRequest {
 	return r
 }
 
+// SetHeader sets the header of the request, the empty value removes it
+func (r *BaseRequest) SetHeader(key, value string) *BaseRequest {
+	r.mtx.Lock()
+	if value == "" {
+		r.header.Del(key)
+	} else {
+		r.header.Set(key, value)
+	}
+	r.mtx.Unlock()
+	return r
+}
+
 func (r *BaseRequest) SetAcceptEncoding(a string) *BaseRequest {
 	if a != "" {
 		r.mtx.Lock()
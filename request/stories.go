@@ -6,6 +6,8 @@ import (
 	"go-vk-sdk/api"
 	"go-vk-sdk/constants"
 	"go-vk-sdk/response"
+	"strconv"
+	"strings"
 )
 
 // Doc: https://dev.vk.com/method/stories
@@ -206,6 +208,40 @@ func (r *StoriesGetPhotoUploadServerRequest) Exec(ctx context.Context) (response
 	return
 }
 
+// AddToNews Publish the story in the news. Required if user_ids is not set
+func (r *StoriesGetPhotoUploadServerRequest) AddToNews(v bool) *StoriesGetPhotoUploadServerRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameAddToNews, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameAddToNews, "0")
+	}
+	return r
+}
+
+// UserIDs IDs of the users who can see the story. Required if add_to_news is not set
+func (r *StoriesGetPhotoUploadServerRequest) UserIDs(ids []int) *StoriesGetPhotoUploadServerRequest {
+	r.parameters.Set(constants.ParameterNameUserIDs, joinInts(ids))
+	return r
+}
+
+// GroupID ID of the community the story is published by
+func (r *StoriesGetPhotoUploadServerRequest) GroupID(id int) *StoriesGetPhotoUploadServerRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
+// LinkText Text of the link of the story, for example to_store, vote, more
+func (r *StoriesGetPhotoUploadServerRequest) LinkText(v string) *StoriesGetPhotoUploadServerRequest {
+	r.parameters.Set(constants.ParameterNameLinkText, v)
+	return r
+}
+
+// LinkURL URL of the link of the story
+func (r *StoriesGetPhotoUploadServerRequest) LinkURL(v string) *StoriesGetPhotoUploadServerRequest {
+	r.parameters.Set(constants.ParameterNameLinkURL, v)
+	return r
+}
+
 // StoriesGetRepliesRequest defines the request for stories.getReplies
 //
 // Retrieves replies to a story.
@@ -284,6 +320,40 @@ func (r *StoriesGetVideoUploadServerRequest) Exec(ctx context.Context) (response
 	return
 }
 
+// AddToNews Publish the story in the news. Required if user_ids is not set
+func (r *StoriesGetVideoUploadServerRequest) AddToNews(v bool) *StoriesGetVideoUploadServerRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameAddToNews, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameAddToNews, "0")
+	}
+	return r
+}
+
+// UserIDs IDs of the users who can see the story. Required if add_to_news is not set
+func (r *StoriesGetVideoUploadServerRequest) UserIDs(ids []int) *StoriesGetVideoUploadServerRequest {
+	r.parameters.Set(constants.ParameterNameUserIDs, joinInts(ids))
+	return r
+}
+
+// GroupID ID of the community the story is published by
+func (r *StoriesGetVideoUploadServerRequest) GroupID(id int) *StoriesGetVideoUploadServerRequest {
+	r.parameters.Set(constants.ParameterNameGroupID, strconv.Itoa(id))
+	return r
+}
+
+// LinkText Text of the link of the story, for example to_store, vote, more
+func (r *StoriesGetVideoUploadServerRequest) LinkText(v string) *StoriesGetVideoUploadServerRequest {
+	r.parameters.Set(constants.ParameterNameLinkText, v)
+	return r
+}
+
+// LinkURL URL of the link of the story
+func (r *StoriesGetVideoUploadServerRequest) LinkURL(v string) *StoriesGetVideoUploadServerRequest {
+	r.parameters.Set(constants.ParameterNameLinkURL, v)
+	return r
+}
+
 // StoriesGetViewersRequest defines the request for stories.getViewers
 //
 // Returns a list of users who viewed the story.
@@ -360,6 +430,28 @@ func (r *StoriesSaveRequest) Exec(ctx context.Context) (response response.Storie
 	return
 }
 
+// UploadResults Parameters upload_result returned by the upload server
+func (r *StoriesSaveRequest) UploadResults(v ...string) *StoriesSaveRequest {
+	r.parameters.Set(constants.ParameterNameUploadResults, strings.Join(v, ","))
+	return r
+}
+
+// Extended Return additional fields of the users and communities
+func (r *StoriesSaveRequest) Extended(v bool) *StoriesSaveRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameExtended, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameExtended, "0")
+	}
+	return r
+}
+
+// Fields List of additional profile fields and community fields to return
+func (r *StoriesSaveRequest) Fields(v ...string) *StoriesSaveRequest {
+	r.parameters.Set(constants.ParameterNameFields, strings.Join(v, ","))
+	return r
+}
+
 // StoriesSearchRequest defines the request for stories.search
 //
 // Returns search results for stories.
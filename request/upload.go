@@ -1,123 +1,144 @@
 package request
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"go-vk-sdk/actor"
 	"go-vk-sdk/api"
 	"go-vk-sdk/constants"
+	internalErrors "go-vk-sdk/errors"
 	"go-vk-sdk/objects"
 	"go-vk-sdk/response"
 	"io"
-	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
 )
 
 // Doc: https://dev.vk.com/ru/api/upload/overview
 
-func uploadFile(request *BaseRequest, ctx context.Context, file *objects.UploadFile, response interface{}) error {
-	buf := new(bytes.Buffer)
-	writer := multipart.NewWriter(buf)
-	defer writer.Close()
-
-	part, err := writer.CreateFormFile(file.FieldName, file.Name)
-	if err != nil {
-		return err
-	}
-
-	_, err = io.Copy(part, file.Data)
+// decodeUploadResponse decodes the body of the upload server into the response,
+// errors of the upload server are returned as *errors.UploadAPIError
+func decodeUploadResponse(resp *http.Response, response interface{}) error {
+	data, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return err
+		return internalErrors.ErrorLog("Request.decodeUploadResponse()", "Error read upload response: "+err.Error())
 	}
 
-	request.SetContentType(writer.FormDataContentType())
-
-	resp, err := request.PostData(ctx, buf)
-	if err != nil {
+	if err = uploadError(resp.StatusCode, data); err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 
-	err = json.NewDecoder(resp.Body).Decode(response)
+	err = json.Unmarshal(data, response)
 	if err != nil {
-		return err
+		return internalErrors.ErrorLog("Request.decodeUploadResponse()", "Error unmarshal upload response: "+err.Error())
 	}
 
 	return nil
 }
 
-func uploadFiles(request *BaseRequest, ctx context.Context, files *[]objects.UploadFile, response interface{}) error {
-	buf := new(bytes.Buffer)
-	writer := multipart.NewWriter(buf)
-	defer writer.Close()
+// uploadError returns *errors.UploadAPIError if the upload server responded with the error: the status is not 200,
+// the field error is set as a string or as an object, or the fields error_code, error_descr are set
+func uploadError(status int, data []byte) error {
+	var body struct {
+		Error json.RawMessage `json:"error"`
+		internalErrors.UploadAPIError
+	}
 
-	for i, file := range *files {
-		part, err := writer.CreateFormFile(fmt.Sprintf("%s%d", file.FieldName, i+1), file.Name)
-		if err != nil {
-			return err
+	if err := json.Unmarshal(data, &body); err != nil {
+		if status != http.StatusOK {
+			return &internalErrors.UploadAPIError{Code: status, Description: strings.TrimSpace(string(data))}
 		}
 
-		_, err = io.Copy(part, file.Data)
-		if err != nil {
-			return err
+		return nil
+	}
+
+	uploadErr := body.UploadAPIError
+
+	if len(body.Error) > 0 && string(body.Error) != "null" {
+		var message string
+		var object struct {
+			Code    int    `json:"error_code"`
+			Type    string `json:"type"`
+			Message string `json:"error_msg"`
+		}
+
+		if json.Unmarshal(body.Error, &message) == nil {
+			uploadErr.Err = message
+		} else if json.Unmarshal(body.Error, &object) == nil {
+			if uploadErr.Code == 0 {
+				uploadErr.Code = object.Code
+			}
+
+			uploadErr.Err = object.Type
+			if object.Message != "" {
+				uploadErr.Err = object.Message
+			}
 		}
 	}
 
-	request.SetContentType(writer.FormDataContentType())
+	if uploadErr.Err == "" && uploadErr.Code == 0 && uploadErr.Description == "" {
+		if status == http.StatusOK {
+			return nil
+		}
 
-	resp, err := request.PostData(ctx, buf)
-	if err != nil {
-		return err
+		uploadErr.Code = status
+		uploadErr.Description = http.StatusText(status)
 	}
-	defer resp.Body.Close()
 
-	err = json.NewDecoder(resp.Body).Decode(response)
-	if err != nil {
-		return err
+	return &uploadErr
+}
+
+func uploadFile(request *BaseRequest, ctx context.Context, file *objects.UploadFile, response interface{}) error {
+	return uploadMultipart(request, ctx, []multipartFile{{field: file.FieldName, file: file}}, nil, response)
+}
+
+func uploadFiles(request *BaseRequest, ctx context.Context, files *[]objects.UploadFile, response interface{}) error {
+	parts := make([]multipartFile, 0, len(*files))
+	for i := range *files {
+		file := &(*files)[i]
+		parts = append(parts, multipartFile{field: fmt.Sprintf("%s%d", file.FieldName, i+1), file: file})
 	}
 
-	return nil
+	return uploadMultipart(request, ctx, parts, nil, response)
 }
 
 func uploadFileSquareCrop(request *BaseRequest, ctx context.Context, file *objects.UploadFile, crop string, response interface{}) error {
-	buf := new(bytes.Buffer)
-	writer := multipart.NewWriter(buf)
-	defer writer.Close()
+	var fields map[string]string
+	if crop != "" {
+		fields = map[string]string{"_square_crop": crop}
+	}
+
+	return uploadMultipart(request, ctx, []multipartFile{{field: file.FieldName, file: file}}, fields, response)
+}
 
-	part, err := writer.CreateFormFile(file.FieldName, file.Name)
+// uploadMultipart posts the files as the multipart form, the data of the files is streamed without buffering
+func uploadMultipart(request *BaseRequest, ctx context.Context, files []multipartFile, fields map[string]string, response interface{}) error {
+	body, err := newMultipartBody(files, fields)
 	if err != nil {
 		return err
 	}
 
-	_, err = io.Copy(part, file.Data)
+	data, release, err := body.reader()
 	if err != nil {
 		return err
 	}
+	defer release()
 
-	request.SetContentType(writer.FormDataContentType())
-
-	if crop != "" {
-		err = writer.WriteField("_square_crop", crop)
-		if err != nil {
-			return err
-		}
-	}
+	request.SetContentType(body.contentType)
 
-	resp, err := request.PostData(ctx, buf)
+	resp, err := request.PostData(ctx, data)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	err = json.NewDecoder(resp.Body).Decode(response)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return decodeUploadResponse(resp, response)
 }
 
+const UploadPhotoAlbumFilesLimit = 5 // max number of the photos uploaded to the album by one request
+
 // UploadPhotoAlbumRequest
 //
 //	Acceptable formats: JPG, PNG, GIF.
@@ -140,10 +161,37 @@ func NewUploadPhotoAlbumRequest(a *api.API, actor actor.Actor) *UploadPhotoAlbum
 
 // Exec UploadFile.FieldName = "file"
 func (r *UploadPhotoAlbumRequest) Exec(ctx context.Context, files *[]objects.UploadFile) (response response.PhotosUploadPhotoAlbumResponse, err error) {
+	if err = r.Validate(files); err != nil {
+		return
+	}
+
 	err = uploadFiles(&r.BaseRequest, ctx, files, &response)
 	return
 }
 
+// Validate checks the files against UploadConstraintsPhoto before the upload, no more than 5 photos per request
+func (r *UploadPhotoAlbumRequest) Validate(files *[]objects.UploadFile) error {
+	if files == nil {
+		return internalErrors.Error("Request.UploadPhotoAlbumRequest.Validate()", "files are undefined")
+	}
+
+	if r.isValidationSkipped() {
+		return nil
+	}
+
+	if len(*files) > UploadPhotoAlbumFilesLimit {
+		return internalErrors.Error("Request.UploadPhotoAlbumRequest.Validate()", "too many photos, the limit is "+strconv.Itoa(UploadPhotoAlbumFilesLimit))
+	}
+
+	for i := range *files {
+		if err := r.validateUpload(&UploadConstraintsPhoto, &(*files)[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // UploadPhotoWallRequest
 //
 //	Acceptable formats: JPG, PNG, GIF.
@@ -164,10 +212,19 @@ func NewUploadPhotoWallRequest(a *api.API, actor actor.Actor) *UploadPhotoWallRe
 
 // Exec UploadFile.FieldName = "photo"
 func (r *UploadPhotoWallRequest) Exec(ctx context.Context, file *objects.UploadFile) (response response.PhotosUploadPhotoWallResponse, err error) {
+	if err = r.Validate(file); err != nil {
+		return
+	}
+
 	err = uploadFile(&r.BaseRequest, ctx, file, &response)
 	return
 }
 
+// Validate checks the file against UploadConstraintsPhoto before the upload
+func (r *UploadPhotoWallRequest) Validate(file *objects.UploadFile) error {
+	return r.validateUpload(&UploadConstraintsPhoto, file)
+}
+
 // UploadPhotoOwnerRequest
 //
 // Acceptable formats: JPG, PNG, GIF.
@@ -192,10 +249,20 @@ func NewUploadPhotoOwnerRequest(a *api.API, actor actor.Actor) *UploadPhotoOwner
 
 // Exec UploadFile.FieldName = "photo"
 func (r *UploadPhotoOwnerRequest) Exec(ctx context.Context, file *objects.UploadFile) (response response.PhotosUploadPhotoOwnerResponse, err error) {
+	if err = r.Validate(file); err != nil {
+		return
+	}
+
 	err = uploadFileSquareCrop(&r.BaseRequest, ctx, file, r.parameters.Get(constants.ParameterNameFieldSquareCrop), &response)
 	return
 }
 
+// Validate checks the file against UploadConstraintsPhotoOwner before the upload
+func (r *UploadPhotoOwnerRequest) Validate(file *objects.UploadFile) error {
+	return r.validateUpload(&UploadConstraintsPhotoOwner, file)
+}
+
+// SquareCrop x,y,w of the square thumbnail, CenterSquareCrop returns the largest square in the center of the image
 func (r *UploadPhotoOwnerRequest) SquareCrop(crop string) *UploadPhotoOwnerRequest {
 	r.parameters.Set(constants.ParameterNameFieldSquareCrop, crop)
 	return r
@@ -220,10 +287,19 @@ func NewUploadPhotoMessagesRequest(a *api.API, actor actor.Actor) *UploadPhotoMe
 
 // Exec UploadFile.FieldName = "photo"
 func (r *UploadPhotoMessagesRequest) Exec(ctx context.Context, file *objects.UploadFile) (response response.PhotosUploadPhotoMessageResponse, err error) {
+	if err = r.Validate(file); err != nil {
+		return
+	}
+
 	err = uploadFile(&r.BaseRequest, ctx, file, &response)
 	return
 }
 
+// Validate checks the file against UploadConstraintsPhoto before the upload
+func (r *UploadPhotoMessagesRequest) Validate(file *objects.UploadFile) error {
+	return r.validateUpload(&UploadConstraintsPhoto, file)
+}
+
 // UploadPhotoChatRequest
 //
 // Acceptable formats: JPG, PNG, GIF.
@@ -243,10 +319,19 @@ func NewUploadPhotoChatRequest(a *api.API, actor actor.Actor) *UploadPhotoChatRe
 
 // Exec UploadFile.FieldName = "photo"
 func (r *UploadPhotoChatRequest) Exec(ctx context.Context, file *objects.UploadFile) (response response.PhotosUploadPhotoChatResponse, err error) {
+	if err = r.Validate(file); err != nil {
+		return
+	}
+
 	err = uploadFile(&r.BaseRequest, ctx, file, &response)
 	return
 }
 
+// Validate checks the file against UploadConstraintsPhotoChat before the upload
+func (r *UploadPhotoChatRequest) Validate(file *objects.UploadFile) error {
+	return r.validateUpload(&UploadConstraintsPhotoChat, file)
+}
+
 // UploadPhotoMarketRequest
 //
 //	Acceptable formats: JPG, PNG, GIF.
@@ -267,10 +352,19 @@ func NewUploadPhotoMarketRequest(a *api.API, actor actor.Actor) *UploadPhotoMark
 
 // Exec UploadFile.FieldName = "file"
 func (r *UploadPhotoMarketRequest) Exec(ctx context.Context, file *objects.UploadFile) (response response.PhotosUploadPhotoMarketResponse, err error) {
+	if err = r.Validate(file); err != nil {
+		return
+	}
+
 	err = uploadFile(&r.BaseRequest, ctx, file, &response)
 	return
 }
 
+// Validate checks the file against UploadConstraintsPhotoMarket before the upload
+func (r *UploadPhotoMarketRequest) Validate(file *objects.UploadFile) error {
+	return r.validateUpload(&UploadConstraintsPhotoMarket, file)
+}
+
 // UploadPhotoMarketAlbumRequest Acceptable formats: JPG, PNG, GIF.
 //
 // Acceptable formats: JPG, PNG, GIF.
@@ -293,14 +387,24 @@ func NewUploadPhotoMarketAlbumRequest(a *api.API, actor actor.Actor) *UploadPhot
 
 // Exec UploadFile.FieldName = "file"
 func (r *UploadPhotoMarketAlbumRequest) Exec(ctx context.Context, file *objects.UploadFile) (response response.PhotosUploadPhotoMarketAlbumResponse, err error) {
+	if err = r.Validate(file); err != nil {
+		return
+	}
+
 	err = uploadFile(&r.BaseRequest, ctx, file, &response)
 	return
 }
 
+// Validate checks the file against UploadConstraintsPhotoMarketAlbum before the upload
+func (r *UploadPhotoMarketAlbumRequest) Validate(file *objects.UploadFile) error {
+	return r.validateUpload(&UploadConstraintsPhotoMarketAlbum, file)
+}
+
 // UploadVideoRequest
 //
 // Acceptable formats: AVI, MP4, 3GP, MPEG, MOV, MP3, FLV, WMV.
 //
+//	Large files are uploaded by UploadVideoChunkedRequest, which can be resumed after a failure.
 //	Doc: https://dev.vk.com/ru/api/upload/video-in-profile
 type UploadVideoRequest struct {
 	BaseRequest
@@ -336,10 +440,19 @@ func NewUploadDocumentRequest(a *api.API, actor actor.Actor) *UploadDocumentRequ
 
 // Exec UploadFile.FieldName = "file"
 func (r *UploadDocumentRequest) Exec(ctx context.Context, file *objects.UploadFile) (response response.PhotosUploadDocumentResponse, err error) {
+	if err = r.Validate(file); err != nil {
+		return
+	}
+
 	err = uploadFile(&r.BaseRequest, ctx, file, &response)
 	return
 }
 
+// Validate checks the file against UploadConstraintsDocument before the upload
+func (r *UploadDocumentRequest) Validate(file *objects.UploadFile) error {
+	return r.validateUpload(&UploadConstraintsDocument, file)
+}
+
 // UploadPhotoOwnerCoverRequest
 //
 //	Acceptable formats: JPG, PNG, GIF.
@@ -362,10 +475,19 @@ func NewUploadPhotoOwnerCoverRequest(a *api.API, actor actor.Actor) *UploadPhoto
 
 // Exec UploadFile.FieldName = "photo"
 func (r *UploadPhotoOwnerCoverRequest) Exec(ctx context.Context, file *objects.UploadFile) (response response.PhotosUploadPhotoOwnerResponse, err error) {
+	if err = r.Validate(file); err != nil {
+		return
+	}
+
 	err = uploadFile(&r.BaseRequest, ctx, file, &response)
 	return
 }
 
+// Validate checks the file against UploadConstraintsPhotoOwnerCover before the upload
+func (r *UploadPhotoOwnerCoverRequest) Validate(file *objects.UploadFile) error {
+	return r.validateUpload(&UploadConstraintsPhotoOwnerCover, file)
+}
+
 // UploadAudioMessageRequest
 //
 //	Acceptable formats: OGG, OPUS.
@@ -386,10 +508,19 @@ func NewUploadAudioMessageRequest(a *api.API, actor actor.Actor) *UploadAudioMes
 
 // Exec UploadFile.FieldName = "file"
 func (r *UploadAudioMessageRequest) Exec(ctx context.Context, file *objects.UploadFile) (response response.AudioUploadAudioMessageResponse, err error) {
+	if err = r.Validate(file); err != nil {
+		return
+	}
+
 	err = uploadFile(&r.BaseRequest, ctx, file, &response)
 	return
 }
 
+// Validate checks the file against UploadConstraintsAudioMessage before the upload
+func (r *UploadAudioMessageRequest) Validate(file *objects.UploadFile) error {
+	return r.validateUpload(&UploadConstraintsAudioMessage, file)
+}
+
 // UploadPhotoStoriesRequest
 //
 //	Acceptable formats: JPG, PNG, GIF.
@@ -406,10 +537,19 @@ func NewUploadPhotoStoriesRequest(a *api.API, actor actor.Actor) *UploadPhotoSto
 
 // Exec UploadFile.FieldName = "file"
 func (r *UploadPhotoStoriesRequest) Exec(ctx context.Context, file *objects.UploadFile) (response response.PhotosUploadPhotoStoriesResponse, err error) {
+	if err = r.Validate(file); err != nil {
+		return
+	}
+
 	err = uploadFile(&r.BaseRequest, ctx, file, &response)
 	return
 }
 
+// Validate checks the file against UploadConstraintsPhotoStories before the upload
+func (r *UploadPhotoStoriesRequest) Validate(file *objects.UploadFile) error {
+	return r.validateUpload(&UploadConstraintsPhotoStories, file)
+}
+
 // UploadVideoStoriesRequest
 //
 //	Acceptable formats: H.264, AAC, MP4
@@ -426,10 +566,19 @@ func NewUploadVideoStoriesRequest(a *api.API, actor actor.Actor) *UploadVideoSto
 
 // Exec UploadFile.FieldName = "video"
 func (r *UploadVideoStoriesRequest) Exec(ctx context.Context, file *objects.UploadFile) (response response.VideosUploadVideoStoriesResponse, err error) {
+	if err = r.Validate(file); err != nil {
+		return
+	}
+
 	err = uploadFile(&r.BaseRequest, ctx, file, &response)
 	return
 }
 
+// Validate checks the file against UploadConstraintsVideoStories before the upload
+func (r *UploadVideoStoriesRequest) Validate(file *objects.UploadFile) error {
+	return r.validateUpload(&UploadConstraintsVideoStories, file)
+}
+
 // UploadPollsPhotoRequest
 //
 //	Acceptable formats: JPG, PNG, GIF.
@@ -445,10 +594,19 @@ func NewUploadPollsPhotoRequest(a *api.API, actor actor.Actor) *UploadPollsPhoto
 
 // Exec UploadFile.FieldName = "photo"
 func (r *UploadPollsPhotoRequest) Exec(ctx context.Context, file *objects.UploadFile) (response response.PollsUploadPhotoResponse, err error) {
+	if err = r.Validate(file); err != nil {
+		return
+	}
+
 	err = uploadFile(&r.BaseRequest, ctx, file, &response)
 	return
 }
 
+// Validate checks the file against UploadConstraintsPhotoPolls before the upload
+func (r *UploadPollsPhotoRequest) Validate(file *objects.UploadFile) error {
+	return r.validateUpload(&UploadConstraintsPhotoPolls, file)
+}
+
 // UploadPrettyCardsPhotoRequest
 //
 //	Acceptable formats: JPG, PNG, GIF.
@@ -463,10 +621,19 @@ func NewUploadPrettyCardsPhotoRequest(a *api.API, actor actor.Actor) *UploadPret
 
 // Exec UploadFile.FieldName = "file"
 func (r *UploadPrettyCardsPhotoRequest) Exec(ctx context.Context, file *objects.UploadFile) (response response.PrettyCardsUploadPhotoResponse, err error) {
+	if err = r.Validate(file); err != nil {
+		return
+	}
+
 	err = uploadFile(&r.BaseRequest, ctx, file, &response)
 	return
 }
 
+// Validate checks the file against UploadConstraintsPhoto before the upload
+func (r *UploadPrettyCardsPhotoRequest) Validate(file *objects.UploadFile) error {
+	return r.validateUpload(&UploadConstraintsPhoto, file)
+}
+
 // UploadLeadFormsPhotoRequest
 //
 //	Acceptable formats: JPG, PNG, GIF.
@@ -481,10 +648,19 @@ func NewUploadLeadFormsPhotoRequest(a *api.API, actor actor.Actor) *UploadLeadFo
 
 // Exec UploadFile.FieldName = "file"
 func (r *UploadLeadFormsPhotoRequest) Exec(ctx context.Context, file *objects.UploadFile) (response response.LeadFormsUploadPhotoResponse, err error) {
+	if err = r.Validate(file); err != nil {
+		return
+	}
+
 	err = uploadFile(&r.BaseRequest, ctx, file, &response)
 	return
 }
 
+// Validate checks the file against UploadConstraintsPhoto before the upload
+func (r *UploadLeadFormsPhotoRequest) Validate(file *objects.UploadFile) error {
+	return r.validateUpload(&UploadConstraintsPhoto, file)
+}
+
 // UploadAppImageRequest uploading Image into App collection for community app widgets.
 type UploadAppImageRequest struct {
 	BaseRequest
@@ -528,10 +704,19 @@ func NewUploadMarusiaPictureRequest(a *api.API, actor actor.Actor) *UploadMarusi
 
 // Exec UploadFile.FieldName = "photo"
 func (r *UploadMarusiaPictureRequest) Exec(ctx context.Context, file *objects.UploadFile) (response response.MarusiaUploadPictureResponse, err error) {
+	if err = r.Validate(file); err != nil {
+		return
+	}
+
 	err = uploadFile(&r.BaseRequest, ctx, file, &response)
 	return
 }
 
+// Validate checks the file against UploadConstraintsMarusiaPicture before the upload
+func (r *UploadMarusiaPictureRequest) Validate(file *objects.UploadFile) error {
+	return r.validateUpload(&UploadConstraintsMarusiaPicture, file)
+}
+
 // UploadMarusiaAudioRequest
 //
 //	https://dev.vk.com/ru/marusia/media-api
@@ -545,6 +730,15 @@ func NewUploadMarusiaAudioRequest(a *api.API, actor actor.Actor) *UploadMarusiaA
 
 // Exec UploadFile.FieldName = "file"
 func (r *UploadMarusiaAudioRequest) Exec(ctx context.Context, file *objects.UploadFile) (response response.MarusiaUploadAudioResponse, err error) {
+	if err = r.Validate(file); err != nil {
+		return
+	}
+
 	err = uploadFile(&r.BaseRequest, ctx, file, &response)
 	return
 }
+
+// Validate checks the file against UploadConstraintsMarusiaAudio before the upload
+func (r *UploadMarusiaAudioRequest) Validate(file *objects.UploadFile) error {
+	return r.validateUpload(&UploadConstraintsMarusiaAudio, file)
+}
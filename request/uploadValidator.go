@@ -0,0 +1,579 @@
+package request
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	internalErrors "go-vk-sdk/errors"
+	"go-vk-sdk/objects"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Doc: https://dev.vk.com/ru/api/upload/overview
+
+const uploadSniffLength = 1 << 20 // number of the first bytes of the file read to detect the format and the size of the image
+
+// UploadConstraints Restrictions of the upload server which are checked before the upload,
+// so the bad file is rejected with a descriptive error instead of an opaque error of VK.
+//
+//	The content type is detected by http.DetectContentType, which is extended with MP3 without ID3 tag and QuickTime,
+//	the size of the image by image.DecodeConfig, the duration of the MP4 and QuickTime video from its mvhd box.
+//	The zero value of a field disables its check.
+//	The size and the duration are checked only if the data implements io.Seeker or Len() int.
+//	The data is read without being consumed: io.Seeker is rewound, other readers are replaced by the reader
+//	which returns the read bytes again, so UploadFile.Data is modified by the validation.
+//
+//	The upload requests validate the file by Exec with the constraints of their type,
+//	BaseRequest.SetUploadConstraints replaces them and BaseRequest.SkipValidation disables the validation.
+type UploadConstraints struct {
+	ContentTypes        []string      // allowed content types
+	ForbiddenExtensions []string      // forbidden extensions of the file name, with the dot
+	ForbidExecutable    bool          // reject PE and ELF executables
+	MaxSize             int64         // max size of the file in bytes
+	MinWidth            int           // min width of the image in pixels
+	MinHeight           int           // min height of the image in pixels
+	MaxHeight           int           // max height of the image in pixels
+	MaxSidesSum         int           // max sum of the width and the height of the image
+	MaxSidesRatio       float64       // max ratio of the longer side to the shorter one, 20 for 1:20
+	MinWidthToHeight    float64       // min ratio of the width to the height
+	MaxWidthToHeight    float64       // max ratio of the width to the height
+	MaxDuration         time.Duration // max duration of the MP4 or QuickTime video
+}
+
+var (
+	uploadContentTypesPhoto = []string{"image/jpeg", "image/png", "image/gif"}
+
+	// UploadConstraintsPhoto Photos of the albums, the wall, the messages, pretty cards and lead forms
+	UploadConstraintsPhoto = UploadConstraints{
+		ContentTypes:  uploadContentTypesPhoto,
+		MaxSize:       50 << 20,
+		MaxSidesSum:   14000,
+		MaxSidesRatio: 20,
+	}
+
+	// UploadConstraintsPhotoOwner Main photo of the user or the community
+	UploadConstraintsPhotoOwner = UploadConstraints{
+		ContentTypes:     uploadContentTypesPhoto,
+		MaxSize:          50 << 20,
+		MinWidth:         200,
+		MinHeight:        200,
+		MaxSidesSum:      14000,
+		MinWidthToHeight: 0.25,
+		MaxWidthToHeight: 3,
+	}
+
+	// UploadConstraintsPhotoChat Main photo of the chat
+	UploadConstraintsPhotoChat = UploadConstraints{
+		ContentTypes:  uploadContentTypesPhoto,
+		MaxSize:       50 << 20,
+		MinWidth:      200,
+		MinHeight:     200,
+		MaxSidesSum:   14000,
+		MaxSidesRatio: 20,
+	}
+
+	// UploadConstraintsPhotoOwnerCover Cover of the community
+	UploadConstraintsPhotoOwnerCover = UploadConstraints{
+		ContentTypes:  uploadContentTypesPhoto,
+		MaxSize:       50 << 20,
+		MinWidth:      795,
+		MinHeight:     265,
+		MaxSidesSum:   14000,
+		MaxSidesRatio: 20,
+	}
+
+	// UploadConstraintsPhotoMarket Photo of the product
+	UploadConstraintsPhotoMarket = UploadConstraints{
+		ContentTypes:  uploadContentTypesPhoto,
+		MaxSize:       50 << 20,
+		MinWidth:      400,
+		MinHeight:     400,
+		MaxSidesSum:   14000,
+		MaxSidesRatio: 20,
+	}
+
+	// UploadConstraintsPhotoMarketAlbum Main photo of the collection of the products
+	UploadConstraintsPhotoMarketAlbum = UploadConstraints{
+		ContentTypes:  uploadContentTypesPhoto,
+		MaxSize:       50 << 20,
+		MinWidth:      1280,
+		MinHeight:     720,
+		MaxSidesSum:   14000,
+		MaxSidesRatio: 20,
+	}
+
+	// UploadConstraintsPhotoPolls Background of the poll
+	UploadConstraintsPhotoPolls = UploadConstraints{
+		ContentTypes: uploadContentTypesPhoto,
+		MaxSize:      50 << 20,
+		MinWidth:     795,
+		MinHeight:    200,
+		MaxSidesSum:  14000,
+	}
+
+	// UploadConstraintsPhotoStories Photo of the story
+	UploadConstraintsPhotoStories = UploadConstraints{
+		ContentTypes: uploadContentTypesPhoto,
+		MaxSize:      10 << 20,
+		MaxSidesSum:  14000,
+	}
+
+	// UploadConstraintsVideoStories Video of the story, MP4 or MOV
+	UploadConstraintsVideoStories = UploadConstraints{
+		ContentTypes: []string{"video/mp4", "video/quicktime"},
+		MaxDuration:  15 * time.Second,
+	}
+
+	// UploadConstraintsDocument Document, any file except MP3 and executable files
+	UploadConstraintsDocument = UploadConstraints{
+		ForbiddenExtensions: []string{".mp3", ".exe", ".com", ".bat", ".cmd", ".msi", ".scr", ".pif", ".vbs", ".apk"},
+		ForbidExecutable:    true,
+		MaxSize:             200 << 20,
+	}
+
+	// UploadConstraintsAudioMessage Voice message, OGG or OPUS
+	UploadConstraintsAudioMessage = UploadConstraints{
+		ContentTypes: []string{"application/ogg", "audio/ogg"},
+	}
+
+	// UploadConstraintsMarusiaPicture Picture of the skill of Marusia
+	UploadConstraintsMarusiaPicture = UploadConstraints{
+		ContentTypes: []string{"image/jpeg", "image/png"},
+		MaxHeight:    600,
+	}
+
+	// UploadConstraintsMarusiaAudio Audio of the skill of Marusia, MP3, WAV or OGG
+	UploadConstraintsMarusiaAudio = UploadConstraints{
+		ContentTypes: []string{"audio/mpeg", "audio/wave", "application/ogg", "audio/ogg"},
+	}
+)
+
+// Validate returns the error describing the first violated restriction
+func (c *UploadConstraints) Validate(file *objects.UploadFile) error {
+	const from = "Request.UploadConstraints.Validate()"
+
+	if file == nil || file.Data == nil {
+		return internalErrors.Error(from, "data of the file is undefined")
+	}
+
+	prefix := "file " + file.Name + ": "
+
+	ext := strings.ToLower(filepath.Ext(file.Name))
+	if ext != "" && slices.Contains(c.ForbiddenExtensions, ext) {
+		return internalErrors.Error(from, prefix+"extension "+ext+" is not allowed")
+	}
+
+	size := uploadSize(file.Data)
+	if c.MaxSize > 0 && size > c.MaxSize {
+		return internalErrors.Error(from, prefix+"size "+strconv.FormatInt(size, 10)+" bytes is more than "+
+			strconv.FormatInt(c.MaxSize, 10)+" bytes")
+	}
+
+	head, err := uploadHead(file, uploadSniffLength)
+	if err != nil {
+		return internalErrors.Error(from, prefix+"error read data "+err.Error())
+	}
+
+	if len(head) == 0 {
+		return internalErrors.Error(from, prefix+"file is empty")
+	}
+
+	if c.ForbidExecutable && isExecutable(head) {
+		return internalErrors.Error(from, prefix+"executable files are not allowed")
+	}
+
+	contentType := detectContentType(head)
+	if len(c.ContentTypes) > 0 && !slices.Contains(c.ContentTypes, contentType) {
+		return internalErrors.Error(from, prefix+"content type "+contentType+" is not allowed, allowed: "+strings.Join(c.ContentTypes, ", "))
+	}
+
+	if strings.HasPrefix(contentType, "image/") && c.checksImage() {
+		config, known, err := uploadImageConfig(file, head)
+		if err != nil {
+			return internalErrors.Error(from, prefix+"error decoding image "+err.Error())
+		}
+
+		if message := c.validateImage(config.Width, config.Height); known && message != "" {
+			return internalErrors.Error(from, prefix+message)
+		}
+	}
+
+	if c.MaxDuration > 0 && (contentType == "video/mp4" || contentType == "video/quicktime") {
+		if seeker, ok := file.Data.(io.ReadSeeker); ok {
+			duration, err := mp4Duration(seeker)
+			if err != nil {
+				return internalErrors.Error(from, prefix+"error read duration of the video "+err.Error())
+			}
+
+			if duration > c.MaxDuration {
+				return internalErrors.Error(from, prefix+"duration "+duration.String()+" is more than "+c.MaxDuration.String())
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetUploadConstraints replaces the constraints of the upload request which are checked by Exec before the upload
+func (r *BaseRequest) SetUploadConstraints(constraints UploadConstraints) *BaseRequest {
+	r.mtx.Lock()
+	r.constraints = &constraints
+	r.mtx.Unlock()
+	return r
+}
+
+// SkipValidation disables the validation of the file by the upload request, Validate returns nil
+func (r *BaseRequest) SkipValidation(skip bool) *BaseRequest {
+	r.mtx.Lock()
+	r.skipValidation = skip
+	r.mtx.Unlock()
+	return r
+}
+
+func (r *BaseRequest) isValidationSkipped() bool {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	return r.skipValidation
+}
+
+// validateUpload checks the file against the constraints set by SetUploadConstraints or the default constraints of the request
+func (r *BaseRequest) validateUpload(defaults *UploadConstraints, file *objects.UploadFile) error {
+	r.mtx.RLock()
+	constraints, skip := r.constraints, r.skipValidation
+	r.mtx.RUnlock()
+
+	if skip {
+		return nil
+	}
+
+	if constraints == nil {
+		constraints = defaults
+	}
+
+	return constraints.Validate(file)
+}
+
+// detectContentType returns the content type detected by http.DetectContentType without parameters,
+// MP3 without ID3 tag and QuickTime video, which it does not detect, are recognised by their headers
+func detectContentType(head []byte) string {
+	contentType, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	if contentType != "application/octet-stream" {
+		return contentType
+	}
+
+	if isMPEGAudioFrame(head) {
+		return "audio/mpeg"
+	}
+
+	if isQuickTime(head) {
+		return "video/quicktime"
+	}
+
+	return contentType
+}
+
+// isMPEGAudioFrame reports whether the data starts with the header of the MPEG audio frame:
+// 11 bits of frame sync, not reserved version and layer, not reserved bitrate and sample rate
+func isMPEGAudioFrame(head []byte) bool {
+	if len(head) < 4 || head[0] != 0xFF || head[1]&0xE0 != 0xE0 {
+		return false
+	}
+
+	version := head[1] >> 3 & 0x03
+	layer := head[1] >> 1 & 0x03
+	bitrate := head[2] >> 4
+	sampleRate := head[2] >> 2 & 0x03
+
+	return version != 0x01 && layer != 0x00 && bitrate != 0x0F && sampleRate != 0x03
+}
+
+// isQuickTime reports whether the data starts with the ftyp box of the brand "qt  "
+// or with one of the top level atoms of the QuickTime file without ftyp
+func isQuickTime(head []byte) bool {
+	if len(head) < 12 {
+		return false
+	}
+
+	switch string(head[4:8]) {
+	case "ftyp":
+		return string(head[8:12]) == "qt  "
+	case "moov", "mdat", "wide", "free", "skip", "pnot":
+		return true
+	}
+
+	return false
+}
+
+// isExecutable reports whether the data is the PE or ELF executable.
+// The PE file starts with the MZ header, whose field e_lfanew at 0x3C points to the signature PE\0\0,
+// so text files starting with "MZ" are not rejected
+func isExecutable(head []byte) bool {
+	if bytes.HasPrefix(head, []byte("\x7fELF")) {
+		return true
+	}
+
+	if !bytes.HasPrefix(head, []byte("MZ")) || len(head) < 0x40 {
+		return false
+	}
+
+	offset := int64(binary.LittleEndian.Uint32(head[0x3C:0x40]))
+
+	return offset >= 0x40 && offset+4 <= int64(len(head)) && bytes.Equal(head[offset:offset+4], []byte("PE\x00\x00"))
+}
+
+func (c *UploadConstraints) checksImage() bool {
+	return c.MinWidth > 0 || c.MinHeight > 0 || c.MaxHeight > 0 || c.MaxSidesSum > 0 ||
+		c.MaxSidesRatio > 0 || c.MinWidthToHeight > 0 || c.MaxWidthToHeight > 0
+}
+
+// validateImage returns the description of the violated restriction of the image, empty if the image is valid
+func (c *UploadConstraints) validateImage(width, height int) string {
+	sides := strconv.Itoa(width) + "x" + strconv.Itoa(height)
+
+	if width <= 0 || height <= 0 {
+		return "invalid size of the image " + sides
+	}
+
+	if width < c.MinWidth || height < c.MinHeight {
+		return "size of the image " + sides + " is less than " + strconv.Itoa(c.MinWidth) + "x" + strconv.Itoa(c.MinHeight)
+	}
+
+	if c.MaxHeight > 0 && height > c.MaxHeight {
+		return "height of the image " + strconv.Itoa(height) + " is more than " + strconv.Itoa(c.MaxHeight)
+	}
+
+	if c.MaxSidesSum > 0 && width+height > c.MaxSidesSum {
+		return "sum of the width and the height of the image " + sides + " is more than " + strconv.Itoa(c.MaxSidesSum)
+	}
+
+	if c.MaxSidesRatio > 0 && float64(max(width, height))/float64(min(width, height)) > c.MaxSidesRatio {
+		return "aspect ratio of the image " + sides + " is more than 1:" + strconv.FormatFloat(c.MaxSidesRatio, 'f', -1, 64)
+	}
+
+	ratio := float64(width) / float64(height)
+	if (c.MinWidthToHeight > 0 && ratio < c.MinWidthToHeight) || (c.MaxWidthToHeight > 0 && ratio > c.MaxWidthToHeight) {
+		return "aspect ratio of the image " + sides + " is out of the range " +
+			strconv.FormatFloat(c.MinWidthToHeight, 'f', -1, 64) + " - " + strconv.FormatFloat(c.MaxWidthToHeight, 'f', -1, 64)
+	}
+
+	return ""
+}
+
+// CenterSquareCrop returns _square_crop of the largest square in the center of the image in the format x,y,w
+// for UploadPhotoOwnerRequest.SquareCrop
+func CenterSquareCrop(file *objects.UploadFile) (string, error) {
+	head, err := uploadHead(file, uploadSniffLength)
+	if err != nil {
+		return "", internalErrors.Error("Request.CenterSquareCrop()", "error read data "+err.Error())
+	}
+
+	config, known, err := uploadImageConfig(file, head)
+	if err != nil {
+		return "", internalErrors.Error("Request.CenterSquareCrop()", "error decoding image "+err.Error())
+	}
+
+	if !known {
+		return "", internalErrors.Error("Request.CenterSquareCrop()", "size of the image is not in the first "+
+			strconv.Itoa(uploadSniffLength)+" bytes, the data must implement io.Seeker")
+	}
+
+	side := min(config.Width, config.Height)
+
+	return strconv.Itoa((config.Width-side)/2) + "," + strconv.Itoa((config.Height-side)/2) + "," + strconv.Itoa(side), nil
+}
+
+// uploadImageConfig returns the size of the image, io.ReadSeeker is decoded from the data itself and rewound,
+// other data is decoded from the head. known is false if the size is not in the head, for example
+// when EXIF or ICC segments of JPEG take more than uploadSniffLength bytes
+func uploadImageConfig(file *objects.UploadFile, head []byte) (config image.Config, known bool, err error) {
+	if seeker, ok := file.Data.(io.ReadSeeker); ok {
+		offset, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return config, false, err
+		}
+
+		config, _, err = image.DecodeConfig(seeker)
+
+		if _, seekErr := seeker.Seek(offset, io.SeekStart); seekErr != nil && err == nil {
+			err = seekErr
+		}
+
+		return config, err == nil, err
+	}
+
+	config, _, err = image.DecodeConfig(bytes.NewReader(head))
+	if errors.Is(err, io.ErrUnexpectedEOF) && len(head) >= uploadSniffLength {
+		return config, false, nil
+	}
+
+	return config, err == nil, err
+}
+
+// uploadSize returns the number of the unread bytes of the data or -1 if it is unknown
+func uploadSize(data io.Reader) int64 {
+	switch d := data.(type) {
+	case interface{ Len() int }:
+		return int64(d.Len())
+	case io.Seeker:
+		offset, err := d.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+
+		end, err := d.Seek(0, io.SeekEnd)
+		if err != nil {
+			return -1
+		}
+
+		if _, err = d.Seek(offset, io.SeekStart); err != nil {
+			return -1
+		}
+
+		return end - offset
+	}
+
+	return -1
+}
+
+// uploadHead returns up to n first unread bytes of the data of the file without consuming them.
+// io.Seeker is rewound, other data is replaced by io.MultiReader of the read bytes and the rest of the data,
+// so file.Data is modified and the caller must use it after the call instead of the original reader
+func uploadHead(file *objects.UploadFile, n int) ([]byte, error) {
+	if file == nil || file.Data == nil {
+		return nil, internalErrors.Error("Request.uploadHead()", "data of the file is undefined")
+	}
+
+	seeker, isSeeker := file.Data.(io.Seeker)
+
+	var offset int64
+	if isSeeker {
+		var err error
+		if offset, err = seeker.Seek(0, io.SeekCurrent); err != nil {
+			return nil, err
+		}
+	}
+
+	head := make([]byte, n)
+	read, err := io.ReadFull(file.Data, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	head = head[:read]
+
+	if isSeeker {
+		if _, err = seeker.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+	} else {
+		file.Data = io.MultiReader(bytes.NewReader(head), file.Data)
+	}
+
+	return head, nil
+}
+
+// mp4Duration reads the duration of the video from the mvhd box of the moov box, the reader is rewound
+func mp4Duration(r io.ReadSeeker) (time.Duration, error) {
+	start, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Seek(start, io.SeekStart)
+
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+
+	moov, moovEnd, err := mp4FindBox(r, start, end, "moov")
+	if err != nil {
+		return 0, err
+	}
+
+	mvhd, _, err := mp4FindBox(r, moov, moovEnd, "mvhd")
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err = r.Seek(mvhd, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	header := make([]byte, 32)
+	if _, err = io.ReadFull(r, header[:4]); err != nil {
+		return 0, err
+	}
+
+	var timescale, duration uint64
+	if header[0] == 1 {
+		// version 1: creation_time(8) modification_time(8) timescale(4) duration(8)
+		if _, err = io.ReadFull(r, header[4:32]); err != nil {
+			return 0, err
+		}
+		timescale = uint64(binary.BigEndian.Uint32(header[20:24]))
+		duration = binary.BigEndian.Uint64(header[24:32])
+	} else {
+		// version 0: creation_time(4) modification_time(4) timescale(4) duration(4)
+		if _, err = io.ReadFull(r, header[4:20]); err != nil {
+			return 0, err
+		}
+		timescale = uint64(binary.BigEndian.Uint32(header[12:16]))
+		duration = uint64(binary.BigEndian.Uint32(header[16:20]))
+	}
+
+	if timescale == 0 {
+		return 0, internalErrors.Error("Request.mp4Duration()", "timescale of the video is 0")
+	}
+
+	return time.Duration(float64(duration) / float64(timescale) * float64(time.Second)), nil
+}
+
+// mp4FindBox returns the offsets of the content and of the end of the first box of the type between the offsets
+func mp4FindBox(r io.ReadSeeker, from, to int64, kind string) (int64, int64, error) {
+	header := make([]byte, 16)
+
+	for offset := from; offset+8 <= to; {
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return 0, 0, err
+		}
+
+		if _, err := io.ReadFull(r, header[:8]); err != nil {
+			return 0, 0, err
+		}
+
+		size := int64(binary.BigEndian.Uint32(header[:4]))
+		content := offset + 8
+
+		switch size {
+		case 0:
+			size = to - offset
+		case 1:
+			if _, err := io.ReadFull(r, header[8:16]); err != nil {
+				return 0, 0, err
+			}
+			size = int64(binary.BigEndian.Uint64(header[8:16]))
+			content += 8
+		}
+
+		if size < content-offset || offset+size > to {
+			return 0, 0, internalErrors.Error("Request.mp4FindBox()", "invalid size of the box "+string(header[4:8]))
+		}
+
+		if string(header[4:8]) == kind {
+			return content, offset + size, nil
+		}
+
+		offset += size
+	}
+
+	return 0, 0, internalErrors.Error("Request.mp4FindBox()", "box "+kind+" is not found")
+}
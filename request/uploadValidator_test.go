@@ -0,0 +1,193 @@
+package request_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"go-vk-sdk/actor"
+	"go-vk-sdk/api"
+	"go-vk-sdk/objects"
+	"go-vk-sdk/request"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+)
+
+func pngFile(t *testing.T, width, height int) *objects.UploadFile {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+
+	return objects.NewUploadFilePhoto("photo.png", bytes.NewReader(buf.Bytes()))
+}
+
+// quickTime returns the QuickTime file with the ftyp box of the brand "qt  " and mvhd of the duration
+func quickTime(seconds uint32) []byte {
+	box := func(kind string, content []byte) []byte {
+		header := binary.BigEndian.AppendUint32(nil, uint32(8+len(content)))
+		return append(append(header, kind...), content...)
+	}
+
+	mvhd := make([]byte, 20)
+	binary.BigEndian.PutUint32(mvhd[12:16], 1000)
+	binary.BigEndian.PutUint32(mvhd[16:20], seconds*1000)
+
+	ftyp := box("ftyp", []byte("qt  \x00\x00\x00\x00qt  "))
+
+	return append(ftyp, box("moov", box("mvhd", mvhd))...)
+}
+
+func TestUploadConstraintsImage(t *testing.T) {
+	if err := request.UploadConstraintsPhoto.Validate(pngFile(t, 300, 200)); err != nil {
+		t.Errorf("valid photo: %v", err)
+	}
+
+	err := request.UploadConstraintsPhotoOwner.Validate(pngFile(t, 100, 100))
+	if err == nil || !strings.Contains(err.Error(), "is less than 200x200") {
+		t.Errorf("got error %v for the small photo", err)
+	}
+
+	err = request.UploadConstraintsPhoto.Validate(pngFile(t, 1000, 10))
+	if err == nil || !strings.Contains(err.Error(), "aspect ratio") {
+		t.Errorf("got error %v for the narrow photo", err)
+	}
+
+	text := objects.NewUploadFilePhoto("photo.png", strings.NewReader("not a photo"))
+	if err = request.UploadConstraintsPhoto.Validate(text); err == nil || !strings.Contains(err.Error(), "text/plain") {
+		t.Errorf("got error %v for the text", err)
+	}
+}
+
+// largeHeaderJPEG returns JPEG whose APP1 segments before the SOF marker take more than 1 MiB
+func largeHeaderJPEG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height)), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+	segments := []byte{}
+	for len(segments) < 1<<20+1 {
+		segment := []byte{0xFF, 0xE1, 0xFF, 0xFF}
+		segments = append(segments, append(segment, make([]byte, 0xFFFF-2)...)...)
+	}
+
+	return append(append(append([]byte{}, data[:2]...), segments...), data[2:]...)
+}
+
+func TestUploadConstraintsLargeImageHeader(t *testing.T) {
+	data := largeHeaderJPEG(t, 100, 100)
+
+	// the seekable data is decoded entirely, so the size is checked
+	err := request.UploadConstraintsPhotoOwner.Validate(objects.NewUploadFilePhoto("photo.jpg", bytes.NewReader(data)))
+	if err == nil || !strings.Contains(err.Error(), "is less than 200x200") {
+		t.Errorf("got error %v for the seekable photo", err)
+	}
+
+	// the size of the other data is unknown and is not checked
+	file := objects.NewUploadFilePhoto("photo.jpg", io.MultiReader(bytes.NewReader(data)))
+	if err = request.UploadConstraintsPhotoOwner.Validate(file); err != nil {
+		t.Errorf("got error %v for the photo without Seek", err)
+	}
+
+	if _, err = request.CenterSquareCrop(objects.NewUploadFilePhoto("photo.jpg", bytes.NewReader(data))); err != nil {
+		t.Errorf("square crop: %v", err)
+	}
+}
+
+func TestUploadConstraintsMP3WithoutID3(t *testing.T) {
+	// MPEG-1 Layer III, 128 kbit/s, 44100 Hz
+	frame := append([]byte{0xFF, 0xFB, 0x90, 0x64}, make([]byte, 413)...)
+
+	if err := request.UploadConstraintsMarusiaAudio.Validate(objects.NewUploadFile("a.mp3", bytes.NewReader(frame))); err != nil {
+		t.Errorf("MP3 frame: %v", err)
+	}
+
+	// the reserved layer is not the frame sync
+	reserved := append([]byte{0xFF, 0xF9, 0x90, 0x64}, make([]byte, 413)...)
+	if err := request.UploadConstraintsMarusiaAudio.Validate(objects.NewUploadFile("a.mp3", bytes.NewReader(reserved))); err == nil {
+		t.Error("the reserved layer is accepted")
+	}
+}
+
+func TestUploadConstraintsVideoStoriesQuickTime(t *testing.T) {
+	if err := request.UploadConstraintsVideoStories.Validate(objects.NewUploadFileVideo("a.mov", bytes.NewReader(quickTime(10)))); err != nil {
+		t.Errorf("QuickTime video: %v", err)
+	}
+
+	err := request.UploadConstraintsVideoStories.Validate(objects.NewUploadFileVideo("a.mov", bytes.NewReader(quickTime(20))))
+	if err == nil || !strings.Contains(err.Error(), "duration 20s") {
+		t.Errorf("got error %v for the long video", err)
+	}
+}
+
+func TestUploadConstraintsExecutable(t *testing.T) {
+	text := objects.NewUploadFile("notes.txt", strings.NewReader("MZ is the signature of the DOS executables"))
+	if err := request.UploadConstraintsDocument.Validate(text); err != nil {
+		t.Errorf("text starting with MZ: %v", err)
+	}
+
+	pe := make([]byte, 0x80)
+	copy(pe, "MZ")
+	binary.LittleEndian.PutUint32(pe[0x3C:], 0x40)
+	copy(pe[0x40:], "PE\x00\x00")
+
+	err := request.UploadConstraintsDocument.Validate(objects.NewUploadFile("setup.bin", bytes.NewReader(pe)))
+	if err == nil || !strings.Contains(err.Error(), "executable") {
+		t.Errorf("got error %v for the PE file", err)
+	}
+
+	elf := objects.NewUploadFile("tool", strings.NewReader("\x7fELF\x02\x01\x01"))
+	if err = request.UploadConstraintsDocument.Validate(elf); err == nil {
+		t.Error("the ELF file is accepted")
+	}
+}
+
+func TestUploadConstraintsKeepsData(t *testing.T) {
+	data := []byte("plain text of the document")
+
+	// the reader without Seek is replaced by the reader which returns the read bytes again
+	file := objects.NewUploadFile("doc.txt", io.MultiReader(bytes.NewReader(data)))
+	if err := request.UploadConstraintsDocument.Validate(file); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := io.ReadAll(file.Data)
+	if err != nil || !bytes.Equal(read, data) {
+		t.Errorf("got data %q, want %q", read, data)
+	}
+}
+
+func TestUploadRequestValidation(t *testing.T) {
+	a, g := api.NewAPI(), &actor.User{ID: 1, AccessToken: "token"}
+	text := func() *objects.UploadFile {
+		return objects.NewUploadFilePhoto("photo.png", strings.NewReader("not a photo"))
+	}
+
+	r := request.NewUploadPhotoWallRequest(a, g)
+	if err := r.Validate(text()); err == nil {
+		t.Error("the text is accepted by the default constraints")
+	}
+
+	r.SetUploadConstraints(request.UploadConstraints{ContentTypes: []string{"text/plain"}})
+	if err := r.Validate(text()); err != nil {
+		t.Errorf("custom constraints: %v", err)
+	}
+
+	r.SetUploadConstraints(request.UploadConstraintsPhoto)
+	r.SkipValidation(true)
+	if err := r.Validate(text()); err != nil {
+		t.Errorf("skipped validation: %v", err)
+	}
+
+	if err := request.NewUploadPhotoAlbumRequest(a, g).Validate(nil); err == nil {
+		t.Error("nil files are accepted")
+	}
+}
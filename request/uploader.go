@@ -0,0 +1,346 @@
+package request
+
+import (
+	"context"
+	"encoding/json"
+	"go-vk-sdk/actor"
+	"go-vk-sdk/api"
+	internalErrors "go-vk-sdk/errors"
+	"go-vk-sdk/objects"
+	"go-vk-sdk/response"
+)
+
+// Doc: https://dev.vk.com/ru/api/upload/overview
+
+// Uploader Uploads files in one call: gets the upload server, uploads the file and saves it,
+// the result is the object ready to be attached to the message or the post.
+//
+//	Errors of the api methods are returned as *errors.APIError, errors of the upload server as *errors.UploadAPIError.
+type Uploader struct {
+	api   *api.API
+	actor actor.Actor
+}
+
+func NewUploader(a *api.API, actor actor.Actor) *Uploader {
+	return &Uploader{
+		api:   a,
+		actor: actor,
+	}
+}
+
+// UploadWallPhoto uploads the photo to the wall of the community, groupID <= 0 uploads to the wall of the user.
+//
+//	Doc: https://dev.vk.com/ru/api/upload/wall-photo
+func (u *Uploader) UploadWallPhoto(ctx context.Context, groupID int, file *objects.UploadFile) (*objects.Photo, error) {
+	if err := checkUploadFile("Request.Uploader.UploadWallPhoto()", file); err != nil {
+		return nil, err
+	}
+
+	server := NewPhotosGetWallUploadServerRequest(u.api, u.actor)
+	if groupID > 0 {
+		server.GroupID(groupID)
+	}
+
+	serverResponse, err := server.Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	upload := NewUploadPhotoWallRequest(u.api, u.actor)
+	upload.SetURL(serverResponse.Response.UploadURL)
+
+	uploadResponse, err := upload.Exec(ctx, uploadFileField(file, "photo"))
+	if err != nil {
+		return nil, err
+	}
+
+	save := NewPhotosSaveWallPhotoRequest(u.api, u.actor).
+		Photo(uploadResponse.Photo).
+		Server(uploadResponse.Server).
+		Hash(uploadResponse.Hash)
+	if groupID > 0 {
+		save.GroupID(groupID)
+	}
+
+	saveResponse, err := save.Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(saveResponse.Response) == 0 {
+		return nil, internalErrors.Error("Request.Uploader.UploadWallPhoto()", "empty response of photos.saveWallPhoto")
+	}
+
+	return &saveResponse.Response[0], nil
+}
+
+// UploadMessagePhoto uploads the photo for the message to the peer.
+//
+//	Doc: https://dev.vk.com/ru/api/upload/photo-in-message
+func (u *Uploader) UploadMessagePhoto(ctx context.Context, peerID int, file *objects.UploadFile) (*objects.Photo, error) {
+	if err := checkUploadFile("Request.Uploader.UploadMessagePhoto()", file); err != nil {
+		return nil, err
+	}
+
+	serverResponse, err := NewPhotosGetMessagesUploadServerRequest(u.api, u.actor).
+		PeerID(peerID).
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	upload := NewUploadPhotoMessagesRequest(u.api, u.actor)
+	upload.SetURL(serverResponse.Response.UploadURL)
+
+	uploadResponse, err := upload.Exec(ctx, uploadFileField(file, "photo"))
+	if err != nil {
+		return nil, err
+	}
+
+	saveResponse, err := NewPhotosSaveMessagesPhotoRequest(u.api, u.actor).
+		Photo(uploadResponse.Photo).
+		Server(uploadResponse.Server).
+		Hash(uploadResponse.Hash).
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(saveResponse.Response) == 0 {
+		return nil, internalErrors.Error("Request.Uploader.UploadMessagePhoto()", "empty response of photos.saveMessagesPhoto")
+	}
+
+	return &saveResponse.Response[0], nil
+}
+
+// UploadDoc uploads the document to the documents of the community, groupID <= 0 uploads to the documents of the user.
+// The name of the file is used as the title of the document.
+//
+//	Doc: https://dev.vk.com/ru/api/upload/document-in-profile
+func (u *Uploader) UploadDoc(ctx context.Context, groupID int, file *objects.UploadFile) (*objects.Document, error) {
+	if err := checkUploadFile("Request.Uploader.UploadDoc()", file); err != nil {
+		return nil, err
+	}
+
+	server := NewDocsGetUploadServerRequest(u.api, u.actor)
+	if groupID > 0 {
+		server.GroupID(groupID)
+	}
+
+	serverResponse, err := server.Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	saveResponse, err := u.uploadDoc(ctx, serverResponse.Response.UploadURL, file)
+	if err != nil {
+		return nil, err
+	}
+
+	return &saveResponse.Response.Doc, nil
+}
+
+// UploadMessageDoc uploads the document for the message to the peer.
+// The name of the file is used as the title of the document.
+//
+//	Doc: https://dev.vk.com/ru/api/upload/document-in-profile
+func (u *Uploader) UploadMessageDoc(ctx context.Context, peerID int, file *objects.UploadFile) (*objects.Document, error) {
+	if err := checkUploadFile("Request.Uploader.UploadMessageDoc()", file); err != nil {
+		return nil, err
+	}
+
+	serverResponse, err := NewDocsGetMessagesUploadServerRequest(u.api, u.actor).
+		Type("doc").
+		PeerID(peerID).
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	saveResponse, err := u.uploadDoc(ctx, serverResponse.Response.UploadURL, file)
+	if err != nil {
+		return nil, err
+	}
+
+	return &saveResponse.Response.Doc, nil
+}
+
+// UploadAudioMessage uploads the voice message for the peer, the file is OGG or OPUS.
+//
+//	Doc: https://dev.vk.com/ru/api/upload/audio-record
+func (u *Uploader) UploadAudioMessage(ctx context.Context, peerID int, file *objects.UploadFile) (*objects.MessagesAudioMessage, error) {
+	if err := checkUploadFile("Request.Uploader.UploadAudioMessage()", file); err != nil {
+		return nil, err
+	}
+
+	if err := UploadConstraintsAudioMessage.Validate(file); err != nil {
+		return nil, err
+	}
+
+	serverResponse, err := NewDocsGetMessagesUploadServerRequest(u.api, u.actor).
+		Type("audio_message").
+		PeerID(peerID).
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	saveResponse, err := u.uploadDoc(ctx, serverResponse.Response.UploadURL, file)
+	if err != nil {
+		return nil, err
+	}
+
+	return &saveResponse.Response.AudioMessage, nil
+}
+
+// UploadPhotoStory uploads the photo story and publishes it in the news on behalf of the community,
+// groupID <= 0 publishes the story of the user.
+//
+//	Doc: https://dev.vk.com/ru/api/upload/story-in-profile
+func (u *Uploader) UploadPhotoStory(ctx context.Context, groupID int, file *objects.UploadFile) (*objects.StoriesStory, error) {
+	if err := checkUploadFile("Request.Uploader.UploadPhotoStory()", file); err != nil {
+		return nil, err
+	}
+
+	server := NewStoriesGetPhotoUploadServerRequest(u.api, u.actor).AddToNews(true)
+	if groupID > 0 {
+		server.GroupID(groupID)
+	}
+
+	serverResponse, err := server.Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	upload := NewUploadPhotoStoriesRequest(u.api, u.actor)
+	upload.SetURL(serverResponse.Response.UploadURL)
+
+	uploadResponse, err := upload.Exec(ctx, uploadFileField(file, "file"))
+	if err != nil {
+		return nil, err
+	}
+
+	return u.saveStory(ctx, uploadResponse.Response.UploadResult)
+}
+
+// UploadVideoStory uploads the video story and publishes it in the news on behalf of the community,
+// groupID <= 0 publishes the story of the user.
+//
+//	Doc: https://dev.vk.com/ru/api/upload/story-in-profile
+func (u *Uploader) UploadVideoStory(ctx context.Context, groupID int, file *objects.UploadFile) (*objects.StoriesStory, error) {
+	if err := checkUploadFile("Request.Uploader.UploadVideoStory()", file); err != nil {
+		return nil, err
+	}
+
+	server := NewStoriesGetVideoUploadServerRequest(u.api, u.actor).AddToNews(true)
+	if groupID > 0 {
+		server.GroupID(groupID)
+	}
+
+	serverResponse, err := server.Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	upload := NewUploadVideoStoriesRequest(u.api, u.actor)
+	upload.SetURL(serverResponse.Response.UploadURL)
+
+	uploadResponse, err := upload.Exec(ctx, uploadFileField(file, "video_file"))
+	if err != nil {
+		return nil, err
+	}
+
+	return u.saveStory(ctx, uploadResponse.Response.UploadResult)
+}
+
+// UploadMarketPhoto uploads the photo of the product of the community,
+// the ID of the photo is used as main_photo_id and photo_ids of market.add and market.edit.
+//
+//	Doc: https://dev.vk.com/ru/api/upload/photo-in-market
+func (u *Uploader) UploadMarketPhoto(ctx context.Context, groupID int, file *objects.UploadFile) (*objects.Photo, error) {
+	if err := checkUploadFile("Request.Uploader.UploadMarketPhoto()", file); err != nil {
+		return nil, err
+	}
+
+	serverResponse, err := NewMarketGetProductPhotoUploadServerRequest(u.api, u.actor).
+		GroupID(groupID).
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	upload := NewUploadPhotoMarketRequest(u.api, u.actor)
+	upload.SetURL(serverResponse.Response.UploadURL)
+
+	uploadData := uploadFileField(file, "file")
+	if err = upload.Validate(uploadData); err != nil {
+		return nil, err
+	}
+
+	// market.saveProductPhoto takes the whole response of the upload server
+	var uploadResponse json.RawMessage
+	if err = uploadFile(&upload.BaseRequest, ctx, uploadData, &uploadResponse); err != nil {
+		return nil, err
+	}
+
+	saveResponse, err := NewMarketSaveProductPhotoRequest(u.api, u.actor).
+		UploadResponse(string(uploadResponse)).
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	photo := saveResponse.Response.Photo
+	if photo.ID == 0 {
+		photo.ID = saveResponse.Response.ID
+		photo.OwnerID = -groupID
+	}
+
+	return &photo, nil
+}
+
+func (u *Uploader) uploadDoc(ctx context.Context, uploadURL string, file *objects.UploadFile) (saveResponse response.DocsSaveResponse, err error) {
+	upload := NewUploadDocumentRequest(u.api, u.actor)
+	upload.SetURL(uploadURL)
+
+	uploadResponse, err := upload.Exec(ctx, uploadFileField(file, "file"))
+	if err != nil {
+		return
+	}
+
+	return NewDocsSaveRequest(u.api, u.actor).
+		File(uploadResponse.File).
+		Title(file.Name).
+		Exec(ctx)
+}
+
+func (u *Uploader) saveStory(ctx context.Context, uploadResult string) (*objects.StoriesStory, error) {
+	saveResponse, err := NewStoriesSaveRequest(u.api, u.actor).
+		UploadResults(uploadResult).
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(saveResponse.Response.Items) == 0 {
+		return nil, internalErrors.Error("Request.Uploader.saveStory()", "empty response of stories.save")
+	}
+
+	return &saveResponse.Response.Items[0], nil
+}
+
+// checkUploadFile returns the error for the undefined file before the upload server is requested
+func checkUploadFile(from string, file *objects.UploadFile) error {
+	if file == nil || file.Data == nil {
+		return internalErrors.Error(from, "file is undefined")
+	}
+
+	return nil
+}
+
+// uploadFileField returns the copy of the file with the form field expected by the upload server
+func uploadFileField(file *objects.UploadFile, fieldName string) *objects.UploadFile {
+	upload := *file
+	upload.FieldName = fieldName
+	return &upload
+}
@@ -0,0 +1,129 @@
+package request_test
+
+import (
+	"context"
+	"encoding/json"
+	"go-vk-sdk/objects"
+	"go-vk-sdk/request"
+	"go-vk-sdk/vktest"
+	"strings"
+	"testing"
+)
+
+func TestUploaderWallPhoto(t *testing.T) {
+	s, a, g := newServer(t)
+
+	s.Respond("photos.getWallUploadServer", map[string]interface{}{"upload_url": s.UploadURL("wall")})
+	s.Handle("photos.saveWallPhoto", func(call *vktest.Call) (interface{}, error) {
+		return []map[string]interface{}{{"id": 5, "owner_id": -1}}, nil
+	})
+
+	photo, err := request.NewUploader(a, g).UploadWallPhoto(context.Background(), 1, pngFile(t, 300, 200))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if photo.ID != 5 || photo.OwnerID != -1 {
+		t.Errorf("unexpected photo %+v", photo)
+	}
+
+	if call := s.LastCall("photos.getWallUploadServer"); call == nil || call.Get("group_id") != "1" {
+		t.Errorf("unexpected get upload server call %+v", call)
+	}
+
+	upload := s.LastCall("wall")
+	if upload == nil || len(upload.Files) != 1 || upload.Files[0].Field != "photo" || len(upload.Files[0].Data) == 0 {
+		t.Fatalf("unexpected upload %+v", upload)
+	}
+
+	// the save call receives the fields of the upload response
+	save := s.LastCall("photos.saveWallPhoto")
+	if save.Get("photo") != "photo.png" || save.Get("server") != "1" || save.Get("hash") != "vktest" || save.Get("group_id") != "1" {
+		t.Errorf("unexpected save parameters %v", save.Parameters)
+	}
+}
+
+func TestUploaderDoc(t *testing.T) {
+	s, a, g := newServer(t)
+
+	s.Respond("docs.getUploadServer", map[string]interface{}{"upload_url": s.UploadURL("doc")})
+	s.Handle("docs.save", func(call *vktest.Call) (interface{}, error) {
+		return map[string]interface{}{"type": "doc", "doc": map[string]interface{}{"id": 7, "owner_id": 1, "title": call.Get("title")}}, nil
+	})
+
+	doc, err := request.NewUploader(a, g).UploadDoc(context.Background(), 0, objects.NewUploadFile("report.txt", strings.NewReader("report")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.ID != 7 || doc.Title != "report.txt" {
+		t.Errorf("unexpected document %+v", doc)
+	}
+
+	if call := s.LastCall("docs.getUploadServer"); call == nil || call.Get("group_id") != "" {
+		t.Errorf("unexpected get upload server call %+v", call)
+	}
+
+	upload := s.LastCall("doc")
+	if upload == nil || len(upload.Files) != 1 || upload.Files[0].Field != "file" || string(upload.Files[0].Data) != "report" {
+		t.Fatalf("unexpected upload %+v", upload)
+	}
+
+	if file := s.LastCall("docs.save").Get("file"); file != "report.txt" {
+		t.Errorf("got file %q", file)
+	}
+}
+
+func TestUploaderMarketPhoto(t *testing.T) {
+	s, a, g := newServer(t)
+
+	s.Respond("market.getProductPhotoUploadServer", map[string]interface{}{"upload_url": s.UploadURL("market")})
+	s.Handle("market.saveProductPhoto", func(call *vktest.Call) (interface{}, error) {
+		return map[string]interface{}{"photo_id": 9}, nil
+	})
+
+	photo, err := request.NewUploader(a, g).UploadMarketPhoto(context.Background(), 3, pngFile(t, 400, 400))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the photo without the object in the response belongs to the community
+	if photo.ID != 9 || photo.OwnerID != -3 {
+		t.Errorf("unexpected photo %+v", photo)
+	}
+
+	if call := s.LastCall("market.getProductPhotoUploadServer"); call == nil || call.Get("group_id") != "3" {
+		t.Errorf("unexpected get upload server call %+v", call)
+	}
+
+	// market.saveProductPhoto receives the whole response of the upload server
+	var uploadResponse map[string]interface{}
+	if err = json.Unmarshal([]byte(s.LastCall("market.saveProductPhoto").Get("upload_response")), &uploadResponse); err != nil {
+		t.Fatal(err)
+	}
+
+	if uploadResponse["hash"] != "vktest" || uploadResponse["file"] != "photo.png" {
+		t.Errorf("unexpected upload_response %v", uploadResponse)
+	}
+}
+
+func TestUploaderNilFile(t *testing.T) {
+	s, a, g := newServer(t)
+	u := request.NewUploader(a, g)
+
+	if _, err := u.UploadWallPhoto(context.Background(), 1, nil); err == nil {
+		t.Error("nil photo is accepted")
+	}
+
+	if _, err := u.UploadDoc(context.Background(), 1, nil); err == nil {
+		t.Error("nil document is accepted")
+	}
+
+	if _, err := u.UploadMarketPhoto(context.Background(), 1, &objects.UploadFile{Name: "photo.png"}); err == nil {
+		t.Error("file without data is accepted")
+	}
+
+	if calls := len(s.Calls()); calls != 0 {
+		t.Errorf("got %d calls for the undefined files", calls)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"go-vk-sdk/actor"
 	"go-vk-sdk/api"
+	"go-vk-sdk/constants"
 	"go-vk-sdk/response"
 )
 
@@ -158,3 +159,9 @@ func (r *UtilsResolveScreenNameRequest) Exec(ctx context.Context) (response resp
 	err = r.PostUnmarshal(ctx, &response)
 	return
 }
+
+// ScreenName Screen name of the user, the community or the application, for example durov or club1
+func (r *UtilsResolveScreenNameRequest) ScreenName(screenName string) *UtilsResolveScreenNameRequest {
+	r.parameters.Set(constants.ParameterNameScreenName, screenName)
+	return r
+}
@@ -0,0 +1,370 @@
+package request
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"go-vk-sdk/actor"
+	"go-vk-sdk/api"
+	"go-vk-sdk/constants"
+	internalErrors "go-vk-sdk/errors"
+	"go-vk-sdk/objects"
+	"go-vk-sdk/response"
+	"io"
+	"math/rand/v2"
+	"mime"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Doc: https://dev.vk.com/ru/api/upload/video-in-profile
+
+const VideoUploadChunkSize = 5 << 20 // default size of the chunk of the video in bytes
+
+// VideoUploadState Resume state of the chunked video upload, it is saved after every chunk received by the upload server
+type VideoUploadState struct {
+	UploadURL string `json:"upload_url"`
+	SessionID string `json:"session_id"`
+	Size      int64  `json:"size"`   // size of the video in bytes
+	Offset    int64  `json:"offset"` // number of bytes received by the upload server
+}
+
+// VideoUploadProgress Progress of the chunked video upload
+type VideoUploadProgress struct {
+	Uploaded int64
+	Total    int64
+}
+
+// VideoUploadStateStore Persists the resume state of the chunked video upload,
+// so the interrupted upload continues from the last received chunk.
+//
+//	Load returns nil without error if there is no saved state.
+type VideoUploadStateStore interface {
+	Load() (*VideoUploadState, error)
+	Save(state *VideoUploadState) error
+	Clear() error
+}
+
+// VideoUploadStateFile Stores the resume state as JSON in the file, the file is replaced atomically on every save
+type VideoUploadStateFile struct {
+	path string
+}
+
+func NewVideoUploadStateFile(path string) *VideoUploadStateFile {
+	return &VideoUploadStateFile{path: path}
+}
+
+func (f *VideoUploadStateFile) Load() (*VideoUploadState, error) {
+	data, err := os.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, internalErrors.Error("Request.VideoUploadStateFile.Load()", "error read state "+err.Error())
+	}
+
+	state := &VideoUploadState{}
+	if err = json.Unmarshal(data, state); err != nil {
+		return nil, internalErrors.Error("Request.VideoUploadStateFile.Load()", "error decoding state "+err.Error())
+	}
+
+	return state, nil
+}
+
+func (f *VideoUploadStateFile) Save(state *VideoUploadState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return internalErrors.Error("Request.VideoUploadStateFile.Save()", "error encoding state "+err.Error())
+	}
+
+	tmp := f.path + ".tmp"
+	if err = os.WriteFile(tmp, data, 0o600); err != nil {
+		return internalErrors.Error("Request.VideoUploadStateFile.Save()", "error write state "+err.Error())
+	}
+
+	if err = os.Rename(tmp, f.path); err != nil {
+		return internalErrors.Error("Request.VideoUploadStateFile.Save()", "error write state "+err.Error())
+	}
+
+	return nil
+}
+
+func (f *VideoUploadStateFile) Clear() error {
+	if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+		return internalErrors.Error("Request.VideoUploadStateFile.Clear()", "error remove state "+err.Error())
+	}
+
+	return nil
+}
+
+// UploadVideoChunkedRequest
+//
+//	Uploads the video by chunks with the headers Content-Range and Session-ID, every chunk is a separate request,
+//	so a failed chunk is retried alone and the interrupted upload continues from the last received chunk.
+//	The upload server responds 201 with the received ranges to every chunk except the last one
+//	and 200 with the video to the last chunk.
+//	The data of the file must implement io.Seeker, the upload starts at its current offset.
+//	The resume state is loaded from the store, if it is set, and its upload URL is used when the URL is not set,
+//	so the upload is continued without a new call of video.save. The saved offset is not trusted,
+//	the received range is queried from the upload server before the upload is continued.
+//	Doc: https://dev.vk.com/ru/api/upload/video-in-profile
+type UploadVideoChunkedRequest struct {
+	BaseRequest
+	chunkSize int64
+	store     VideoUploadStateStore
+	progress  func(VideoUploadProgress)
+}
+
+func NewUploadVideoChunkedRequest(a *api.API, actor actor.Actor) *UploadVideoChunkedRequest {
+	return &UploadVideoChunkedRequest{
+		BaseRequest: *NewUploadBaseRequest(a, actor),
+		chunkSize:   VideoUploadChunkSize,
+	}
+}
+
+// ChunkSize size > 0, size of the chunk in bytes
+func (r *UploadVideoChunkedRequest) ChunkSize(size int64) *UploadVideoChunkedRequest {
+	if size > 0 {
+		r.chunkSize = size
+	}
+	return r
+}
+
+// State sets the store of the resume state, the state is removed from the store when the upload is finished
+func (r *UploadVideoChunkedRequest) State(store VideoUploadStateStore) *UploadVideoChunkedRequest {
+	r.store = store
+	return r
+}
+
+// Progress sets the function called after every chunk received by the upload server
+func (r *UploadVideoChunkedRequest) Progress(fn func(VideoUploadProgress)) *UploadVideoChunkedRequest {
+	r.progress = fn
+	return r
+}
+
+// ProgressChan sends the progress to the channel without blocking, the progress is dropped if the channel is full
+func (r *UploadVideoChunkedRequest) ProgressChan(ch chan<- VideoUploadProgress) *UploadVideoChunkedRequest {
+	return r.Progress(func(p VideoUploadProgress) {
+		select {
+		case ch <- p:
+		default:
+		}
+	})
+}
+
+// Exec UploadFile.Data implements io.Seeker
+func (r *UploadVideoChunkedRequest) Exec(ctx context.Context, file *objects.UploadFile) (response response.PhotosUploadVideoResponse, err error) {
+	data, ok := file.Data.(io.ReadSeeker)
+	if !ok {
+		return response, internalErrors.Error("Request.UploadVideoChunkedRequest.Exec()", "data of the video must implement io.Seeker")
+	}
+
+	start, err := data.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return response, internalErrors.Error("Request.UploadVideoChunkedRequest.Exec()", "error seek video "+err.Error())
+	}
+
+	end, err := data.Seek(0, io.SeekEnd)
+	if err != nil {
+		return response, internalErrors.Error("Request.UploadVideoChunkedRequest.Exec()", "error seek video "+err.Error())
+	}
+
+	if end <= start {
+		return response, internalErrors.Error("Request.UploadVideoChunkedRequest.Exec()", "video is empty")
+	}
+
+	state, resumed, err := r.resumeState(end - start)
+	if err != nil {
+		return response, err
+	}
+
+	r.SetURL(state.UploadURL)
+	r.SetContentType(constants.ContentTypeOctetStream)
+	r.SetHeader("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.Name}))
+	r.SetHeader("Session-ID", state.SessionID)
+
+	if resumed {
+		var done bool
+		if done, err = r.queryRange(ctx, state, &response); err != nil {
+			return
+		}
+
+		if done {
+			return response, r.finish(state)
+		}
+	}
+
+	r.reportProgress(state)
+
+	buf := make([]byte, min(r.chunkSize, max(state.Size, 1)))
+
+	for {
+		if state.Offset >= state.Size {
+			return response, &internalErrors.UploadAPIError{Description: "upload server received the whole video but did not return it"}
+		}
+
+		n := min(r.chunkSize, state.Size-state.Offset)
+
+		if _, err = data.Seek(start+state.Offset, io.SeekStart); err != nil {
+			return response, internalErrors.Error("Request.UploadVideoChunkedRequest.Exec()", "error seek video "+err.Error())
+		}
+
+		if _, err = io.ReadFull(data, buf[:n]); err != nil {
+			return response, internalErrors.Error("Request.UploadVideoChunkedRequest.Exec()", "error read video "+err.Error())
+		}
+
+		r.SetHeader("Content-Range", "bytes "+strconv.FormatInt(state.Offset, 10)+"-"+
+			strconv.FormatInt(state.Offset+n-1, 10)+"/"+strconv.FormatInt(state.Size, 10))
+
+		var done bool
+		done, err = r.uploadChunk(ctx, bytes.NewReader(buf[:n]), state, &response)
+		if err != nil || done {
+			break
+		}
+
+		if r.store != nil {
+			if err = r.store.Save(state); err != nil {
+				return
+			}
+		}
+
+		r.reportProgress(state)
+	}
+
+	if err != nil {
+		return
+	}
+
+	return response, r.finish(state)
+}
+
+// finish reports the whole video as uploaded and removes the resume state
+func (r *UploadVideoChunkedRequest) finish(state *VideoUploadState) error {
+	state.Offset = state.Size
+	r.reportProgress(state)
+
+	if r.store != nil {
+		return r.store.Clear()
+	}
+
+	return nil
+}
+
+// resumeState returns the saved state if it belongs to the same upload, otherwise the state of the new upload,
+// resumed is true for the saved state
+func (r *UploadVideoChunkedRequest) resumeState(size int64) (state *VideoUploadState, resumed bool, err error) {
+	url := r.GetURL()
+
+	if r.store != nil {
+		state, err = r.store.Load()
+		if err != nil {
+			return nil, false, err
+		}
+
+		if state != nil && state.Size == size && (url == "" || url == state.UploadURL) {
+			return state, true, nil
+		}
+	}
+
+	if url == "" {
+		return nil, false, internalErrors.Error("Request.UploadVideoChunkedRequest.Exec()", "upload URL is undefined")
+	}
+
+	return &VideoUploadState{
+		UploadURL: url,
+		SessionID: strconv.FormatUint(rand.Uint64(), 16),
+		Size:      size,
+	}, false, nil
+}
+
+// queryRange sends the empty request with the range bytes */size, the upload server responds with the received ranges
+// of the session, the offset of the state is moved to their end. The server may have lost the chunks received
+// after the state was saved or received the chunks sent before the failure, so the saved offset is not used.
+// done is true when the upload server responded with the video
+func (r *UploadVideoChunkedRequest) queryRange(ctx context.Context, state *VideoUploadState, response interface{}) (done bool, err error) {
+	r.SetHeader("Content-Range", "bytes */"+strconv.FormatInt(state.Size, 10))
+
+	done, received, err := r.postRange(ctx, http.NoBody, response)
+	if err != nil || done {
+		return done, err
+	}
+
+	state.Offset = received
+
+	return false, nil
+}
+
+// uploadChunk sends the chunk and moves the offset of the state to the end of the received bytes,
+// done is true when the upload server responded with the video
+func (r *UploadVideoChunkedRequest) uploadChunk(ctx context.Context, chunk io.Reader, state *VideoUploadState, response interface{}) (done bool, err error) {
+	done, received, err := r.postRange(ctx, chunk, response)
+	if err != nil || done {
+		return done, err
+	}
+
+	if received <= state.Offset {
+		return false, &internalErrors.UploadAPIError{Code: http.StatusCreated, Description: "chunk is not received, received " + strconv.FormatInt(received, 10) + " bytes"}
+	}
+
+	state.Offset = received
+
+	return false, nil
+}
+
+// postRange sends the data with the headers of the request, it returns the number of bytes received by the upload server
+// from the beginning of the file, done is true when the upload server responded with the video
+func (r *UploadVideoChunkedRequest) postRange(ctx context.Context, data io.Reader, response interface{}) (done bool, received int64, err error) {
+	resp, err := r.PostData(ctx, data)
+	if err != nil {
+		return false, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return true, 0, decodeUploadResponse(resp, response)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, 0, internalErrors.ErrorLog("Request.UploadVideoChunkedRequest.Exec()", "Error read upload response: "+err.Error())
+	}
+
+	received, ok := receivedBytes(string(body))
+	if !ok {
+		return false, 0, &internalErrors.UploadAPIError{Code: resp.StatusCode, Description: "invalid received range " + string(body)}
+	}
+
+	return false, received, nil
+}
+
+func (r *UploadVideoChunkedRequest) reportProgress(state *VideoUploadState) {
+	if r.progress != nil {
+		r.progress(VideoUploadProgress{Uploaded: state.Offset, Total: state.Size})
+	}
+}
+
+// receivedBytes returns the number of bytes received from the beginning of the file
+// from the ranges of the response to the chunk, for example "0-1048575/5242880" or "0-1023,2048-4095/5242880"
+func receivedBytes(ranges string) (int64, bool) {
+	ranges, _, _ = strings.Cut(strings.TrimSpace(ranges), "/")
+
+	first, _, _ := strings.Cut(ranges, ",")
+
+	from, to, ok := strings.Cut(first, "-")
+	if !ok {
+		return 0, ranges == ""
+	}
+
+	end, err := strconv.ParseInt(to, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	if from != "0" {
+		return 0, true
+	}
+
+	return end + 1, true
+}
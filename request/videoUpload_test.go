@@ -0,0 +1,121 @@
+package request_test
+
+import (
+	"bytes"
+	"context"
+	"go-vk-sdk/actor"
+	"go-vk-sdk/api"
+	"go-vk-sdk/objects"
+	"go-vk-sdk/request"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type memoryStateStore struct {
+	state *request.VideoUploadState
+}
+
+func (s *memoryStateStore) Load() (*request.VideoUploadState, error) {
+	if s.state == nil {
+		return nil, nil
+	}
+
+	state := *s.state
+	return &state, nil
+}
+
+func (s *memoryStateStore) Save(state *request.VideoUploadState) error {
+	saved := *state
+	s.state = &saved
+	return nil
+}
+
+func (s *memoryStateStore) Clear() error {
+	s.state = nil
+	return nil
+}
+
+// chunkServer accepts the chunks of the session in order and answers the range query bytes */size
+type chunkServer struct {
+	mtx      sync.Mutex
+	received []byte
+	ranges   []string
+}
+
+func (c *chunkServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	contentRange := strings.TrimPrefix(r.Header.Get("Content-Range"), "bytes ")
+	c.ranges = append(c.ranges, contentRange)
+
+	span, total, _ := strings.Cut(contentRange, "/")
+	size, _ := strconv.Atoi(total)
+
+	if span != "*" {
+		from, _, _ := strings.Cut(span, "-")
+		offset, _ := strconv.Atoi(from)
+		data, _ := io.ReadAll(r.Body)
+
+		// the chunk after a gap is dropped
+		if offset <= len(c.received) {
+			c.received = append(c.received[:offset], data...)
+		}
+	}
+
+	if len(c.received) == size {
+		_, _ = io.WriteString(w, `{"size":`+total+`,"video_id":7,"owner_id":1,"video_hash":"hash"}`)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if len(c.received) > 0 {
+		_, _ = io.WriteString(w, "0-"+strconv.Itoa(len(c.received)-1)+"/"+total)
+	}
+}
+
+func TestUploadVideoChunkedResume(t *testing.T) {
+	chunks := &chunkServer{received: []byte("0123")}
+	server := httptest.NewServer(chunks)
+	defer server.Close()
+
+	video := []byte("0123456789")
+
+	// the state was saved after 8 bytes, but the upload server kept only 4 of them
+	store := &memoryStateStore{state: &request.VideoUploadState{
+		UploadURL: server.URL,
+		SessionID: "session",
+		Size:      int64(len(video)),
+		Offset:    8,
+	}}
+
+	resp, err := request.NewUploadVideoChunkedRequest(api.NewAPI(), &actor.User{ID: 1, AccessToken: "token"}).
+		ChunkSize(4).
+		State(store).
+		Exec(context.Background(), objects.NewUploadFile("video.mp4", bytes.NewReader(video)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.VideoID != 7 || resp.Size != len(video) {
+		t.Errorf("unexpected response %+v", resp)
+	}
+
+	if !bytes.Equal(chunks.received, video) {
+		t.Errorf("server received %q, want %q", chunks.received, video)
+	}
+
+	want := []string{"*/10", "4-7/10", "8-9/10"}
+	if strings.Join(chunks.ranges, " ") != strings.Join(want, " ") {
+		t.Errorf("got ranges %q, want %q", chunks.ranges, want)
+	}
+
+	if store.state != nil {
+		t.Error("the state is not cleared after the upload")
+	}
+}
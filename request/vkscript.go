@@ -0,0 +1,435 @@
+package request
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-vk-sdk/constants"
+	internalErrors "go-vk-sdk/errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Doc: https://dev.vk.com/ru/method/execute
+
+var vkscriptIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+var vkscriptMethod = regexp.MustCompile(`^[A-Za-z]+\.[A-Za-z]+$`)
+
+var vkscriptReserved = map[string]bool{
+	"var": true, "return": true, "if": true, "else": true, "while": true, "API": true, "Args": true,
+	"true": true, "false": true, "null": true, "delete": true, "in": true,
+}
+
+// VKScriptExpr Expression of the VKScript program.
+//
+//	Values of any other type passed to the builder are encoded as literals, strings are always escaped,
+//	so user input never becomes code. Errors of invalid names and values are reported by VKScript.Build.
+type VKScriptExpr struct {
+	code string
+	err  error
+}
+
+func (e VKScriptExpr) String() string {
+	return e.code
+}
+
+func (e VKScriptExpr) Err() error {
+	return e.err
+}
+
+// VKScriptRaw code inserted as is, it must never contain user input
+func VKScriptRaw(code string) VKScriptExpr {
+	return VKScriptExpr{code: code}
+}
+
+// VKScriptLiteral encodes strings, numbers, booleans, nil, slices, maps and structs as a VKScript literal
+func VKScriptLiteral(v interface{}) VKScriptExpr {
+	code, err := vkscriptLiteral(v)
+	if err != nil {
+		return VKScriptExpr{err: internalErrors.Error("Request.VKScriptLiteral()", "error encoding literal "+err.Error())}
+	}
+
+	return VKScriptExpr{code: code}
+}
+
+// VKScriptVar reference to the variable declared by VKScript.Var
+func VKScriptVar(name string) VKScriptExpr {
+	if err := vkscriptName(name); err != nil {
+		return VKScriptExpr{err: err}
+	}
+
+	return VKScriptExpr{code: name}
+}
+
+// VKScriptArg argument of the stored procedure, Args.name
+func VKScriptArg(name string) VKScriptExpr {
+	if !vkscriptIdentifier.MatchString(name) {
+		return VKScriptExpr{err: internalErrors.Error("Request.VKScriptArg()", "invalid argument name "+strconv.Quote(name))}
+	}
+
+	return VKScriptExpr{code: "Args." + name}
+}
+
+// VKScriptArray array literal of the values
+func VKScriptArray(values ...interface{}) VKScriptExpr {
+	items := make([]string, 0, len(values))
+	for _, value := range values {
+		item := vkscriptValue(value)
+		if item.err != nil {
+			return item
+		}
+		items = append(items, item.code)
+	}
+
+	return VKScriptExpr{code: "[" + strings.Join(items, ",") + "]"}
+}
+
+// VKScriptCall call of the api method, API.method({args}). Values of the arguments may be expressions
+func VKScriptCall(method string, args map[string]interface{}) VKScriptExpr {
+	if !vkscriptMethod.MatchString(method) {
+		return VKScriptExpr{err: internalErrors.Error("Request.VKScriptCall()", "invalid method name "+strconv.Quote(method))}
+	}
+
+	object := vkscriptValue(args)
+	if object.err != nil {
+		return object
+	}
+
+	return VKScriptExpr{code: "API." + method + "(" + object.code + ")"}
+}
+
+// VKScriptRequestCall call of the api method of the request with its parameters.
+// The version and language are passed by the execute request itself
+func VKScriptRequestCall(req Request) VKScriptExpr {
+	args := make(map[string]interface{})
+	for key, value := range *req.GetParameters().BuildURLValues() {
+		if key != constants.ParameterNameVersion && key != constants.ParameterNameLang {
+			args[key] = strings.Join(value, ",")
+		}
+	}
+
+	return VKScriptCall(req.GetMethod(), args)
+}
+
+// Field access to the field, e.name
+func (e VKScriptExpr) Field(name string) VKScriptExpr {
+	if e.err != nil {
+		return e
+	}
+
+	if !vkscriptIdentifier.MatchString(name) {
+		return VKScriptExpr{err: internalErrors.Error("Request.VKScriptExpr.Field()", "invalid field name "+strconv.Quote(name))}
+	}
+
+	return VKScriptExpr{code: e.code + "." + name}
+}
+
+// Index access to the element, e[index]
+func (e VKScriptExpr) Index(index interface{}) VKScriptExpr {
+	return e.binary("[", index, "]")
+}
+
+// Length e.length
+func (e VKScriptExpr) Length() VKScriptExpr {
+	return e.Field("length")
+}
+
+// Push e.push(value)
+func (e VKScriptExpr) Push(value interface{}) VKScriptExpr {
+	return e.binary(".push(", value, ")")
+}
+
+// Pop e.pop()
+func (e VKScriptExpr) Pop() VKScriptExpr {
+	return e.binary(".pop(", VKScriptRaw(""), ")")
+}
+
+// Add (e + value), numbers are added and strings are concatenated
+func (e VKScriptExpr) Add(value interface{}) VKScriptExpr {
+	return e.operator("+", value)
+}
+
+func (e VKScriptExpr) Sub(value interface{}) VKScriptExpr {
+	return e.operator("-", value)
+}
+
+func (e VKScriptExpr) Mul(value interface{}) VKScriptExpr {
+	return e.operator("*", value)
+}
+
+func (e VKScriptExpr) Div(value interface{}) VKScriptExpr {
+	return e.operator("/", value)
+}
+
+func (e VKScriptExpr) Eq(value interface{}) VKScriptExpr {
+	return e.operator("==", value)
+}
+
+func (e VKScriptExpr) Ne(value interface{}) VKScriptExpr {
+	return e.operator("!=", value)
+}
+
+func (e VKScriptExpr) Lt(value interface{}) VKScriptExpr {
+	return e.operator("<", value)
+}
+
+func (e VKScriptExpr) Le(value interface{}) VKScriptExpr {
+	return e.operator("<=", value)
+}
+
+func (e VKScriptExpr) Gt(value interface{}) VKScriptExpr {
+	return e.operator(">", value)
+}
+
+func (e VKScriptExpr) Ge(value interface{}) VKScriptExpr {
+	return e.operator(">=", value)
+}
+
+func (e VKScriptExpr) And(value interface{}) VKScriptExpr {
+	return e.operator("&&", value)
+}
+
+func (e VKScriptExpr) Or(value interface{}) VKScriptExpr {
+	return e.operator("||", value)
+}
+
+// Not (!e)
+func (e VKScriptExpr) Not() VKScriptExpr {
+	if e.err != nil {
+		return e
+	}
+
+	return VKScriptExpr{code: "(!" + e.code + ")"}
+}
+
+func (e VKScriptExpr) operator(op string, value interface{}) VKScriptExpr {
+	expr := e.binary(" "+op+" ", value, ")")
+	if expr.err != nil {
+		return expr
+	}
+
+	return VKScriptExpr{code: "(" + expr.code}
+}
+
+func (e VKScriptExpr) binary(op string, value interface{}, suffix string) VKScriptExpr {
+	if e.err != nil {
+		return e
+	}
+
+	right := vkscriptValue(value)
+	if right.err != nil {
+		return right
+	}
+
+	return VKScriptExpr{code: e.code + op + right.code + suffix}
+}
+
+// VKScript Builder of the VKScript program for ExecuteRequest.Script
+//
+//	s := NewVKScript()
+//	s.Var("users", VKScriptCall("users.get", map[string]interface{}{"user_ids": input}))
+//	s.Return(VKScriptVar("users").Index(0).Field("first_name"))
+type VKScript struct {
+	statements []string
+	err        error
+}
+
+func NewVKScript() *VKScript {
+	return &VKScript{}
+}
+
+// Var declares the variable, var name = value;
+func (s *VKScript) Var(name string, value interface{}) *VKScript {
+	if err := vkscriptName(name); err != nil {
+		return s.fail(err)
+	}
+
+	return s.statement("var "+name+" = ", value, ";")
+}
+
+// Set assigns the value to the variable or the element, target = value;
+func (s *VKScript) Set(target VKScriptExpr, value interface{}) *VKScript {
+	if target.err != nil {
+		return s.fail(target.err)
+	}
+
+	return s.statement(target.code+" = ", value, ";")
+}
+
+// Do executes the expression as a statement, for example a call of the api method without using its result
+func (s *VKScript) Do(expr VKScriptExpr) *VKScript {
+	return s.statement("", expr, ";")
+}
+
+// If if (cond) { then }
+func (s *VKScript) If(cond VKScriptExpr, then func(s *VKScript)) *VKScript {
+	return s.block("if (", cond, then, nil)
+}
+
+// IfElse if (cond) { then } else { otherwise }
+func (s *VKScript) IfElse(cond VKScriptExpr, then func(s *VKScript), otherwise func(s *VKScript)) *VKScript {
+	return s.block("if (", cond, then, otherwise)
+}
+
+// While while (cond) { body }. VKScript stops the program after 1000 operations of the loops
+func (s *VKScript) While(cond VKScriptExpr, body func(s *VKScript)) *VKScript {
+	return s.block("while (", cond, body, nil)
+}
+
+// Return return value;
+func (s *VKScript) Return(value interface{}) *VKScript {
+	return s.statement("return ", value, ";")
+}
+
+// Build returns the code of the program or the first error of the builder and its expressions
+func (s *VKScript) Build() (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+
+	if len(s.statements) == 0 {
+		return "", internalErrors.Error("Request.VKScript.Build()", "empty program")
+	}
+
+	return strings.Join(s.statements, "\n"), nil
+}
+
+func (s *VKScript) statement(prefix string, value interface{}, suffix string) *VKScript {
+	if s.err != nil {
+		return s
+	}
+
+	expr := vkscriptValue(value)
+	if expr.err != nil {
+		return s.fail(expr.err)
+	}
+
+	s.statements = append(s.statements, prefix+expr.code+suffix)
+
+	return s
+}
+
+func (s *VKScript) block(prefix string, cond VKScriptExpr, body func(s *VKScript), otherwise func(s *VKScript)) *VKScript {
+	if s.err != nil {
+		return s
+	}
+
+	if cond.err != nil {
+		return s.fail(cond.err)
+	}
+
+	code, err := vkscriptBody(body)
+	if err != nil {
+		return s.fail(err)
+	}
+
+	statement := prefix + cond.code + ") {" + code + "}"
+
+	if otherwise != nil {
+		code, err = vkscriptBody(otherwise)
+		if err != nil {
+			return s.fail(err)
+		}
+
+		statement += " else {" + code + "}"
+	}
+
+	s.statements = append(s.statements, statement)
+
+	return s
+}
+
+func (s *VKScript) fail(err error) *VKScript {
+	if s.err == nil {
+		s.err = err
+	}
+	return s
+}
+
+func vkscriptBody(body func(s *VKScript)) (string, error) {
+	inner := NewVKScript()
+	if body != nil {
+		body(inner)
+	}
+
+	if inner.err != nil {
+		return "", inner.err
+	}
+
+	if len(inner.statements) == 0 {
+		return "", nil
+	}
+
+	return "\n" + strings.Join(inner.statements, "\n") + "\n", nil
+}
+
+func vkscriptName(name string) error {
+	if !vkscriptIdentifier.MatchString(name) || vkscriptReserved[name] {
+		return internalErrors.Error("Request.VKScript", "invalid variable name "+strconv.Quote(name))
+	}
+
+	return nil
+}
+
+// vkscriptValue returns the expression or encodes the value as a literal
+func vkscriptValue(v interface{}) VKScriptExpr {
+	switch v := v.(type) {
+	case VKScriptExpr:
+		return v
+	case *VKScriptExpr:
+		return *v
+	}
+
+	return VKScriptLiteral(v)
+}
+
+// vkscriptLiteral encodes maps and slices of interface{} element by element, so they may contain expressions.
+// Other values are encoded as JSON without HTML escaping, which is valid VKScript
+func vkscriptLiteral(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case VKScriptExpr:
+		return v.code, v.err
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		items := make([]string, 0, len(keys))
+		for _, key := range keys {
+			item := vkscriptValue(v[key])
+			if item.err != nil {
+				return "", item.err
+			}
+
+			name, err := vkscriptJSON(key)
+			if err != nil {
+				return "", err
+			}
+
+			items = append(items, name+":"+item.code)
+		}
+
+		return "{" + strings.Join(items, ",") + "}", nil
+	case []interface{}:
+		expr := VKScriptArray(v...)
+		return expr.code, expr.err
+	}
+
+	return vkscriptJSON(v)
+}
+
+func vkscriptJSON(v interface{}) (string, error) {
+	buf := new(bytes.Buffer)
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(v); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
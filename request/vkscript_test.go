@@ -0,0 +1,194 @@
+package request_test
+
+import (
+	"context"
+	"go-vk-sdk/request"
+	"strings"
+	"testing"
+)
+
+func TestVKScriptStringLiteral(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "quote", value: `"); API.wall.post({}); ("`, want: `"\"); API.wall.post({}); (\""`},
+		{name: "backslash", value: `a\"b`, want: `"a\\\"b"`},
+		{name: "line separators", value: "a\u2028b\u2029c", want: `"a\u2028b\u2029c"`},
+		{name: "control characters", value: "a\nb\tc\x00d\x1f", want: `"a\nb\tc\u0000d\u001f"`},
+		{name: "html is kept", value: "<b>&</b>", want: `"<b>&</b>"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			code, err := request.NewVKScript().Return(test.value).Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if want := "return " + test.want + ";"; code != want {
+				t.Errorf("got %s, want %s", code, want)
+			}
+		})
+	}
+}
+
+func TestVKScriptCollections(t *testing.T) {
+	code, err := request.NewVKScript().
+		Var("ids", request.VKScriptArray(1, "two", nil, true, request.VKScriptArg("id"))).
+		Var("object", map[string]interface{}{
+			"b":     []interface{}{1, request.VKScriptVar("ids").Index(0)},
+			"a":     "x",
+			"c d":   []int{1, 2},
+			"inner": map[string]interface{}{"key": request.VKScriptVar("ids").Length()},
+		}).
+		Return(request.VKScriptVar("object")).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the keys of the maps are sorted, so the code of the program is stable
+	want := strings.Join([]string{
+		`var ids = [1,"two",null,true,Args.id];`,
+		`var object = {"a":"x","b":[1,ids[0]],"c d":[1,2],"inner":{"key":ids.length}};`,
+		`return object;`,
+	}, "\n")
+
+	if code != want {
+		t.Errorf("got\n%s\nwant\n%s", code, want)
+	}
+}
+
+func TestVKScriptCall(t *testing.T) {
+	code, err := request.NewVKScript().
+		Var("users", request.VKScriptCall("users.get", map[string]interface{}{
+			"user_ids": `1,2"`,
+			"fields":   request.VKScriptArg("fields"),
+			"count":    request.VKScriptVar("count").Add(1),
+		})).
+		IfElse(request.VKScriptVar("users").Length().Gt(0), func(s *request.VKScript) {
+			s.Return(request.VKScriptVar("users").Index(0).Field("first_name"))
+		}, func(s *request.VKScript) {
+			s.Return(nil)
+		}).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Join([]string{
+		`var users = API.users.get({"count":(count + 1),"fields":Args.fields,"user_ids":"1,2\""});`,
+		`if ((users.length > 0)) {`,
+		`return users[0].first_name;`,
+		`} else {`,
+		`return null;`,
+		`}`,
+	}, "\n")
+
+	if code != want {
+		t.Errorf("got\n%s\nwant\n%s", code, want)
+	}
+}
+
+func TestVKScriptErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		script *request.VKScript
+		err    string
+	}{
+		{
+			name:   "empty",
+			script: request.NewVKScript(),
+			err:    "empty program",
+		},
+		{
+			name:   "variable name",
+			script: request.NewVKScript().Var("a b", 1).Return(1),
+			err:    `invalid variable name "a b"`,
+		},
+		{
+			name:   "reserved variable name",
+			script: request.NewVKScript().Var("API", 1),
+			err:    `invalid variable name "API"`,
+		},
+		{
+			name:   "variable reference",
+			script: request.NewVKScript().Return(request.VKScriptVar("x;y")),
+			err:    `invalid variable name "x;y"`,
+		},
+		{
+			name:   "argument name",
+			script: request.NewVKScript().Return(request.VKScriptArg("id)")),
+			err:    `invalid argument name "id)"`,
+		},
+		{
+			name:   "field name",
+			script: request.NewVKScript().Return(request.VKScriptVar("users").Field("a.b")),
+			err:    `invalid field name "a.b"`,
+		},
+		{
+			name:   "method name",
+			script: request.NewVKScript().Do(request.VKScriptCall("users.get({});API.wall", nil)),
+			err:    `invalid method name`,
+		},
+		{
+			name:   "literal",
+			script: request.NewVKScript().Return(func() {}),
+			err:    "error encoding literal",
+		},
+		{
+			name: "inner block",
+			script: request.NewVKScript().If(request.VKScriptRaw("true"), func(s *request.VKScript) {
+				s.Var("1a", 1)
+			}),
+			err: `invalid variable name "1a"`,
+		},
+		{
+			name:   "first error is kept",
+			script: request.NewVKScript().Var("a-", 1).Var("b-", 2),
+			err:    `invalid variable name "a-"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			code, err := test.script.Build()
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("got error %v, want %q", err, test.err)
+			}
+
+			if code != "" {
+				t.Errorf("got code %q with the error", code)
+			}
+		})
+	}
+}
+
+func TestExecuteProcedureName(t *testing.T) {
+	s, a, g := newServer(t)
+
+	for _, name := range []string{"", "get users", "users.get", "a;b", "1a"} {
+		var result interface{}
+		err := request.NewExecuteProcedureRequest(a, g, name).Exec(context.Background(), &result)
+		if err == nil || !strings.Contains(err.Error(), "invalid procedure name") {
+			t.Errorf("got error %v for the name %q", err, name)
+		}
+	}
+
+	if calls := len(s.Calls()); calls != 0 {
+		t.Errorf("got %d calls for the invalid names", calls)
+	}
+
+	s.Respond("execute.getUsers", 1)
+
+	var result int
+	if err := request.NewExecuteProcedureRequest(a, g, "getUsers").Arg("id", "1").Exec(context.Background(), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	if call := s.LastCall("execute.getUsers"); call == nil || call.Get("id") != "1" || result != 1 {
+		t.Errorf("unexpected call %+v, result %d", call, result)
+	}
+}
@@ -5,11 +5,21 @@ import (
 	"go-vk-sdk/actor"
 	"go-vk-sdk/api"
 	"go-vk-sdk/constants"
+	internalErrors "go-vk-sdk/errors"
+	"go-vk-sdk/objects"
 	"go-vk-sdk/response"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Doc: https://dev.vk.com/method/wall
 
+const (
+	WallPostAttachmentsLimit = 10                // max number of the attachments of the post
+	WallDonutPaidForever     = time.Duration(-1) // the post is available only to the paid subscribers of VK Donut forever
+)
+
 // WallCheckCopyrightLinkRequest defines the request for wall.checkCopyrightLink
 //
 // Checks if a given link has a valid copyright for posting.
@@ -120,10 +130,121 @@ func NewWallEditRequest(a *api.API, actor actor.Actor) *WallEditRequest {
 
 // Exec executes the request and unmarshals the response into WallEditResponse
 func (r *WallEditRequest) Exec(ctx context.Context) (response response.WallEditResponse, err error) {
+	if err = r.Validate(); err != nil {
+		return
+	}
+
 	err = r.PostUnmarshal(ctx, &response)
 	return
 }
 
+// PostID sets the ID of the post
+func (r *WallEditRequest) PostID(id int) *WallEditRequest {
+	r.parameters.Set(constants.ParameterNamePostID, strconv.Itoa(id))
+	return r
+}
+
+// OwnerID sets the ID of the user or community on whose wall the post is published, negative for communities
+func (r *WallEditRequest) OwnerID(id int) *WallEditRequest {
+	r.parameters.Set(constants.ParameterNameOwnerID, strconv.Itoa(id))
+	return r
+}
+
+// FromGroup determines whether to publish the post on behalf of the community, only if owner_id < 0
+func (r *WallEditRequest) FromGroup(v bool) *WallEditRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameFromGroup, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameFromGroup, "0")
+	}
+	return r
+}
+
+// Message sets the text of the post. Required if attachments is not set
+func (r *WallEditRequest) Message(v string) *WallEditRequest {
+	r.parameters.Set(constants.ParameterNameMessage, v)
+	return r
+}
+
+// Attachments sets the objects attached to the post, up to 10. Objects are formatted as type{owner_id}_{id}
+func (r *WallEditRequest) Attachments(attachments ...objects.Attachment) *WallEditRequest {
+	r.parameters.Set(constants.ParameterNameAttachments, joinAttachments(attachments))
+	return r
+}
+
+// AttachmentsString sets the objects attached to the post in the format type{owner_id}_{id}, or the link to the page
+func (r *WallEditRequest) AttachmentsString(attachments ...string) *WallEditRequest {
+	r.parameters.Set(constants.ParameterNameAttachments, strings.Join(attachments, ","))
+	return r
+}
+
+// PublishDate sets the date of the publication of the scheduled post. The zero time removes the parameter
+func (r *WallEditRequest) PublishDate(date time.Time) *WallEditRequest {
+	setWallPostPublishDate(r.parameters, date)
+	return r
+}
+
+// Copyright sets the link to the source of the post
+func (r *WallEditRequest) Copyright(link string) *WallEditRequest {
+	r.parameters.Set(constants.ParameterNameCopyright, link)
+	return r
+}
+
+// Location sets the geographical latitude and longitude of the place
+func (r *WallEditRequest) Location(lat, long float64) *WallEditRequest {
+	setWallPostLocation(r.parameters, lat, long)
+	return r
+}
+
+// PlaceID sets the ID of the place where the user was tagged
+func (r *WallEditRequest) PlaceID(id int) *WallEditRequest {
+	r.parameters.Set(constants.ParameterNamePlaceID, strconv.Itoa(id))
+	return r
+}
+
+// Signed determines whether to add the signature of the author to the post published on behalf of the community
+func (r *WallEditRequest) Signed(v bool) *WallEditRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameSigned, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameSigned, "0")
+	}
+	return r
+}
+
+// MarkAsAds determines whether to mark the post as an advertisement
+func (r *WallEditRequest) MarkAsAds(v bool) *WallEditRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameMarkAsAds, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameMarkAsAds, "0")
+	}
+	return r
+}
+
+// CloseComments determines whether to disable the comments of the post
+func (r *WallEditRequest) CloseComments(v bool) *WallEditRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameCloseComments, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameCloseComments, "0")
+	}
+	return r
+}
+
+// DonutPaidDuration sets the period during which the post is available only to the paid subscribers of VK Donut,
+// from 1 to 7 days or WallDonutPaidForever
+func (r *WallEditRequest) DonutPaidDuration(d time.Duration) *WallEditRequest {
+	setWallPostDonutPaidDuration(r.parameters, d)
+	return r
+}
+
+// Validate checks the parameters of the post locally: text or attachments, the number of attachments,
+// publish_date in the future and donut_paid_duration
+func (r *WallEditRequest) Validate() error {
+	return validateWallPost("Request.WallEditRequest.Validate()", r.GetParameters(), time.Now())
+}
+
 // WallEditAdsStealthRequest defines the request for wall.editAdsStealth
 //
 // Edits a hidden (stealth) post on a wall.
@@ -413,10 +534,141 @@ func NewWallPostRequest(a *api.API, actor actor.Actor) *WallPostRequest {
 
 // Exec executes the request and unmarshals the response into WallPostResponse
 func (r *WallPostRequest) Exec(ctx context.Context) (response response.WallPostResponse, err error) {
+	if err = r.Validate(); err != nil {
+		return
+	}
+
 	err = r.PostUnmarshal(ctx, &response)
 	return
 }
 
+// OwnerID sets the ID of the user or community on whose wall the post is published, negative for communities
+func (r *WallPostRequest) OwnerID(id int) *WallPostRequest {
+	r.parameters.Set(constants.ParameterNameOwnerID, strconv.Itoa(id))
+	return r
+}
+
+// FromGroup determines whether to publish the post on behalf of the community, only if owner_id < 0
+func (r *WallPostRequest) FromGroup(v bool) *WallPostRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameFromGroup, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameFromGroup, "0")
+	}
+	return r
+}
+
+// Message sets the text of the post. Required if attachments is not set
+func (r *WallPostRequest) Message(v string) *WallPostRequest {
+	r.parameters.Set(constants.ParameterNameMessage, v)
+	return r
+}
+
+// Attachments sets the objects attached to the post, up to 10. Objects are formatted as type{owner_id}_{id}
+func (r *WallPostRequest) Attachments(attachments ...objects.Attachment) *WallPostRequest {
+	r.parameters.Set(constants.ParameterNameAttachments, joinAttachments(attachments))
+	return r
+}
+
+// AttachmentsString sets the objects attached to the post in the format type{owner_id}_{id}, or the link to the page
+func (r *WallPostRequest) AttachmentsString(attachments ...string) *WallPostRequest {
+	r.parameters.Set(constants.ParameterNameAttachments, strings.Join(attachments, ","))
+	return r
+}
+
+// PublishDate sets the date of the publication of the scheduled post. The zero time removes the parameter
+func (r *WallPostRequest) PublishDate(date time.Time) *WallPostRequest {
+	setWallPostPublishDate(r.parameters, date)
+	return r
+}
+
+// Copyright sets the link to the source of the post
+func (r *WallPostRequest) Copyright(link string) *WallPostRequest {
+	r.parameters.Set(constants.ParameterNameCopyright, link)
+	return r
+}
+
+// Location sets the geographical latitude and longitude of the place
+func (r *WallPostRequest) Location(lat, long float64) *WallPostRequest {
+	setWallPostLocation(r.parameters, lat, long)
+	return r
+}
+
+// PlaceID sets the ID of the place where the user was tagged
+func (r *WallPostRequest) PlaceID(id int) *WallPostRequest {
+	r.parameters.Set(constants.ParameterNamePlaceID, strconv.Itoa(id))
+	return r
+}
+
+// Signed determines whether to add the signature of the author to the post published on behalf of the community
+func (r *WallPostRequest) Signed(v bool) *WallPostRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameSigned, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameSigned, "0")
+	}
+	return r
+}
+
+// MarkAsAds determines whether to mark the post as an advertisement
+func (r *WallPostRequest) MarkAsAds(v bool) *WallPostRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameMarkAsAds, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameMarkAsAds, "0")
+	}
+	return r
+}
+
+// CloseComments determines whether to disable the comments of the post
+func (r *WallPostRequest) CloseComments(v bool) *WallPostRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameCloseComments, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameCloseComments, "0")
+	}
+	return r
+}
+
+// DonutPaidDuration sets the period during which the post is available only to the paid subscribers of VK Donut,
+// from 1 to 7 days or WallDonutPaidForever
+func (r *WallPostRequest) DonutPaidDuration(d time.Duration) *WallPostRequest {
+	setWallPostDonutPaidDuration(r.parameters, d)
+	return r
+}
+
+// Validate checks the parameters of the post locally: text or attachments, the number of attachments,
+// publish_date in the future and donut_paid_duration
+func (r *WallPostRequest) Validate() error {
+	return validateWallPost("Request.WallPostRequest.Validate()", r.GetParameters(), time.Now())
+}
+
+// FriendsOnly determines whether to publish the post only for friends, only for the user wall
+func (r *WallPostRequest) FriendsOnly(v bool) *WallPostRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameFriendsOnly, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameFriendsOnly, "0")
+	}
+	return r
+}
+
+// MuteNotifications determines whether to skip the notifications about the post to the subscribers
+func (r *WallPostRequest) MuteNotifications(v bool) *WallPostRequest {
+	if v {
+		r.parameters.Set(constants.ParameterNameMuteNotifications, "1")
+	} else {
+		r.parameters.Set(constants.ParameterNameMuteNotifications, "0")
+	}
+	return r
+}
+
+// GUID sets the unique identifier preventing the same post from being published twice
+func (r *WallPostRequest) GUID(guid string) *WallPostRequest {
+	r.parameters.Set(constants.ParameterNameGUID, guid)
+	return r
+}
+
 // WallPostAdsStealthRequest defines the request for wall.postAdsStealth
 //
 // Creates a hidden (stealth) post on the wall.
@@ -589,3 +841,67 @@ func (r *WallUnpinRequest) Exec(ctx context.Context) (response response.WallUnpi
 	err = r.PostUnmarshal(ctx, &response)
 	return
 }
+
+// setWallPostPublishDate sets publish_date of wall.post and wall.edit, the zero time removes it
+func setWallPostPublishDate(parameters Parameters, date time.Time) {
+	if date.IsZero() {
+		parameters.Remove(constants.ParameterNamePublishDate)
+	} else {
+		_ = parameters.Set(constants.ParameterNamePublishDate, strconv.FormatInt(date.Unix(), 10))
+	}
+}
+
+// setWallPostLocation sets lat and long of wall.post and wall.edit
+func setWallPostLocation(parameters Parameters, lat, long float64) {
+	_ = parameters.Set(constants.ParameterNameLat, strconv.FormatFloat(lat, 'f', -1, 64))
+	_ = parameters.Set(constants.ParameterNameLong, strconv.FormatFloat(long, 'f', -1, 64))
+}
+
+// setWallPostDonutPaidDuration sets donut_paid_duration of wall.post and wall.edit in seconds, -1 for WallDonutPaidForever.
+// The other negative durations are kept as the text of the duration, so validateWallPost rejects them
+// instead of sending -1 for -time.Second
+func setWallPostDonutPaidDuration(parameters Parameters, d time.Duration) {
+	switch {
+	case d == WallDonutPaidForever:
+		_ = parameters.Set(constants.ParameterNameDonutPaidDuration, "-1")
+	case d < 0:
+		_ = parameters.Set(constants.ParameterNameDonutPaidDuration, d.String())
+	default:
+		_ = parameters.Set(constants.ParameterNameDonutPaidDuration, strconv.Itoa(int(d/time.Second)))
+	}
+}
+
+// validateWallPost checks the parameters of wall.post and wall.edit
+func validateWallPost(from string, parameters Parameters, now time.Time) error {
+	attachments := parameters.Get(constants.ParameterNameAttachments)
+
+	if parameters.Get(constants.ParameterNameMessage) == "" && attachments == "" {
+		return internalErrors.Error(from, "message or attachments is required")
+	}
+
+	if attachments != "" && len(strings.Split(attachments, ",")) > WallPostAttachmentsLimit {
+		return internalErrors.Error(from, "too many attachments, the limit is "+strconv.Itoa(WallPostAttachmentsLimit))
+	}
+
+	if publishDate := parameters.Get(constants.ParameterNamePublishDate); publishDate != "" {
+		unix, err := strconv.ParseInt(publishDate, 10, 64)
+		if err != nil {
+			return internalErrors.Error(from, "invalid publish_date "+publishDate)
+		}
+
+		date := time.Unix(unix, 0)
+		if !date.After(now) {
+			return internalErrors.Error(from, "publish_date must be in the future")
+		}
+	}
+
+	if duration := parameters.Get(constants.ParameterNameDonutPaidDuration); duration != "" && duration != "-1" {
+		seconds, err := strconv.Atoi(duration)
+		day := int(24 * time.Hour / time.Second)
+		if err != nil || seconds < day || seconds > 7*day || seconds%day != 0 {
+			return internalErrors.Error(from, "donut_paid_duration must be from 1 to 7 days or forever")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package request_test
+
+import (
+	"go-vk-sdk/actor"
+	"go-vk-sdk/api"
+	"go-vk-sdk/request"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWallPostValidate(t *testing.T) {
+	a, g := api.NewAPI(), &actor.User{ID: 1, AccessToken: "token"}
+	day := 24 * time.Hour
+
+	attachments := make([]string, 0, request.WallPostAttachmentsLimit+1)
+	for i := 0; i <= request.WallPostAttachmentsLimit; i++ {
+		attachments = append(attachments, "photo1_1")
+	}
+
+	tests := []struct {
+		name string
+		post *request.WallPostRequest
+		err  string
+	}{
+		{
+			name: "empty",
+			post: request.NewWallPostRequest(a, g),
+			err:  "message or attachments is required",
+		},
+		{
+			name: "attachments only",
+			post: request.NewWallPostRequest(a, g).AttachmentsString("photo1_1"),
+		},
+		{
+			name: "attachments limit",
+			post: request.NewWallPostRequest(a, g).AttachmentsString(attachments...),
+			err:  "too many attachments",
+		},
+		{
+			name: "attachments at the limit",
+			post: request.NewWallPostRequest(a, g).AttachmentsString(attachments[1:]...),
+		},
+		{
+			name: "publish_date in the past",
+			post: request.NewWallPostRequest(a, g).Message("text").PublishDate(time.Now().Add(-time.Minute)),
+			err:  "publish_date must be in the future",
+		},
+		{
+			name: "donut 1 day",
+			post: request.NewWallPostRequest(a, g).Message("text").DonutPaidDuration(day),
+		},
+		{
+			name: "donut 7 days",
+			post: request.NewWallPostRequest(a, g).Message("text").DonutPaidDuration(7 * day),
+		},
+		{
+			name: "donut forever",
+			post: request.NewWallPostRequest(a, g).Message("text").DonutPaidDuration(request.WallDonutPaidForever),
+		},
+		{
+			name: "donut 8 days",
+			post: request.NewWallPostRequest(a, g).Message("text").DonutPaidDuration(8 * day),
+			err:  "donut_paid_duration",
+		},
+		{
+			name: "donut part of the day",
+			post: request.NewWallPostRequest(a, g).Message("text").DonutPaidDuration(36 * time.Hour),
+			err:  "donut_paid_duration",
+		},
+		{
+			name: "donut negative",
+			post: request.NewWallPostRequest(a, g).Message("text").DonutPaidDuration(-time.Hour),
+			err:  "donut_paid_duration",
+		},
+		{
+			name: "donut minus one second",
+			post: request.NewWallPostRequest(a, g).Message("text").DonutPaidDuration(-time.Second),
+			err:  "donut_paid_duration",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.post.Validate()
+			if test.err == "" && err != nil {
+				t.Errorf("got error %v", err)
+			}
+
+			if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+				t.Errorf("got error %v, want %q", err, test.err)
+			}
+		})
+	}
+
+	if duration := request.NewWallPostRequest(a, g).DonutPaidDuration(request.WallDonutPaidForever).GetParameters().Get("donut_paid_duration"); duration != "-1" {
+		t.Errorf("got donut_paid_duration %q for WallDonutPaidForever", duration)
+	}
+
+	if err := request.NewWallEditRequest(a, g).DonutPaidDuration(-day).Validate(); err == nil {
+		t.Error("wall.edit accepts the negative duration")
+	}
+}
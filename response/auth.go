@@ -34,6 +34,15 @@ func (a *AuthDirectResponse) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("unable to parse direct auth response: %s", string(data))
 }
 
+// Err returns the OAuth error of the response or nil
+func (a *AuthDirectResponse) Err() error {
+	if a.Error == nil {
+		return nil
+	}
+
+	return a.Error
+}
+
 type AuthUserCodeFlowResponse struct {
 	User  *actor.User               // {"access_token":"vk1.UMx","expires_in":86179,"user_id":111}
 	Error *errors.AuthCodeFlowError // {"errors":"invalid_grant","error_description":"Code is invalid or expired."}
@@ -58,6 +67,15 @@ func (a *AuthUserCodeFlowResponse) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("unable to parse user code flow auth response: %s", string(data))
 }
 
+// Err returns the OAuth error of the response or nil
+func (a *AuthUserCodeFlowResponse) Err() error {
+	if a.Error == nil {
+		return nil
+	}
+
+	return a.Error
+}
+
 type AuthGroupCodeFlowResponse struct {
 	Group *actor.Group
 	Error *errors.AuthCodeFlowError
@@ -82,6 +100,15 @@ func (a *AuthGroupCodeFlowResponse) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("unable to parse groip code flow auth response: %s", string(data))
 }
 
+// Err returns the OAuth error of the response or nil
+func (a *AuthGroupCodeFlowResponse) Err() error {
+	if a.Error == nil {
+		return nil
+	}
+
+	return a.Error
+}
+
 type AuthUserVKIDCodeFlowResponse struct {
 	User  *actor.UserVKID
 	Error *errors.AuthCodeFlowError
@@ -106,6 +133,15 @@ func (a *AuthUserVKIDCodeFlowResponse) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("unable to parse user code flow auth response: %s", string(data))
 }
 
+// Err returns the OAuth error of the response or nil
+func (a *AuthUserVKIDCodeFlowResponse) Err() error {
+	if a.Error == nil {
+		return nil
+	}
+
+	return a.Error
+}
+
 type AuthRestoreResponse struct {
 	BaseResponse
 	Response struct {
@@ -1,24 +1,25 @@
 package response
 
 import (
+	"encoding/json"
 	"go-vk-sdk/errors"
 )
 
 type ExecuteResponse struct {
 	BaseResponse
+	Response      json.RawMessage         `json:"response"`
 	ExecuteErrors errors.ExecuteAPIErrors `json:"execute_errors"`
-	Response      []byte                  `json:"response"`
 }
 
-func (e *ExecuteResponse) MarshalJSON() ([]byte, error) {
-	if e == nil {
-		return []byte("null"), nil
+// Err returns *errors.APIError if the whole call failed or *errors.ExecuteAPIErrors if some of the inner calls failed
+func (e *ExecuteResponse) Err() error {
+	if err := e.BaseResponse.Err(); err != nil {
+		return err
 	}
 
-	return append([]byte{}, e.Response...), nil
-}
+	if len(e.ExecuteErrors) > 0 {
+		return &e.ExecuteErrors
+	}
 
-func (e *ExecuteResponse) UnmarshalJSON(data []byte) error {
-	e.Response = append(e.Response[0:0], data...)
 	return nil
 }
@@ -376,9 +376,19 @@ type PhotosUploadPhotoMarketAlbumResponse struct {
 	Server int    `json:"server"` // Upload server number
 }
 
-type PhotosUploadVideoResponse errors.UploadAPIError
+// PhotosUploadVideoResponse Response of the upload server to the video or to the last chunk of the video
+type PhotosUploadVideoResponse struct {
+	Size      int    `json:"size"`       // Size of the video in bytes
+	VideoID   int    `json:"video_id"`   // Video ID
+	OwnerID   int    `json:"owner_id"`   // Video owner ID
+	VideoHash string `json:"video_hash"` // Video hash
+	errors.UploadAPIError
+}
 
-type PhotosUploadDocumentResponse errors.UploadAPIError
+type PhotosUploadDocumentResponse struct {
+	File string `json:"file"` // Uploaded document data
+	errors.UploadAPIError
+}
 
 type PhotosUploadPhotoStoriesResponse struct {
 	Response struct {
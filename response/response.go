@@ -8,3 +8,13 @@ type BaseResponse struct {
 	Response interface{}     `json:"response"` // override
 	Error    errors.APIError `json:"error"`
 }
+
+// Err returns the VK error of the envelope as *errors.APIError or nil
+func (r *BaseResponse) Err() error {
+	if r.Error.Code == 0 {
+		return nil
+	}
+
+	err := r.Error
+	return &err
+}
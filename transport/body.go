@@ -0,0 +1,70 @@
+package transport
+
+import (
+	internalErrors "go-vk-sdk/errors"
+	"io"
+	"net/http"
+)
+
+// ReplayableBody Request body which is recreated by the factory for every attempt of the request,
+// so retried requests send the whole payload again.
+//
+//	Bodies of type *bytes.Buffer, *bytes.Reader and *strings.Reader are replayable without it.
+//	Requests with any other body are sent only once.
+type ReplayableBody struct {
+	io.Reader
+	factory func() (io.Reader, error)
+	length  int64
+}
+
+// NewReplayableBody length is the exact size of the body in bytes or -1 if it is unknown
+func NewReplayableBody(factory func() (io.Reader, error), length int64) (*ReplayableBody, error) {
+	if factory == nil {
+		return nil, internalErrors.Error("Transport.NewReplayableBody()", "body factory is undefined")
+	}
+
+	reader, err := factory()
+	if err != nil {
+		return nil, internalErrors.Error("Transport.NewReplayableBody()", "error create body "+err.Error())
+	}
+
+	return &ReplayableBody{Reader: reader, factory: factory, length: length}, nil
+}
+
+// Len returns the size of the body in bytes or -1 if it is unknown
+func (b *ReplayableBody) Len() int64 {
+	return b.length
+}
+
+func (b *ReplayableBody) getBody() (io.ReadCloser, error) {
+	reader, err := b.factory()
+	if err != nil {
+		return nil, err
+	}
+
+	if rc, ok := reader.(io.ReadCloser); ok {
+		return rc, nil
+	}
+
+	return io.NopCloser(reader), nil
+}
+
+// rewindBody prepares the body of the request for the next attempt
+func rewindBody(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+
+	if req.GetBody == nil {
+		return internalErrors.Error("Transport.rewindBody()", "request body cannot be replayed")
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return internalErrors.Error("Transport.rewindBody()", "error recreate request body "+err.Error())
+	}
+
+	req.Body = body
+
+	return nil
+}
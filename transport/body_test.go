@@ -0,0 +1,120 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type bodyAttempt struct {
+	body          string
+	contentLength int64
+}
+
+// failingServer answers 500 to the first attempt and 200 to the next ones, recording the received bodies
+func failingServer(t *testing.T) (*httptest.Server, func() []bodyAttempt) {
+	var (
+		mtx      sync.Mutex
+		attempts []bodyAttempt
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mtx.Lock()
+		attempts = append(attempts, bodyAttempt{body: string(body), contentLength: r.ContentLength})
+		first := len(attempts) == 1
+		mtx.Unlock()
+
+		if first {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"response":1}`)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []bodyAttempt {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return append([]bodyAttempt(nil), attempts...)
+	}
+}
+
+func newRetryClient() *HTTPClient {
+	client := NewHTTPClient()
+	client.SetRetryPolicy(&BackoffRetryPolicy{MaxAttempts: 3, InitialInterval: time.Millisecond, Multiplier: 1})
+	return client
+}
+
+func TestReplayableBodyRetry(t *testing.T) {
+	server, attempts := failingServer(t)
+
+	const payload = "--boundary\r\ncontent of the file\r\n--boundary--\r\n"
+
+	factories := 0
+	body, err := NewReplayableBody(func() (io.Reader, error) {
+		factories++
+		// the reader is neither seekable nor known to net/http, only the factory can recreate it
+		return io.MultiReader(strings.NewReader(payload[:10]), strings.NewReader(payload[10:])), nil
+	}, int64(len(payload)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := newRetryClient().Post(context.Background(), server.URL, body, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	got := attempts()
+	if len(got) != 2 {
+		t.Fatalf("got %d attempts, want 2", len(got))
+	}
+
+	for i, attempt := range got {
+		if attempt.body != payload || attempt.contentLength != int64(len(payload)) {
+			t.Errorf("attempt %d: got body %q with Content-Length %d", i+1, attempt.body, attempt.contentLength)
+		}
+	}
+
+	if factories != 2 {
+		t.Errorf("the body is created %d times, want 2", factories)
+	}
+}
+
+func TestNonReplayableBodyIsNotRetried(t *testing.T) {
+	server, attempts := failingServer(t)
+
+	body := io.MultiReader(strings.NewReader("payload"))
+	if _, err := newRetryClient().Post(context.Background(), server.URL, body, nil); err == nil {
+		t.Fatal("the failed attempt is not reported")
+	}
+
+	if got := attempts(); len(got) != 1 || got[0].body != "payload" {
+		t.Errorf("got attempts %+v, want the single attempt", got)
+	}
+}
+
+func TestBufferedBodyRetry(t *testing.T) {
+	server, attempts := failingServer(t)
+
+	resp, err := newRetryClient().Post(context.Background(), server.URL, strings.NewReader("payload"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	got := attempts()
+	if len(got) != 2 || got[0] != got[1] || got[1].body != "payload" || got[1].contentLength != 7 {
+		t.Errorf("got attempts %+v", got)
+	}
+}
@@ -0,0 +1,432 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	internalErrors "go-vk-sdk/errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type CassetteMode int
+
+const (
+	CassetteModeReplay CassetteMode = iota + 1 // serve recorded interactions, no network access
+	CassetteModeRecord                         // send requests through the inner client and record them
+)
+
+const CassetteRedacted string = "REDACTED"
+
+// CassetteInteraction Recorded pair of the request and the response
+type CassetteInteraction struct {
+	Method     string      `json:"method,omitempty"` // api method, empty for upload and long poll requests
+	HTTPMethod string      `json:"http_method"`
+	URL        string      `json:"url"` // URL without query
+	Parameters url.Values  `json:"parameters,omitempty"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+type cassetteFile struct {
+	Interactions []CassetteInteraction `json:"interactions"`
+}
+
+// Cassette Client which records VK request/response pairs to a file and serves them back for offline tests.
+//
+//	In the record mode requests are sent through the inner client, secrets are redacted before they are stored:
+//	the Authorization header, the access_token, password and client_secret parameters and the same fields of JSON responses.
+//	In the replay mode the requests are matched by the api method (or URL for requests without a method) and parameters,
+//	redacted and ignored parameters are not compared. Repeated matching requests get the recorded responses in order,
+//	the last one is served when they are exhausted.
+type Cassette struct {
+	mtx          sync.Mutex
+	mode         CassetteMode
+	path         string
+	client       Client
+	interactions []CassetteInteraction
+	played       map[string]int
+	redacted     map[string]bool
+	redactedBody *regexp.Regexp // string values of the redacted JSON fields
+	ignored      map[string]bool
+	middlewares  []Middleware
+	isClose      bool
+}
+
+// NewCassetteRecorder records the requests sent through the client, the file is written by Save or Close
+func NewCassetteRecorder(path string, client Client) *Cassette {
+	if client == nil {
+		client = NewHTTPClient()
+	}
+
+	return newCassette(CassetteModeRecord, path, client)
+}
+
+// NewCassetteReplayer loads the interactions recorded to the file
+func NewCassetteReplayer(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, internalErrors.Error("Transport.NewCassetteReplayer()", "error read cassette "+err.Error())
+	}
+
+	var file cassetteFile
+	if err = json.Unmarshal(data, &file); err != nil {
+		return nil, internalErrors.Error("Transport.NewCassetteReplayer()", "error decoding cassette "+err.Error())
+	}
+
+	c := newCassette(CassetteModeReplay, path, nil)
+	c.interactions = file.Interactions
+
+	return c, nil
+}
+
+func newCassette(mode CassetteMode, path string, client Client) *Cassette {
+	c := &Cassette{
+		mode:     mode,
+		path:     path,
+		client:   client,
+		played:   make(map[string]int),
+		redacted: map[string]bool{"access_token": true, "password": true, "client_secret": true},
+		ignored:  map[string]bool{"random_id": true},
+	}
+	c.redactedBody = redactedBodyPattern(c.redacted)
+
+	return c
+}
+
+// Redact adds the names of parameters and JSON response fields whose values are never stored
+func (c *Cassette) Redact(names ...string) {
+	c.mtx.Lock()
+	for _, name := range names {
+		c.redacted[name] = true
+	}
+	c.redactedBody = redactedBodyPattern(c.redacted)
+	c.mtx.Unlock()
+}
+
+// Ignore adds the names of parameters which are not compared in the replay mode, for example random or time dependent values
+func (c *Cassette) Ignore(names ...string) {
+	c.mtx.Lock()
+	for _, name := range names {
+		c.ignored[name] = true
+	}
+	c.mtx.Unlock()
+}
+
+// Interactions returns a copy of the recorded interactions
+func (c *Cassette) Interactions() []CassetteInteraction {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return append([]CassetteInteraction(nil), c.interactions...)
+}
+
+func (c *Cassette) Mode() CassetteMode {
+	return c.mode
+}
+
+// Save writes the recorded interactions to the file
+func (c *Cassette) Save() error {
+	c.mtx.Lock()
+	data, err := json.MarshalIndent(cassetteFile{Interactions: c.interactions}, "", "  ")
+	c.mtx.Unlock()
+	if err != nil {
+		return internalErrors.Error("Transport.Cassette.Save()", "error encoding cassette "+err.Error())
+	}
+
+	if err = os.WriteFile(c.path, data, 0o644); err != nil {
+		return internalErrors.Error("Transport.Cassette.Save()", "error write cassette "+err.Error())
+	}
+
+	return nil
+}
+
+func (c *Cassette) Get(ctx context.Context, url string, header *http.Header) (*http.Response, error) {
+	return c.do(ctx, http.MethodGet, url, nil, header)
+}
+
+func (c *Cassette) GetDecodeJSON(ctx context.Context, url string, target interface{}, header *http.Header) (*http.Response, error) {
+	res, err := c.do(ctx, http.MethodGet, url, nil, header)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, decodeCassetteJSON(res, target)
+}
+
+func (c *Cassette) Post(ctx context.Context, url string, body io.Reader, header *http.Header) (*http.Response, error) {
+	return c.do(ctx, http.MethodPost, url, body, header)
+}
+
+func (c *Cassette) PostDecodeJSON(ctx context.Context, url string, body io.Reader, target interface{}, header *http.Header) (*http.Response, error) {
+	res, err := c.do(ctx, http.MethodPost, url, body, header)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, decodeCassetteJSON(res, target)
+}
+
+func (c *Cassette) SetUserAgent(ua string) {
+	if c.client != nil {
+		c.client.SetUserAgent(ua)
+	}
+}
+
+func (c *Cassette) SetAttemptTimeout(t time.Duration) {
+	if c.client != nil {
+		c.client.SetAttemptTimeout(t)
+	}
+}
+
+// Use appends middlewares which are called around every recorded or replayed request
+func (c *Cassette) Use(middlewares ...Middleware) {
+	c.mtx.Lock()
+	for _, m := range middlewares {
+		if m != nil {
+			c.middlewares = append(c.middlewares, m)
+		}
+	}
+	c.mtx.Unlock()
+}
+
+// Close saves the file in the record mode and closes the inner client
+func (c *Cassette) Close() error {
+	c.mtx.Lock()
+	if c.isClose {
+		c.mtx.Unlock()
+		return internalErrors.ErrorLog("Transport.Cassette.Close()", "cassette is already closed")
+	}
+	c.isClose = true
+	c.mtx.Unlock()
+
+	if c.mode != CassetteModeRecord {
+		return nil
+	}
+
+	if err := c.Save(); err != nil {
+		return err
+	}
+
+	return c.client.Close()
+}
+
+func (c *Cassette) IsClose() bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.isClose
+}
+
+func (c *Cassette) do(ctx context.Context, method, url string, body io.Reader, header *http.Header) (*http.Response, error) {
+	if c.IsClose() {
+		return nil, internalErrors.ErrorLog("Transport.Cassette.do()", "cassette is already closed")
+	}
+
+	req, err := newRequest(ctx, method, url, body, header)
+	if err != nil {
+		return nil, internalErrors.ErrorLog("Transport.Cassette.do()", "error create HTTP request with context "+err.Error())
+	}
+
+	c.mtx.Lock()
+	roundTrip := chainMiddlewares(c.roundTrip, c.middlewares)
+	c.mtx.Unlock()
+
+	return roundTrip(req)
+}
+
+func (c *Cassette) roundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, internalErrors.ErrorLog("Transport.Cassette.roundTrip()", "error read request body "+err.Error())
+		}
+		body = data
+	}
+
+	interaction := c.describe(req, body)
+
+	if c.mode == CassetteModeReplay {
+		return c.replay(req, &interaction)
+	}
+
+	return c.record(req, body, &interaction)
+}
+
+func (c *Cassette) replay(req *http.Request, interaction *CassetteInteraction) (*http.Response, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	key := c.key(interaction)
+
+	var matched []int
+	for i := range c.interactions {
+		if c.key(&c.interactions[i]) == key {
+			matched = append(matched, i)
+		}
+	}
+
+	if len(matched) == 0 {
+		return nil, internalErrors.ErrorLog("Transport.Cassette.replay()", "no recorded interaction for "+key)
+	}
+
+	n := c.played[key]
+	if n >= len(matched) {
+		n = len(matched) - 1
+	}
+	c.played[key]++
+
+	recorded := c.interactions[matched[n]]
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recorded.Header.Clone(),
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+func (c *Cassette) record(req *http.Request, body []byte, interaction *CassetteInteraction) (*http.Response, error) {
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+
+	var resp *http.Response
+	var err error
+	if req.Method == http.MethodGet {
+		resp, err = c.client.Get(req.Context(), req.URL.String(), &req.Header)
+	} else {
+		resp, err = c.client.Post(req.Context(), req.URL.String(), reqBody, &req.Header)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, internalErrors.ErrorLog("Transport.Cassette.record()", "error read response body "+err.Error())
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	header := resp.Header.Clone()
+	// the body is stored decoded
+	header.Del("Content-Encoding")
+	header.Del("Content-Length")
+	header.Del("Set-Cookie")
+
+	interaction.StatusCode = resp.StatusCode
+	interaction.Header = header
+
+	c.mtx.Lock()
+	interaction.Body = c.redactBody(string(data))
+	c.interactions = append(c.interactions, *interaction)
+	c.mtx.Unlock()
+
+	return resp, nil
+}
+
+// describe builds the request part of the interaction with redacted parameters
+func (c *Cassette) describe(req *http.Request, body []byte) CassetteInteraction {
+	u := *req.URL
+	parameters := u.Query()
+	u.RawQuery = ""
+
+	interaction := CassetteInteraction{
+		HTTPMethod: req.Method,
+		URL:        u.String(),
+	}
+
+	if info, ok := CallInfoFromContext(req.Context()); ok {
+		interaction.Method = info.Method
+		for key, value := range info.Parameters {
+			parameters[key] = append([]string(nil), value...)
+		}
+	} else if body != nil && !strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/") {
+		if values, err := url.ParseQuery(string(body)); err == nil {
+			for key, value := range values {
+				parameters[key] = value
+			}
+		}
+	}
+
+	c.mtx.Lock()
+	for key := range parameters {
+		if c.redacted[key] {
+			parameters[key] = []string{CassetteRedacted}
+		}
+	}
+	c.mtx.Unlock()
+
+	if len(parameters) > 0 {
+		interaction.Parameters = parameters
+	}
+
+	return interaction
+}
+
+// key must be called with the lock held
+func (c *Cassette) key(interaction *CassetteInteraction) string {
+	keys := make([]string, 0, len(interaction.Parameters))
+	for key := range interaction.Parameters {
+		if !c.redacted[key] && !c.ignored[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	if interaction.Method != "" {
+		b.WriteString(interaction.Method)
+	} else {
+		b.WriteString(interaction.HTTPMethod + " " + interaction.URL)
+	}
+
+	for _, key := range keys {
+		b.WriteString(" " + key + "=" + strings.Join(interaction.Parameters[key], ","))
+	}
+
+	return b.String()
+}
+
+// redactBody must be called with the lock held
+func (c *Cassette) redactBody(body string) string {
+	return c.redactedBody.ReplaceAllString(body, `${1}"`+CassetteRedacted+`"`)
+}
+
+// redactedBodyPattern matches the string values of the JSON fields with the names, the field name is the first group
+func redactedBodyPattern(names map[string]bool) *regexp.Regexp {
+	quoted := make([]string, 0, len(names))
+	for name := range names {
+		quoted = append(quoted, regexp.QuoteMeta(name))
+	}
+	sort.Strings(quoted)
+
+	return regexp.MustCompile(`("(?:` + strings.Join(quoted, "|") + `)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+}
+
+func decodeCassetteJSON(res *http.Response, target interface{}) error {
+	defer res.Body.Close()
+
+	err := json.NewDecoder(res.Body).Decode(target)
+	if err != nil {
+		return internalErrors.ErrorLog("Transport.Cassette.decodeJSON()", "error decoding JSON "+err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCassetteRecordReplay(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"response":{"n":`+strconv.Itoa(int(n))+`,"access_token":"vk1.a.secret","user":{"password":"p\"w"}}}`)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	recorder := NewCassetteRecorder(path, nil)
+
+	parameters := url.Values{"user_ids": {"1"}, "access_token": {"vk1.a.secret"}, "random_id": {"7"}}
+	ctx := WithCallInfo(context.Background(), &CallInfo{Method: "users.get", Parameters: parameters})
+
+	for i := 0; i < 2; i++ {
+		resp, err := recorder.Post(ctx, server.URL+"/method/users.get", strings.NewReader(parameters.Encode()), &http.Header{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the caller gets the original response
+		body, _ := io.ReadAll(resp.Body)
+		if !strings.Contains(string(body), "vk1.a.secret") {
+			t.Errorf("recorded response is modified: %s", body)
+		}
+	}
+
+	interactions := recorder.Interactions()
+	if len(interactions) != 2 {
+		t.Fatalf("got %d interactions, want 2", len(interactions))
+	}
+
+	for _, interaction := range interactions {
+		if strings.Contains(interaction.Body, "secret") || strings.Contains(interaction.Body, `p\"w`) {
+			t.Errorf("secrets are stored: %s", interaction.Body)
+		}
+
+		if got := interaction.Parameters.Get("access_token"); got != CassetteRedacted {
+			t.Errorf("got access_token %q, want %q", got, CassetteRedacted)
+		}
+	}
+
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	replayer, err := NewCassetteReplayer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// another token and random_id still match the recorded calls
+	parameters = url.Values{"user_ids": {"1"}, "access_token": {"another"}, "random_id": {"8"}}
+	ctx = WithCallInfo(context.Background(), &CallInfo{Method: "users.get", Parameters: parameters})
+
+	for _, want := range []int{1, 2, 2} {
+		var target struct {
+			Response struct {
+				N           int    `json:"n"`
+				AccessToken string `json:"access_token"`
+			} `json:"response"`
+		}
+
+		if _, err = replayer.PostDecodeJSON(ctx, "https://api.vk.com/method/users.get", nil, &target, &http.Header{}); err != nil {
+			t.Fatal(err)
+		}
+
+		if target.Response.N != want || target.Response.AccessToken != CassetteRedacted {
+			t.Errorf("got n=%d access_token=%q, want n=%d", target.Response.N, target.Response.AccessToken, want)
+		}
+	}
+
+	parameters.Set("user_ids", "2")
+	if _, err = replayer.Post(ctx, "https://api.vk.com/method/users.get", nil, &http.Header{}); err == nil {
+		t.Error("unrecorded call is replayed")
+	}
+}
+
+func TestCassetteRedactBody(t *testing.T) {
+	c := newCassette(CassetteModeRecord, "", nil)
+	c.Redact("code", "key.with.dots")
+
+	got := c.redactBody(`{"code": "abc", "key.with.dots":"x", "keyXwithXdots":"y", "client_secret":"s\"t", "count":"1"}`)
+	want := `{"code": "REDACTED", "key.with.dots":"REDACTED", "keyXwithXdots":"y", "client_secret":"REDACTED", "count":"1"}`
+
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
@@ -17,3 +17,20 @@ type Client interface {
 	Close() error
 	IsClose() bool
 }
+
+// MiddlewareClient Optional interface of the clients with the middleware chain, HTTPClient and Cassette implement it
+type MiddlewareClient interface {
+	Use(middlewares ...Middleware)
+}
+
+// Use appends the middlewares to the chain of the client, false if the client does not support middlewares
+func Use(client Client, middlewares ...Middleware) bool {
+	c, ok := client.(MiddlewareClient)
+	if !ok {
+		return false
+	}
+
+	c.Use(middlewares...)
+
+	return true
+}
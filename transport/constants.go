@@ -16,4 +16,8 @@ const (
 	ForceHTTP2                bool          = true
 	RetryAttempts             int           = 3
 	RetryAttemptTimeout       time.Duration = time.Millisecond * 500
+	RetryMaxInterval          time.Duration = time.Millisecond * 10000
+	RetryMaxElapsedTime       time.Duration = time.Millisecond * 30000
+	RetryMultiplier           float64       = 2
+	RetryJitter               float64       = 0.5
 )
@@ -1,15 +1,18 @@
 package transport
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	internalErrors "go-vk-sdk/errors"
 	"io"
 	"net"
 	"net/http"
 	"net/http/cookiejar"
+	"strings"
 	"sync"
 	"time"
 )
@@ -20,6 +23,8 @@ type HTTPClientParameters struct {
 	UserAgent       string            `json:"user_agent,omitempty"`
 	AttemptsRequest int               `json:"attempts_request,omitempty"`
 	AttemptTimeout  time.Duration     `json:"attempt_timeout,omitempty"`
+	RetryPolicy     RetryPolicy       `json:"-"` // if nil, BackoffRetryPolicy with AttemptsRequest and AttemptTimeout is used
+	Middlewares     []Middleware      `json:"-"`
 }
 
 type HTTPClient struct {
@@ -28,6 +33,8 @@ type HTTPClient struct {
 	userAgent       string
 	attemptsRequest int
 	attemptTimeout  time.Duration
+	retryPolicy     RetryPolicy
+	middlewares     []Middleware
 	isClose         bool
 }
 
@@ -84,80 +91,195 @@ func NewHTTPClientParameters(p *HTTPClientParameters) *HTTPClient {
 		client.attemptTimeout = p.AttemptTimeout
 	}
 
+	if p.RetryPolicy != nil {
+		client.retryPolicy = p.RetryPolicy
+	}
+
+	client.Use(p.Middlewares...)
+
 	return client
 }
 
 func (c *HTTPClient) request(ctx context.Context, req *http.Request) (*http.Response, error) {
-	if c.isClose {
+	if c.IsClose() {
 		return nil, internalErrors.ErrorLog("Transport.HttpClient.request()", "client is already closed")
 	}
 
+	c.mtx.RLock()
 	req.Header.Set("User-Agent", c.userAgent)
+	policy := c.getRetryPolicy()
+	roundTrip := chainMiddlewares(c.Do, c.middlewares)
+	c.mtx.RUnlock()
+
 	req.Header.Set("Accept-Encoding", "gzip")
 
-	var err error
-	var resp *http.Response
+	start := time.Now()
 
-	for i := 0; i < c.attemptsRequest; i++ {
+	for attempt := 1; ; attempt++ {
 		if ctx.Err() != nil {
 			return nil, internalErrors.ErrorLog("Transport.HttpClient.request()", "close context "+ctx.Err().Error())
 		}
 
-		resp, err = c.Do(req)
-		if err != nil {
-			time.Sleep(c.attemptTimeout)
-			continue
+		if attempt > 1 {
+			if err := rewindBody(req); err != nil {
+				return nil, internalErrors.ErrorLog("Transport.HttpClient.request()", err.Error())
+			}
+
+			if info, ok := CallInfoFromContext(ctx); ok && info.Wait != nil {
+				if err := info.Wait(ctx); err != nil {
+					return nil, err
+				}
+			}
 		}
 
-		if resp.StatusCode >= 500 {
-			resp.Body.Close()
-			err = internalErrors.ErrorLog("Transport.HttpClient.request()", fmt.Sprintf("unexpected status code %d\n", resp.StatusCode))
-			time.Sleep(c.attemptTimeout)
-			continue
+		resp, err := c.attempt(req, roundTrip)
+		if err == nil {
+			return resp, nil
 		}
 
-		if resp.Header.Get("Content-Encoding") == "gzip" {
-			gzipReader, err := gzip.NewReader(resp.Body)
-			if err != nil {
-				resp.Body.Close()
-				return nil, internalErrors.ErrorLog("Transport.HttpClient.request()", "error decompressing data "+err.Error())
+		delay, ok := policy.Next(attempt, time.Since(start), resp, err)
+		if ok && !isReplayable(req) {
+			ok = false
+		}
+
+		if !ok {
+			// VK error is left in the response envelope, it is decoded by the caller
+			var apiError *internalErrors.APIError
+			if resp != nil && errors.As(err, &apiError) {
+				return resp, nil
 			}
 
-			// Original body objects is replaced with a custom ReadCloser,
-			// which stores the reader of the original body objects and a Gzip reader,
-			// so that when closing all readers are closed correctly
-			resp.Body = &ReadCloser{Origin: resp.Body, Encode: gzipReader}
+			return nil, err
+		}
+
+		if resp != nil {
+			resp.Body.Close()
 		}
 
-		return resp, nil
+		if err = sleepContext(ctx, delay); err != nil {
+			return nil, internalErrors.ErrorLog("Transport.HttpClient.request()", "close context "+err.Error())
+		}
 	}
+}
 
-	return nil, err
+// attempt sends the request once and classifies the result.
+//
+//	On 429 and 5xx status codes the body is closed and an error is returned along with the response,
+//	on VK error in the envelope the body is buffered in memory and *errors.APIError is returned along with the response.
+func (c *HTTPClient) attempt(req *http.Request, roundTrip RoundTrip) (*http.Response, error) {
+	resp, err := roundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		resp.Body.Close()
+		return resp, internalErrors.ErrorLog("Transport.HttpClient.attempt()", fmt.Sprintf("unexpected status code %d", resp.StatusCode))
+	}
+
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gzipReader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			resp.Body.Close()
+			return nil, internalErrors.ErrorLog("Transport.HttpClient.attempt()", "error decompressing data "+err.Error())
+		}
+
+		// Original body objects is replaced with a custom ReadCloser,
+		// which stores the reader of the original body objects and a Gzip reader,
+		// so that when closing all readers are closed correctly
+		resp.Body = &ReadCloser{Origin: resp.Body, Encode: gzipReader}
+	}
+
+	if resp.StatusCode == http.StatusOK && strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		if err = peekAPIError(resp); err != nil {
+			return resp, err
+		}
+	}
+
+	return resp, nil
 }
 
-func (c *HTTPClient) Get(ctx context.Context, url string, header *http.Header) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+// peekAPIError reads the JSON body and returns *errors.APIError if the envelope contains an error.
+// The body is replaced with an in-memory copy, so it can still be decoded by the caller
+func peekAPIError(resp *http.Response) error {
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(data))
 	if err != nil {
-		return nil, internalErrors.ErrorLog("Transport.HttpClient.Get()", "error create HTTP request with context "+err.Error())
+		return err
+	}
+
+	if !bytes.Contains(data, []byte(`"error"`)) {
+		return nil
+	}
+
+	var envelope struct {
+		Error json.RawMessage `json:"error"`
+	}
+
+	if json.Unmarshal(data, &envelope) != nil || len(envelope.Error) == 0 || envelope.Error[0] != '{' {
+		return nil
+	}
+
+	apiError := &internalErrors.APIError{}
+	if json.Unmarshal(envelope.Error, apiError) != nil || apiError.Code == 0 {
+		return nil
+	}
+
+	return apiError
+}
+
+// isReplayable reports whether the body of the request can be sent again
+func isReplayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// newRequest creates the request with a copy of the header, so shared request headers are never modified.
+// The body of type *ReplayableBody is recreated for every attempt
+func newRequest(ctx context.Context, method, url string, body io.Reader, header *http.Header) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if replayable, ok := body.(*ReplayableBody); ok {
+		req.GetBody = replayable.getBody
+		if replayable.length >= 0 {
+			req.ContentLength = replayable.length
+		}
 	}
 
 	if header != nil {
-		req.Header = *header
+		req.Header = header.Clone()
+	}
+
+	return req, nil
+}
+
+// getRetryPolicy must be called with the read lock held
+func (c *HTTPClient) getRetryPolicy() RetryPolicy {
+	if c.retryPolicy != nil {
+		return c.retryPolicy
+	}
+
+	return NewBackoffRetryPolicy(c.attemptsRequest, c.attemptTimeout)
+}
+
+func (c *HTTPClient) Get(ctx context.Context, url string, header *http.Header) (*http.Response, error) {
+	req, err := newRequest(ctx, http.MethodGet, url, nil, header)
+	if err != nil {
+		return nil, internalErrors.ErrorLog("Transport.HttpClient.Get()", "error create HTTP request with context "+err.Error())
 	}
 
 	return c.request(ctx, req)
 }
 
 func (c *HTTPClient) GetDecodeJSON(ctx context.Context, url string, target interface{}, header *http.Header) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	req, err := newRequest(ctx, http.MethodGet, url, nil, header)
 	if err != nil {
 		return nil, internalErrors.ErrorLog("Transport.HttpClient.GetDecodeJSON()", "error create HTTP request with context "+err.Error())
 	}
 
-	if header != nil {
-		req.Header = *header
-	}
-
 	res, err := c.request(ctx, req)
 	if err != nil {
 		return nil, err
@@ -173,28 +295,20 @@ func (c *HTTPClient) GetDecodeJSON(ctx context.Context, url string, target inter
 }
 
 func (c *HTTPClient) Post(ctx context.Context, url string, body io.Reader, header *http.Header) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	req, err := newRequest(ctx, http.MethodPost, url, body, header)
 	if err != nil {
 		return nil, internalErrors.ErrorLog("Transport.HttpClient.Post()", "error create HTTP request with context "+err.Error())
 	}
 
-	if header != nil {
-		req.Header = *header
-	}
-
 	return c.request(ctx, req)
 }
 
 func (c *HTTPClient) PostDecodeJSON(ctx context.Context, url string, body io.Reader, target interface{}, header *http.Header) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	req, err := newRequest(ctx, http.MethodPost, url, body, header)
 	if err != nil {
 		return nil, internalErrors.ErrorLog("Transport.HttpClient.PostDecodeJSON()", "error create HTTP request with context "+err.Error())
 	}
 
-	if header != nil {
-		req.Header = *header
-	}
-
 	res, err := c.request(ctx, req)
 	if err != nil {
 		return nil, err
@@ -225,6 +339,24 @@ func (c *HTTPClient) SetAttemptTimeout(t time.Duration) {
 	}
 }
 
+// Use appends middlewares to the end of the chain
+func (c *HTTPClient) Use(middlewares ...Middleware) {
+	c.mtx.Lock()
+	for _, m := range middlewares {
+		if m != nil {
+			c.middlewares = append(c.middlewares, m)
+		}
+	}
+	c.mtx.Unlock()
+}
+
+// SetRetryPolicy replaces the retry policy, nil restores BackoffRetryPolicy with the attempts and attempt timeout of the client
+func (c *HTTPClient) SetRetryPolicy(p RetryPolicy) {
+	c.mtx.Lock()
+	c.retryPolicy = p
+	c.mtx.Unlock()
+}
+
 func (c *HTTPClient) Close() error {
 	if c.isClose {
 		return internalErrors.ErrorLog("Transport.HttpClient.PostDecodeJSON()", "client is already closed")
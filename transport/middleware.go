@@ -0,0 +1,73 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"go-vk-sdk/logger"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// RoundTrip sends a single attempt of the HTTP request
+type RoundTrip func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps RoundTrip of the client.
+//
+//	Middlewares are called in the order they were added, the first one is the outermost.
+//	Every attempt of a retried request passes through the whole chain.
+type Middleware func(next RoundTrip) RoundTrip
+
+// CallInfo describes the VK api call which the HTTP request belongs to
+type CallInfo struct {
+	Method     string     // api method name, for example users.get. Empty for upload and long poll requests
+	Parameters url.Values // copy of the request parameters
+
+	// Wait is called by HTTPClient before every repeated attempt of the request,
+	// the request layer passes the rate limiter of the access token, so retries do not exceed the limit
+	Wait func(ctx context.Context) error
+}
+
+type callInfoKey struct{}
+
+// WithCallInfo returns the context carrying the call info, it is available to middlewares through the request context
+func WithCallInfo(ctx context.Context, info *CallInfo) context.Context {
+	return context.WithValue(ctx, callInfoKey{}, info)
+}
+
+// CallInfoFromContext returns the call info attached by the request layer
+func CallInfoFromContext(ctx context.Context) (*CallInfo, bool) {
+	info, ok := ctx.Value(callInfoKey{}).(*CallInfo)
+	return info, ok && info != nil
+}
+
+// LogMiddleware logs every attempt with the api method, status code and duration
+func LogMiddleware() Middleware {
+	return func(next RoundTrip) RoundTrip {
+		return func(req *http.Request) (*http.Response, error) {
+			method := req.URL.Path
+			if info, ok := CallInfoFromContext(req.Context()); ok && info.Method != "" {
+				method = info.Method
+			}
+
+			start := time.Now()
+			resp, err := next(req)
+			if err != nil {
+				logger.Log("Transport.LogMiddleware()", fmt.Sprintf("%s %s failed in %s: %s", req.Method, method, time.Since(start), err.Error()))
+				return resp, err
+			}
+
+			logger.Log("Transport.LogMiddleware()", fmt.Sprintf("%s %s %d in %s", req.Method, method, resp.StatusCode, time.Since(start)))
+
+			return resp, nil
+		}
+	}
+}
+
+func chainMiddlewares(roundTrip RoundTrip, middlewares []Middleware) RoundTrip {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		roundTrip = middlewares[i](roundTrip)
+	}
+
+	return roundTrip
+}
@@ -0,0 +1,131 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"go-vk-sdk/logger"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMiddlewareOrderAndRetries(t *testing.T) {
+	server, attempts := failingServer(t)
+
+	var (
+		mtx   sync.Mutex
+		order []string
+		infos []*CallInfo
+	)
+
+	mark := func(name string) Middleware {
+		return func(next RoundTrip) RoundTrip {
+			return func(req *http.Request) (*http.Response, error) {
+				mtx.Lock()
+				order = append(order, name+">")
+				if info, ok := CallInfoFromContext(req.Context()); ok && name == "first" {
+					infos = append(infos, info)
+				}
+				mtx.Unlock()
+
+				resp, err := next(req)
+
+				mtx.Lock()
+				order = append(order, "<"+name)
+				mtx.Unlock()
+
+				return resp, err
+			}
+		}
+	}
+
+	client := newRetryClient()
+	if !Use(client, mark("first")) {
+		t.Fatal("HTTPClient does not support middlewares")
+	}
+	client.Use(mark("second"))
+
+	info := &CallInfo{Method: "users.get", Parameters: url.Values{"user_ids": {"1"}}}
+	ctx := WithCallInfo(context.Background(), info)
+
+	resp, err := client.Post(ctx, server.URL, strings.NewReader("user_ids=1"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if len(attempts()) != 2 {
+		t.Fatalf("got %d attempts, want 2", len(attempts()))
+	}
+
+	// the first middleware is the outermost and every attempt passes through the whole chain
+	want := "first> second> <second <first first> second> <second <first"
+	if got := strings.Join(order, " "); got != want {
+		t.Errorf("got order %q, want %q", got, want)
+	}
+
+	if len(infos) != 2 {
+		t.Fatalf("the call info is seen by %d attempts, want 2", len(infos))
+	}
+
+	for _, got := range infos {
+		if got.Method != "users.get" || got.Parameters.Get("user_ids") != "1" {
+			t.Errorf("unexpected call info %+v", got)
+		}
+	}
+}
+
+func TestMiddlewareWithoutCallInfo(t *testing.T) {
+	server, _ := failingServer(t)
+
+	seen := true
+	client := newRetryClient()
+	client.Use(func(next RoundTrip) RoundTrip {
+		return func(req *http.Request) (*http.Response, error) {
+			_, seen = CallInfoFromContext(req.Context())
+			return next(req)
+		}
+	})
+
+	resp, err := client.Get(context.Background(), server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if seen {
+		t.Error("the call info is reported for the request without it")
+	}
+}
+
+func TestLogMiddleware(t *testing.T) {
+	var out bytes.Buffer
+	logger.SetOutput(&out)
+	logger.Enable()
+	t.Cleanup(func() {
+		logger.Disable()
+		logger.SetOutput(os.Stdout)
+	})
+
+	server, _ := failingServer(t)
+
+	client := newRetryClient()
+	client.Use(LogMiddleware())
+
+	ctx := WithCallInfo(context.Background(), &CallInfo{Method: "users.get"})
+	resp, err := client.Get(ctx, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	log := out.String()
+	for _, want := range []string{"GET users.get 500 in", "GET users.get 200 in"} {
+		if !strings.Contains(log, want) {
+			t.Errorf("log %q does not contain %q", log, want)
+		}
+	}
+}
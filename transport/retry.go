@@ -0,0 +1,148 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	internalErrors "go-vk-sdk/errors"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy decides whether a failed attempt of the HTTP request is repeated and how long to wait before it.
+//
+//	Next is called after every failed attempt, attempt starts from 1 and elapsed is the time passed since the first attempt.
+//	resp is the last HTTP response (may be nil on network errors, its body is already closed),
+//	err is the network error or *errors.APIError when VK returned an error in the response envelope.
+type RetryPolicy interface {
+	Next(attempt int, elapsed time.Duration, resp *http.Response, err error) (time.Duration, bool)
+}
+
+// BackoffRetryPolicy Exponential backoff with jitter
+//
+//	The delay before attempt n is InitialInterval * Multiplier^(n-1), limited by MaxInterval
+//	and randomized by ±Jitter fraction of itself. The Retry-After header of the response has priority over the computed delay.
+//	Requests are repeated on network errors, 429 and 5xx status codes and on retryable VK errors (6, 10, 29),
+//	see errors.ErrorType.IsRetryable. Flood control (9) is not repeated, see errors.ErrorType.IsTransient
+type BackoffRetryPolicy struct {
+	MaxAttempts     int           // total number of attempts, <= 0 is unlimited (bounded by MaxElapsedTime)
+	InitialInterval time.Duration // delay before the first retry
+	MaxInterval     time.Duration // upper bound of a single delay, 0 is unbounded
+	Multiplier      float64       // growth factor of the delay, values < 1 are treated as 1
+	Jitter          float64       // randomization factor in [0, 1]
+	MaxElapsedTime  time.Duration // no retry is started after this time since the first attempt, 0 is unbounded
+}
+
+func NewBackoffRetryPolicy(attempts int, initialInterval time.Duration) *BackoffRetryPolicy {
+	return &BackoffRetryPolicy{
+		MaxAttempts:     attempts,
+		InitialInterval: initialInterval,
+		MaxInterval:     RetryMaxInterval,
+		Multiplier:      RetryMultiplier,
+		Jitter:          RetryJitter,
+		MaxElapsedTime:  RetryMaxElapsedTime,
+	}
+}
+
+func (p *BackoffRetryPolicy) Next(attempt int, elapsed time.Duration, resp *http.Response, err error) (time.Duration, bool) {
+	if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+		return 0, false
+	}
+
+	if !IsRetryable(resp, err) {
+		return 0, false
+	}
+
+	delay := p.backoff(attempt)
+	if after, ok := RetryAfter(resp); ok {
+		delay = after
+	}
+
+	if p.MaxElapsedTime > 0 && elapsed+delay > p.MaxElapsedTime {
+		return 0, false
+	}
+
+	return delay, true
+}
+
+func (p *BackoffRetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.InitialInterval) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxInterval > 0 && delay > float64(p.MaxInterval) {
+		delay = float64(p.MaxInterval)
+	}
+
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		delay += delay * jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(delay)
+}
+
+// IsRetryable reports whether the result of the attempt is a transient failure
+func IsRetryable(resp *http.Response, err error) bool {
+	var apiError *internalErrors.APIError
+	if errors.As(err, &apiError) {
+		return internalErrors.ErrorType(apiError.Code).IsRetryable()
+	}
+
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	if resp != nil {
+		return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+	}
+
+	return false
+}
+
+// RetryAfter parses the Retry-After header of the response, both delay-seconds and HTTP-date forms are supported
+func RetryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
+
+// sleepContext waits for the given duration or until the context is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,158 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	internalErrors "go-vk-sdk/errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBackoffGrowthAndCap(t *testing.T) {
+	p := &BackoffRetryPolicy{InitialInterval: 100 * time.Millisecond, MaxInterval: time.Second, Multiplier: 2}
+
+	want := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}
+
+	for i, delay := range want {
+		if got := p.backoff(i + 1); got != delay {
+			t.Errorf("attempt %d: got delay %v, want %v", i+1, got, delay)
+		}
+	}
+
+	// the multiplier below 1 keeps the delay constant
+	p.Multiplier = 0.5
+	if got := p.backoff(4); got != 100*time.Millisecond {
+		t.Errorf("got delay %v with the multiplier below 1", got)
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	p := &BackoffRetryPolicy{InitialInterval: time.Second, Multiplier: 1, Jitter: 0.25}
+
+	low, high := time.Second, time.Second
+	for i := 0; i < 1000; i++ {
+		delay := p.backoff(1)
+		if delay < 750*time.Millisecond || delay > 1250*time.Millisecond {
+			t.Fatalf("delay %v is out of the jitter bounds", delay)
+		}
+
+		low, high = min(low, delay), max(high, delay)
+	}
+
+	if low == high {
+		t.Error("the delay is not randomized")
+	}
+
+	// the jitter above 1 is limited to 1, the delay never becomes negative
+	p.Jitter = 5
+	for i := 0; i < 1000; i++ {
+		if delay := p.backoff(1); delay < 0 || delay > 2*time.Second {
+			t.Fatalf("delay %v is out of the jitter bounds", delay)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	header := func(value string) *http.Response {
+		return &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {value}}}
+	}
+
+	if delay, ok := RetryAfter(header("3")); !ok || delay != 3*time.Second {
+		t.Errorf("got %v %t for the seconds", delay, ok)
+	}
+
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if delay, ok := RetryAfter(header(date)); !ok || delay <= 58*time.Second || delay > time.Minute {
+		t.Errorf("got %v %t for the date", delay, ok)
+	}
+
+	past := time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat)
+	if delay, ok := RetryAfter(header(past)); !ok || delay != 0 {
+		t.Errorf("got %v %t for the past date", delay, ok)
+	}
+
+	for _, value := range []string{"", "-1", "soon"} {
+		if _, ok := RetryAfter(header(value)); ok {
+			t.Errorf("Retry-After %q is accepted", value)
+		}
+	}
+
+	if _, ok := RetryAfter(nil); ok {
+		t.Error("Retry-After of the nil response is accepted")
+	}
+
+	// Retry-After has priority over the computed delay
+	p := &BackoffRetryPolicy{InitialInterval: time.Millisecond, Multiplier: 1}
+	if delay, ok := p.Next(1, 0, header("2"), errors.New("unexpected status code 429")); !ok || delay != 2*time.Second {
+		t.Errorf("got %v %t, want the delay of Retry-After", delay, ok)
+	}
+}
+
+func TestBackoffRetryPolicyNext(t *testing.T) {
+	p := &BackoffRetryPolicy{MaxAttempts: 3, InitialInterval: time.Second, Multiplier: 1, MaxElapsedTime: 10 * time.Second}
+	network := errors.New("connection reset")
+
+	if _, ok := p.Next(1, 0, nil, network); !ok {
+		t.Error("the network error is not retried")
+	}
+
+	if _, ok := p.Next(3, 0, nil, network); ok {
+		t.Error("the request is retried after MaxAttempts")
+	}
+
+	// the retry which would end after MaxElapsedTime is not started
+	if _, ok := p.Next(1, 9500*time.Millisecond, nil, network); ok {
+		t.Error("the request is retried after MaxElapsedTime")
+	}
+
+	if delay, ok := p.Next(1, 9*time.Second, nil, network); !ok || delay != time.Second {
+		t.Errorf("got %v %t within MaxElapsedTime", delay, ok)
+	}
+
+	for code, retry := range map[int]bool{6: true, 9: false, 10: true, 15: false, 29: true} {
+		err := &internalErrors.APIError{Code: code}
+		if _, ok := p.Next(1, 0, &http.Response{StatusCode: http.StatusOK}, err); ok != retry {
+			t.Errorf("VK error %d: got retry %t, want %t", code, ok, retry)
+		}
+	}
+
+	for status, retry := range map[int]bool{http.StatusTooManyRequests: true, http.StatusBadGateway: true, http.StatusNotFound: false} {
+		if _, ok := p.Next(1, 0, &http.Response{StatusCode: status}, nil); ok != retry {
+			t.Errorf("status %d: got retry %t, want %t", status, ok, retry)
+		}
+	}
+
+	if _, ok := p.Next(1, 0, nil, context.Canceled); ok {
+		t.Error("the canceled request is retried")
+	}
+}
+
+func TestSleepContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	start := time.Now()
+	if err := sleepContext(ctx, time.Minute); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("the sleep is not interrupted, it took %v", elapsed)
+	}
+
+	if err := sleepContext(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("got error %v", err)
+	}
+
+	if err := sleepContext(ctx, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v for the done context", err)
+	}
+}
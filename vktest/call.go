@@ -0,0 +1,103 @@
+package vktest
+
+import (
+	"go-vk-sdk/errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type CallKind string
+
+const (
+	CallKindMethod   CallKind = "method"
+	CallKindOAuth    CallKind = "oauth"
+	CallKindLongPoll CallKind = "long_poll"
+	CallKindUpload   CallKind = "upload"
+)
+
+// HandlerFunc returns the response of the scripted call.
+//
+//	For api methods the value is written as the "response" field of the envelope, *errors.APIError as the "error" field
+//	and any other error as the error 1. For the other kinds the value is written as the whole body.
+type HandlerFunc func(call *Call) (interface{}, error)
+
+// ExecuteResult Response of the execute method with errors of the inner calls, returned by the handler of execute
+type ExecuteResult struct {
+	Response interface{}
+	Errors   []errors.ExecuteAPIError
+}
+
+// Call Request received by the server
+type Call struct {
+	Kind       CallKind
+	Name       string // api method, OAuth method, upload name or long poll kind (group, user)
+	HTTPMethod string
+	Parameters url.Values // query and form parameters
+	Header     http.Header
+	Files      []*File // files of multipart requests
+}
+
+// File uploaded by a multipart request
+type File struct {
+	Field string
+	Name  string
+	Data  []byte
+}
+
+// Get returns the first value of the parameter
+func (c *Call) Get(key string) string {
+	return c.Parameters.Get(key)
+}
+
+func (c *Call) Has(key string) bool {
+	return c.Parameters.Has(key)
+}
+
+// AccessToken returns the token of the access_token parameter or the Authorization header
+func (c *Call) AccessToken() string {
+	if token := c.Parameters.Get("access_token"); token != "" {
+		return token
+	}
+
+	return strings.TrimPrefix(c.Header.Get("Authorization"), "Bearer ")
+}
+
+// File returns the uploaded file of the form field or nil
+func (c *Call) File(field string) *File {
+	for _, file := range c.Files {
+		if file.Field == field {
+			return file
+		}
+	}
+
+	return nil
+}
+
+func (c *Call) parseMultipart(body io.Reader, boundary string) error {
+	reader := multipart.NewReader(body, boundary)
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return err
+		}
+
+		if part.FileName() == "" {
+			c.Parameters.Add(part.FormName(), string(data))
+			continue
+		}
+
+		c.Files = append(c.Files, &File{Field: part.FormName(), Name: part.FileName(), Data: data})
+	}
+}
@@ -0,0 +1,193 @@
+package vktest
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-vk-sdk/api"
+	"go-vk-sdk/events"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// longPoll Event queue of the Long Poll server, the number of the first event is 1
+type longPoll struct {
+	mtx     sync.Mutex
+	kind    string
+	key     string
+	updates []json.RawMessage
+	failed  []int
+	notify  chan struct{} // closed and replaced when the queue changes
+}
+
+func newLongPoll(kind string) *longPoll {
+	return &longPoll{
+		kind:   kind,
+		key:    "vktest-" + kind,
+		notify: make(chan struct{}),
+	}
+}
+
+// session returns the key and ts for the next event
+func (l *longPoll) session() (string, int) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return l.key, len(l.updates) + 1
+}
+
+func (l *longPoll) push(update json.RawMessage) {
+	l.mtx.Lock()
+	l.updates = append(l.updates, update)
+	l.wake()
+	l.mtx.Unlock()
+}
+
+func (l *longPoll) fail(failed int) {
+	l.mtx.Lock()
+	l.failed = append(l.failed, failed)
+	l.wake()
+	l.mtx.Unlock()
+}
+
+func (l *longPoll) reset() {
+	l.mtx.Lock()
+	l.updates = nil
+	l.failed = nil
+	l.wake()
+	l.mtx.Unlock()
+}
+
+// wake must be called with the lock held
+func (l *longPoll) wake() {
+	close(l.notify)
+	l.notify = make(chan struct{})
+}
+
+// check returns the events starting from ts, the next ts and the injected failure
+func (l *longPoll) check(key string, ts int) ([]json.RawMessage, int, int, <-chan struct{}) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	next := len(l.updates) + 1
+
+	if len(l.failed) > 0 {
+		failed := l.failed[0]
+		l.failed = l.failed[1:]
+		return nil, next, failed, nil
+	}
+
+	if key != l.key {
+		return nil, next, 2, nil
+	}
+
+	if ts < 1 || ts > next {
+		return nil, next, 1, nil
+	}
+
+	return l.updates[ts-1:], next, 0, l.notify
+}
+
+// PushGroupEvent adds the event to the group Long Poll queue, object is encoded to JSON
+func (s *Server) PushGroupEvent(eventType events.EventType, object interface{}) error {
+	data, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+
+	_, ts := s.groupLP.session()
+
+	update, err := json.Marshal(events.EventUpdate{
+		Type:       eventType,
+		EventID:    fmt.Sprintf("vktest-%d", ts),
+		VersionAPI: api.Version,
+		Object:     data,
+		GroupID:    s.GroupID,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.groupLP.push(update)
+
+	return nil
+}
+
+// PushUserEvent adds the event to the user Long Poll queue, the update is an array starting with the event code,
+// for example 4, message_id, flags, peer_id, timestamp, text
+func (s *Server) PushUserEvent(update ...interface{}) error {
+	data, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+
+	s.userLP.push(data)
+
+	return nil
+}
+
+// FailGroupLongPoll queues the "failed" response of the group Long Poll server (1, 2 or 3)
+func (s *Server) FailGroupLongPoll(failed int) {
+	s.groupLP.fail(failed)
+}
+
+// FailUserLongPoll queues the "failed" response of the user Long Poll server (1, 2, 3 or 4)
+func (s *Server) FailUserLongPoll(failed int) {
+	s.userLP.fail(failed)
+}
+
+func (s *Server) serveGroupLongPoll(w http.ResponseWriter, r *http.Request) {
+	s.serveLongPoll(w, r, s.groupLP, func(ts int) interface{} {
+		return strconv.Itoa(ts)
+	})
+}
+
+func (s *Server) serveUserLongPoll(w http.ResponseWriter, r *http.Request) {
+	s.serveLongPoll(w, r, s.userLP, func(ts int) interface{} {
+		return ts
+	})
+}
+
+// serveLongPoll answers a_check at once if there are events, otherwise waits for them up to the wait parameter
+func (s *Server) serveLongPoll(w http.ResponseWriter, r *http.Request, lp *longPoll, formatTs func(int) interface{}) {
+	call, err := s.record(CallKindLongPoll, lp.kind, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if call.Get("act") != "a_check" {
+		http.Error(w, "unknown act "+call.Get("act"), http.StatusBadRequest)
+		return
+	}
+
+	ts, _ := strconv.Atoi(call.Get("ts"))
+	wait, _ := strconv.Atoi(call.Get("wait"))
+
+	timer := time.NewTimer(time.Duration(wait) * time.Second)
+	defer timer.Stop()
+
+	for {
+		updates, next, failed, notify := lp.check(call.Get("key"), ts)
+		if failed != 0 {
+			writeJSON(w, http.StatusOK, map[string]interface{}{"failed": failed, "ts": formatTs(next)})
+			return
+		}
+
+		if len(updates) > 0 {
+			writeJSON(w, http.StatusOK, map[string]interface{}{"ts": formatTs(next), "updates": updates})
+			return
+		}
+
+		select {
+		case <-notify:
+			continue
+		case <-timer.C:
+		case <-r.Context().Done():
+		case <-s.done:
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{"ts": formatTs(next), "updates": []json.RawMessage{}})
+		return
+	}
+}
@@ -0,0 +1,473 @@
+package vktest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go-vk-sdk/api"
+	"go-vk-sdk/errors"
+	"go-vk-sdk/transport"
+	"image"
+	"image/png"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Server In-process fake of the VK api for tests.
+//
+//	It speaks the /method/<name> protocol, the OAuth endpoints (/oauth/token, /oauth/access_token, /oauth/oauth2/auth),
+//	group and user Long Poll a_check (/longpoll/group, /longpoll/user) and upload URLs (/upload/<name>).
+//	Responses are scripted per method: one-shot responses queued by Respond and Fail are served first in order,
+//	then the handler registered by Handle. Methods without a script fail with the error 3 (unknown method),
+//	except for groups.getLongPollServer, messages.getLongPollServer and groups.setLongPollSettings which serve
+//	the built-in Long Poll servers. Every received request is recorded and available through Calls.
+type Server struct {
+	*httptest.Server
+	GroupID int // group_id of the pushed group Long Poll events
+
+	mtx      sync.Mutex
+	handlers map[string]HandlerFunc
+	queues   map[string][]HandlerFunc
+	calls    []*Call
+	token    string
+	groupLP  *longPoll
+	userLP   *longPoll
+	done     chan struct{}
+	isClose  bool
+}
+
+// NewServer starts the server, it must be closed by Close
+func NewServer() *Server {
+	s := &Server{
+		GroupID:  1,
+		handlers: make(map[string]HandlerFunc),
+		queues:   make(map[string][]HandlerFunc),
+		groupLP:  newLongPoll("group"),
+		userLP:   newLongPoll("user"),
+		done:     make(chan struct{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/method/", s.serveMethod)
+	mux.HandleFunc("/oauth/", s.serveOAuth)
+	mux.HandleFunc("/longpoll/group", s.serveGroupLongPoll)
+	mux.HandleFunc("/longpoll/user", s.serveUserLongPoll)
+	mux.HandleFunc("/upload/", s.serveUpload)
+	mux.HandleFunc("/captcha.php", s.serveCaptcha)
+
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// Close interrupts the waiting Long Poll requests and shuts the server down
+func (s *Server) Close() {
+	s.mtx.Lock()
+	if s.isClose {
+		s.mtx.Unlock()
+		return
+	}
+	s.isClose = true
+	close(s.done)
+	s.mtx.Unlock()
+
+	s.Server.Close()
+}
+
+// API returns the api configured to use the server.
+//
+//	The rate limiter is disabled and failed requests are not retried, so injected errors are returned to the caller at once.
+//	Replace the client to test retries.
+func (s *Server) API() *api.API {
+	a := api.NewAPI()
+	_ = a.SetHostMethodEndpoint(s.URL)
+	_ = a.SetHostAuthEndpoint(s.AuthEndpoint())
+	a.Client = transport.NewHTTPClientParameters(&transport.HTTPClientParameters{
+		RetryPolicy: transport.NewBackoffRetryPolicy(1, 0),
+	})
+
+	return a
+}
+
+// MethodEndpoint base URL of the api methods
+func (s *Server) MethodEndpoint() string {
+	return s.URL + "/method/"
+}
+
+// AuthEndpoint base URL of the OAuth methods
+func (s *Server) AuthEndpoint() string {
+	return s.URL + "/oauth/"
+}
+
+// UploadURL returns the upload URL for the scripted responses of the get upload server methods
+func (s *Server) UploadURL(name string) string {
+	return s.URL + "/upload/" + name
+}
+
+func (s *Server) GroupLongPollURL() string {
+	return s.URL + "/longpoll/group"
+}
+
+func (s *Server) UserLongPollURL() string {
+	return s.URL + "/longpoll/user"
+}
+
+// SetAccessToken method calls with another access token fail with the error 5, empty token disables the check
+func (s *Server) SetAccessToken(token string) {
+	s.mtx.Lock()
+	s.token = token
+	s.mtx.Unlock()
+}
+
+// Handle registers the handler of the api method which is called when no one-shot response is queued
+func (s *Server) Handle(method string, h HandlerFunc) {
+	s.handle(CallKindMethod, method, h)
+}
+
+// Respond queues a successful one-shot response, the value is written as the "response" field of the envelope
+func (s *Server) Respond(method string, response interface{}) {
+	s.enqueue(CallKindMethod, method, func(*Call) (interface{}, error) {
+		return response, nil
+	})
+}
+
+// Fail queues a one-shot VK error
+func (s *Server) Fail(method string, err *errors.APIError) {
+	s.enqueue(CallKindMethod, method, func(*Call) (interface{}, error) {
+		return nil, err
+	})
+}
+
+// FailCode queues a one-shot VK error with the code
+func (s *Server) FailCode(method string, code errors.ErrorType) {
+	s.Fail(method, &errors.APIError{Code: int(code), Message: errorMessage(code)})
+}
+
+// FailCaptcha queues a one-shot error 14, captcha_img points to the captcha image served by the server
+func (s *Server) FailCaptcha(method string, sid string) {
+	s.Fail(method, &errors.APIError{
+		Code:       int(errors.CaptchaCode),
+		Message:    errorMessage(errors.CaptchaCode),
+		CaptchaSID: sid,
+		CaptchaImg: s.URL + "/captcha.php?sid=" + url.QueryEscape(sid),
+	})
+}
+
+// FailFlood queues a one-shot error 9
+func (s *Server) FailFlood(method string) {
+	s.FailCode(method, errors.FloodCode)
+}
+
+// FailAuth queues a one-shot error 5
+func (s *Server) FailAuth(method string) {
+	s.FailCode(method, errors.AuthCode)
+}
+
+// HandleOAuth registers the handler of the OAuth method (token, access_token, oauth2/auth).
+//
+//	The returned value is written as the whole body, *errors.AuthDirectError and *errors.AuthCodeFlowError are written with the status 401
+func (s *Server) HandleOAuth(name string, h HandlerFunc) {
+	s.handle(CallKindOAuth, name, h)
+}
+
+// RespondOAuth queues a one-shot body of the OAuth method
+func (s *Server) RespondOAuth(name string, body interface{}) {
+	s.enqueue(CallKindOAuth, name, func(*Call) (interface{}, error) {
+		return body, nil
+	})
+}
+
+// FailOAuth queues a one-shot OAuth error, err is *errors.AuthDirectError or *errors.AuthCodeFlowError
+func (s *Server) FailOAuth(name string, err error) {
+	s.enqueue(CallKindOAuth, name, func(*Call) (interface{}, error) {
+		return nil, err
+	})
+}
+
+// HandleUpload registers the handler of the upload URL, the returned value is written as the whole body
+// and *errors.UploadAPIError as the upload error
+func (s *Server) HandleUpload(name string, h HandlerFunc) {
+	s.handle(CallKindUpload, name, h)
+}
+
+// RespondUpload queues a one-shot body of the upload URL
+func (s *Server) RespondUpload(name string, body interface{}) {
+	s.enqueue(CallKindUpload, name, func(*Call) (interface{}, error) {
+		return body, nil
+	})
+}
+
+// FailUpload queues a one-shot upload error
+func (s *Server) FailUpload(name string, err *errors.UploadAPIError) {
+	s.enqueue(CallKindUpload, name, func(*Call) (interface{}, error) {
+		return nil, err
+	})
+}
+
+// Calls returns all received requests in order
+func (s *Server) Calls() []*Call {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return append([]*Call(nil), s.calls...)
+}
+
+// CallsOf returns the received requests with the name: api method, OAuth method, upload name or long poll kind (group, user)
+func (s *Server) CallsOf(name string) []*Call {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var calls []*Call
+	for _, call := range s.calls {
+		if call.Name == name {
+			calls = append(calls, call)
+		}
+	}
+
+	return calls
+}
+
+// LastCall returns the last received request with the name or nil
+func (s *Server) LastCall(name string) *Call {
+	calls := s.CallsOf(name)
+	if len(calls) == 0 {
+		return nil
+	}
+
+	return calls[len(calls)-1]
+}
+
+// Reset removes the recorded calls, the scripted responses and the pending Long Poll events
+func (s *Server) Reset() {
+	s.mtx.Lock()
+	s.calls = nil
+	clear(s.handlers)
+	clear(s.queues)
+	s.mtx.Unlock()
+
+	s.groupLP.reset()
+	s.userLP.reset()
+}
+
+func (s *Server) handle(kind CallKind, name string, h HandlerFunc) {
+	s.mtx.Lock()
+	if h == nil {
+		delete(s.handlers, scriptKey(kind, name))
+	} else {
+		s.handlers[scriptKey(kind, name)] = h
+	}
+	s.mtx.Unlock()
+}
+
+func (s *Server) enqueue(kind CallKind, name string, h HandlerFunc) {
+	key := scriptKey(kind, name)
+
+	s.mtx.Lock()
+	s.queues[key] = append(s.queues[key], h)
+	s.mtx.Unlock()
+}
+
+// script returns the next one-shot response or the handler, nil if the call is not scripted
+func (s *Server) script(call *Call) HandlerFunc {
+	key := scriptKey(call.Kind, call.Name)
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if queue := s.queues[key]; len(queue) > 0 {
+		s.queues[key] = queue[1:]
+		return queue[0]
+	}
+
+	return s.handlers[key]
+}
+
+func scriptKey(kind CallKind, name string) string {
+	return string(kind) + ":" + name
+}
+
+// record parses and stores the request
+func (s *Server) record(kind CallKind, name string, r *http.Request) (*Call, error) {
+	call := &Call{
+		Kind:       kind,
+		Name:       name,
+		HTTPMethod: r.Method,
+		Parameters: r.URL.Query(),
+		Header:     r.Header.Clone(),
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if strings.HasPrefix(mediaType, "multipart/") {
+		if err := call.parseMultipart(r.Body, params["boundary"]); err != nil {
+			return nil, err
+		}
+	} else if r.Body != nil {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		values, err := url.ParseQuery(string(data))
+		if err != nil {
+			return nil, err
+		}
+
+		for key, value := range values {
+			call.Parameters[key] = append(call.Parameters[key], value...)
+		}
+	}
+
+	s.mtx.Lock()
+	s.calls = append(s.calls, call)
+	s.mtx.Unlock()
+
+	return call, nil
+}
+
+func (s *Server) serveMethod(w http.ResponseWriter, r *http.Request) {
+	call, err := s.record(CallKindMethod, strings.TrimPrefix(r.URL.Path, "/method/"), r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mtx.Lock()
+	token := s.token
+	s.mtx.Unlock()
+
+	var response interface{}
+	if token != "" && call.AccessToken() != token {
+		err = &errors.APIError{Code: int(errors.AuthCode), Message: errorMessage(errors.AuthCode)}
+	} else if h := s.script(call); h != nil {
+		response, err = h(call)
+	} else {
+		response, err = s.builtin(call)
+	}
+
+	if err != nil {
+		apiError, ok := err.(*errors.APIError)
+		if !ok {
+			apiError = &errors.APIError{Code: int(errors.UnknownCode), Message: err.Error()}
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{"error": apiError})
+		return
+	}
+
+	if result, ok := response.(*ExecuteResult); ok {
+		body := map[string]interface{}{"response": result.Response}
+		if len(result.Errors) > 0 {
+			body["execute_errors"] = result.Errors
+		}
+
+		writeJSON(w, http.StatusOK, body)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"response": response})
+}
+
+// builtin serves the methods which are not scripted
+func (s *Server) builtin(call *Call) (interface{}, error) {
+	switch call.Name {
+	case "groups.getLongPollServer":
+		key, ts := s.groupLP.session()
+		return map[string]interface{}{"key": key, "server": s.GroupLongPollURL(), "ts": fmt.Sprint(ts)}, nil
+	case "messages.getLongPollServer":
+		key, ts := s.userLP.session()
+		return map[string]interface{}{"key": key, "server": s.UserLongPollURL(), "ts": ts, "pts": ts}, nil
+	case "groups.setLongPollSettings":
+		return 1, nil
+	}
+
+	return nil, &errors.APIError{Code: int(errors.MethodCode), Message: errorMessage(errors.MethodCode)}
+}
+
+func (s *Server) serveOAuth(w http.ResponseWriter, r *http.Request) {
+	call, err := s.record(CallKindOAuth, strings.TrimPrefix(r.URL.Path, "/oauth/"), r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h := s.script(call)
+	if h == nil {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"access_token": "vktest", "expires_in": 0, "user_id": 1})
+		return
+	}
+
+	body, err := h(call)
+	if err != nil {
+		writeJSON(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, body)
+}
+
+func (s *Server) serveUpload(w http.ResponseWriter, r *http.Request) {
+	call, err := s.record(CallKindUpload, strings.TrimPrefix(r.URL.Path, "/upload/"), r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h := s.script(call)
+	if h == nil {
+		body := map[string]interface{}{"server": 1, "hash": "vktest"}
+		for _, file := range call.Files {
+			body[file.Field] = file.Name
+		}
+
+		writeJSON(w, http.StatusOK, body)
+		return
+	}
+
+	body, err := h(call)
+	if err != nil {
+		writeJSON(w, http.StatusOK, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, body)
+}
+
+func (s *Server) serveCaptcha(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "image/png")
+	_ = png.Encode(w, image.NewGray(image.Rect(0, 0, 1, 1)))
+}
+
+func errorMessage(code errors.ErrorType) string {
+	switch code {
+	case errors.AuthCode:
+		return "User authorization failed"
+	case errors.MethodCode:
+		return "Unknown method passed"
+	case errors.TooManyRequestCode:
+		return "Too many requests per second"
+	case errors.FloodCode:
+		return "Flood control"
+	case errors.InternalServerCode:
+		return "Internal server error"
+	case errors.CaptchaCode:
+		return "Captcha needed"
+	default:
+		return fmt.Sprintf("Error with code %d", code)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = io.Copy(w, bytes.NewReader(data))
+}
@@ -0,0 +1,241 @@
+package vktest_test
+
+import (
+	"context"
+	"errors"
+	"go-vk-sdk/actor"
+	"go-vk-sdk/api"
+	internalErrors "go-vk-sdk/errors"
+	"go-vk-sdk/events"
+	"go-vk-sdk/longPollGroup"
+	"go-vk-sdk/request"
+	"go-vk-sdk/transport"
+	"go-vk-sdk/vktest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var users = []map[string]interface{}{{"id": 1, "first_name": "Павел", "last_name": "Дуров"}}
+
+func newServer(t *testing.T) (*vktest.Server, *api.API, actor.Actor) {
+	t.Helper()
+
+	s := vktest.NewServer()
+	t.Cleanup(s.Close)
+
+	return s, s.API(), &actor.Group{ID: 1, AccessToken: "token"}
+}
+
+func apiErrorCode(t *testing.T, err error) int {
+	t.Helper()
+
+	var apiError *internalErrors.APIError
+	if !errors.As(err, &apiError) {
+		t.Fatalf("got error %v, want *errors.APIError", err)
+	}
+
+	return apiError.Code
+}
+
+func TestCaptchaIsSolved(t *testing.T) {
+	s, a, g := newServer(t)
+
+	var solved []string
+	a.CaptchaHandler = api.CaptchaHandlerFunc(func(ctx context.Context, sid, img string) (string, error) {
+		solved = append(solved, sid+" "+img)
+		return "key", nil
+	})
+
+	s.FailCaptcha("users.get", "sid1")
+	s.Respond("users.get", users)
+
+	resp, err := request.NewUsersGetRequest(a, g).Exec(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Response) != 1 || resp.Response[0].FirstName != "Павел" {
+		t.Errorf("unexpected response %+v", resp.Response)
+	}
+
+	if len(solved) != 1 || solved[0] != "sid1 "+s.URL+"/captcha.php?sid=sid1" {
+		t.Errorf("unexpected solved captchas %q", solved)
+	}
+
+	calls := s.CallsOf("users.get")
+	if len(calls) != 2 {
+		t.Fatalf("got %d calls, want 2", len(calls))
+	}
+
+	if calls[0].Has("captcha_sid") {
+		t.Error("the first call has captcha_sid")
+	}
+
+	if calls[1].Get("captcha_sid") != "sid1" || calls[1].Get("captcha_key") != "key" {
+		t.Errorf("the repeated call has captcha_sid=%q captcha_key=%q", calls[1].Get("captcha_sid"), calls[1].Get("captcha_key"))
+	}
+}
+
+func TestCaptchaWithoutHandler(t *testing.T) {
+	s, a, g := newServer(t)
+
+	s.FailCaptcha("users.get", "sid1")
+
+	_, err := request.NewUsersGetRequest(a, g).Exec(context.Background())
+	if code := apiErrorCode(t, err); code != int(internalErrors.CaptchaCode) {
+		t.Errorf("got error %d, want 14", code)
+	}
+}
+
+func TestFloodIsNotRetried(t *testing.T) {
+	s, a, g := newServer(t)
+	a.Client = transport.NewHTTPClientParameters(&transport.HTTPClientParameters{
+		RetryPolicy: transport.NewBackoffRetryPolicy(3, time.Millisecond),
+	})
+
+	// the post may be applied before flood control, repeating it could publish it twice
+	s.FailFlood("wall.post")
+	s.Respond("wall.post", map[string]int{"post_id": 1})
+
+	_, err := request.NewWallPostRequest(a, g).Message("hello").Exec(context.Background())
+	if code := apiErrorCode(t, err); code != int(internalErrors.FloodCode) {
+		t.Errorf("got error %d, want 9", code)
+	}
+
+	if calls := len(s.CallsOf("wall.post")); calls != 1 {
+		t.Errorf("got %d calls, want 1", calls)
+	}
+}
+
+func TestTooManyRequestsIsRetried(t *testing.T) {
+	s, a, g := newServer(t)
+	a.Client = transport.NewHTTPClientParameters(&transport.HTTPClientParameters{
+		RetryPolicy: transport.NewBackoffRetryPolicy(3, time.Millisecond),
+	})
+
+	s.FailCode("users.get", internalErrors.TooManyRequestCode)
+	s.Respond("users.get", users)
+
+	resp, err := request.NewUsersGetRequest(a, g).Exec(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Response) != 1 {
+		t.Errorf("got %d users, want 1", len(resp.Response))
+	}
+
+	if calls := len(s.CallsOf("users.get")); calls != 2 {
+		t.Errorf("got %d calls, want 2", calls)
+	}
+}
+
+func TestAccessToken(t *testing.T) {
+	s, a, _ := newServer(t)
+
+	s.SetAccessToken("good")
+	s.Handle("users.get", func(*vktest.Call) (interface{}, error) {
+		return users, nil
+	})
+
+	_, err := request.NewUsersGetRequest(a, &actor.Group{ID: 1, AccessToken: "bad"}).Exec(context.Background())
+	if code := apiErrorCode(t, err); code != int(internalErrors.AuthCode) {
+		t.Errorf("got error %d, want 5", code)
+	}
+
+	good := &actor.Group{ID: 1, AccessToken: "good"}
+	if _, err = request.NewUsersGetRequest(a, good).Exec(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if token := s.LastCall("users.get").AccessToken(); token != "good" {
+		t.Errorf("got access token %q, want good", token)
+	}
+
+	s.FailAuth("users.get")
+
+	_, err = request.NewUsersGetRequest(a, good).Exec(context.Background())
+	if code := apiErrorCode(t, err); code != int(internalErrors.AuthCode) {
+		t.Errorf("got error %d, want 5", code)
+	}
+}
+
+func TestGroupLongPoll(t *testing.T) {
+	s, a, g := newServer(t)
+
+	lp := longPollGroup.NewLongPoll(a, g, 1)
+	if err := lp.SetWait(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := lp.UpdateServer(true); err != nil {
+		t.Fatal(err)
+	}
+
+	lp.TrackEvent(events.EventTypeMessageNew)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- lp.Run(ctx) }()
+
+	for i, text := range []string{"first", "second"} {
+		if i == 1 {
+			// the key is expired, the long poll requests a new one
+			s.FailGroupLongPoll(2)
+		}
+
+		err := s.PushGroupEvent(events.EventTypeMessageNew, map[string]interface{}{
+			"message": map[string]interface{}{"id": i + 1, "peer_id": 2, "from_id": 2, "text": text},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case update := <-lp.Updates():
+			event, ok := update.Event.(*events.EventMessageNew)
+			if !ok || event.Message.Text != text || update.GroupID != s.GroupID {
+				t.Fatalf("unexpected update %+v", update)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("update %q is not received", text)
+		}
+	}
+
+	// every event is delivered once
+	select {
+	case update := <-lp.Updates():
+		t.Fatalf("repeated update %+v", update)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	_ = lp.Stop()
+	cancel()
+	<-done
+
+	if settings := s.LastCall("groups.setLongPollSettings"); settings == nil || settings.Get("message_new") != "1" {
+		t.Error("message_new is not enabled in the long poll settings")
+	}
+
+	if servers := len(s.CallsOf("groups.getLongPollServer")); servers != 2 {
+		t.Errorf("got %d groups.getLongPollServer calls, want 2", servers)
+	}
+
+	checks := s.CallsOf("group")
+	if len(checks) < 2 {
+		t.Fatalf("got %d a_check requests, want at least 2", len(checks))
+	}
+
+	for _, check := range checks {
+		if check.Get("act") != "a_check" || check.Get("key") == "" {
+			t.Errorf("unexpected long poll request %v", check.Parameters)
+		}
+
+		if ts, err := strconv.Atoi(check.Get("ts")); err != nil || ts < 1 {
+			t.Errorf("got ts %q", check.Get("ts"))
+		}
+	}
+}